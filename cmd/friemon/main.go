@@ -16,6 +16,8 @@ import (
 	"github.com/theoreotm/friemon/internal/application/commands"
 	"github.com/theoreotm/friemon/internal/application/components"
 	"github.com/theoreotm/friemon/internal/application/handlers"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
 	"github.com/theoreotm/friemon/internal/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -42,6 +44,8 @@ func main() {
 		os.Exit(1)
 	}
 
+	game.SetRarityWeights(cfg.Bot.RarityWeights)
+
 	// Initialize logging
 	if err := logger.Initialize(cfg.Log); err != nil {
 		fmt.Printf("Failed to initialize logger: %v\n", err)
@@ -86,7 +90,7 @@ func main() {
 	}
 
 	// Setup bot with event listeners
-	if err := b.SetupBot(handlers.OnMessage(b)); err != nil {
+	if err := b.SetupBot(handlers.OnMessage(b), handlers.OnReaction(b)); err != nil {
 		log.Fatal("Failed to setup bot", logger.ErrorField(err))
 	}
 
@@ -105,6 +109,47 @@ func main() {
 
 	b.Client.AddEventListeners(h)
 
+	// Register periodic tasks and start processing scheduled tasks
+	if err := services.SetupMatchmaking(b); err != nil {
+		log.Fatal("Failed to setup matchmaking", logger.ErrorField(err))
+	}
+
+	if err := services.SetupSeasons(b); err != nil {
+		log.Fatal("Failed to setup seasons", logger.ErrorField(err))
+	}
+
+	if err := services.SetupRatingDecay(b); err != nil {
+		log.Fatal("Failed to setup rating decay", logger.ErrorField(err))
+	}
+
+	if err := services.SetupBattles(b); err != nil {
+		log.Fatal("Failed to setup battles", logger.ErrorField(err))
+	}
+
+	if err := services.SetupTournaments(b); err != nil {
+		log.Fatal("Failed to setup tournaments", logger.ErrorField(err))
+	}
+
+	if err := services.SetupMarket(b); err != nil {
+		log.Fatal("Failed to setup market", logger.ErrorField(err))
+	}
+
+	if err := services.SetupAuctions(b); err != nil {
+		log.Fatal("Failed to setup auctions", logger.ErrorField(err))
+	}
+
+	if err := services.SetupSpawnEvents(b); err != nil {
+		log.Fatal("Failed to setup spawn events", logger.ErrorField(err))
+	}
+
+	if err := services.SetupXP(b); err != nil {
+		log.Fatal("Failed to setup XP", logger.ErrorField(err))
+	}
+
+	if err := b.Scheduler.Start(); err != nil {
+		log.Fatal("Failed to start scheduler", logger.ErrorField(err))
+	}
+
 	// Connect to Discord
 	if err := b.Client.OpenGateway(ctx); err != nil {
 		log.Fatal("Failed to connect to Discord", logger.ErrorField(err))
@@ -1,5 +1,7 @@
 package constants
 
+import "strings"
+
 type Personality int
 type Color int
 
@@ -103,6 +105,16 @@ func (p Personality) String() string {
 	}[p]
 }
 
+// ParsePersonality returns the personality with the given name.
+func ParsePersonality(name string) (Personality, bool) {
+	for _, p := range Personalities {
+		if strings.EqualFold(p.String(), name) {
+			return p, true
+		}
+	}
+	return PersonalityAloof, false
+}
+
 // Personalities is an array of all available personalities.
 var Personalities = []Personality{
 	PersonalityAloof,
@@ -137,6 +149,13 @@ const (
 	ColorInfo    int = 0x297bd1
 	ColorLoading int = 0x23272a
 	ColorDefault int = 0x2b2d31
+
+	// Rarity tiers
+	ColorCommon    int = 0x99aab5
+	ColorUncommon  int = 0x57f287
+	ColorRare      int = 0x3498db
+	ColorLegendary int = 0xf1c40f
+	ColorMythical  int = 0xe91e63
 )
 
 // StatusEffect represents possible status effects for a character.
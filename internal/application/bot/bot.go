@@ -31,6 +31,10 @@ type Bot struct {
 	Redis         *redis.Client
 	Scheduler     *scheduler.Scheduler
 	BattleManager *game.BattleManager
+	TradeManager  *game.TradeManager
+	RankedQueue   *memstore.RankedQueue
+	Leaderboard   *memstore.Leaderboard
+	RatingSystem  game.RatingSystem
 }
 
 func (b *Bot) GetRestClient() tasks.RestClient {
@@ -47,6 +51,7 @@ func New(cfg Config, buildInfo BuildInfo, ctx context.Context) *Bot {
 		BuildInfo:     buildInfo,
 		Context:       ctx,
 		BattleManager: game.NewBattleManager(),
+		TradeManager:  game.NewTradeManager(),
 	}
 }
 
@@ -57,6 +62,12 @@ type BuildInfo struct {
 }
 
 func (b *Bot) SetupBot(listeners ...dbot.EventListener) error {
+	ratingSystem, err := game.NewRatingSystem(b.Cfg.Bot.RatingSystem, game.DefaultGameSettings().ELOKFactor)
+	if err != nil {
+		return err
+	}
+	b.RatingSystem = ratingSystem
+
 	// Database setup
 	dbConn, err := db.NewDB(b.Cfg.Database)
 	if err != nil {
@@ -76,6 +87,8 @@ func (b *Bot) SetupBot(listeners ...dbot.EventListener) error {
 		DB:       b.Cfg.Redis.DB,
 	})
 	b.Redis = redisClient
+	b.RankedQueue = memstore.NewRankedQueue(redisClient)
+	b.Leaderboard = memstore.NewLeaderboard(redisClient)
 
 	// Cache setup
 	redisCache, err := memstore.NewRedisCache(b.Cfg.Redis.Addr, b.Cfg.Redis.Password, b.Cfg.Redis.DB)
@@ -98,19 +111,23 @@ func (b *Bot) SetupBot(listeners ...dbot.EventListener) error {
 	}
 	b.Scheduler = scheduler
 
+	// Paginator setup, it listens for its own button interactions
+	b.Paginator = paginator.New()
+
 	// Discord bot setup
 	client, err := disgo.New(b.Cfg.Bot.Token,
 		dbot.WithGatewayConfigOpts(
 			gateway.WithIntents(
 				gateway.IntentGuilds,
 				gateway.IntentGuildMessages,
+				gateway.IntentGuildMessageReactions,
 				gateway.IntentMessageContent,
 			),
 		),
 		dbot.WithCacheConfigOpts(
 			cache.WithCaches(cache.FlagChannels, cache.FlagGuilds),
 		),
-		dbot.WithEventListeners(append(listeners, &events.ListenerAdapter{
+		dbot.WithEventListeners(append(listeners, b.Paginator, &events.ListenerAdapter{
 			OnReady: b.OnReady,
 		})...),
 	)
@@ -119,9 +136,6 @@ func (b *Bot) SetupBot(listeners ...dbot.EventListener) error {
 	}
 	b.Client = client
 
-	// Paginator setup
-	b.Paginator = paginator.New()
-
 	return nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/disgoorg/snowflake/v2"
+	"github.com/theoreotm/friemon/internal/core/game"
 	"github.com/theoreotm/friemon/internal/infrastructure/db"
 	"github.com/theoreotm/friemon/internal/pkg/logger"
 )
@@ -24,12 +25,14 @@ func LoadConfig() (*Config, error) {
 			OutputPath: getEnvWithDefault("LOG_OUTPUT_PATH", "stdout"),
 		},
 		Bot: BotConfig{
-			Token:        getEnvRequired("BOT_TOKEN"),
-			DevMode:      getEnvBool("DEV_MODE", false),
-			SyncCommands: getEnvBool("SYNC_COMMANDS", true),
-			Version:      getEnvWithDefault("BOT_VERSION", "1.0.0"),
-			DevGuilds:    parseSnowflakes(os.Getenv("DEV_GUILDS")),
-			AdminUsers:   parseSnowflakes(os.Getenv("ADMIN_USERS")),
+			Token:         getEnvRequired("BOT_TOKEN"),
+			DevMode:       getEnvBool("DEV_MODE", false),
+			SyncCommands:  getEnvBool("SYNC_COMMANDS", true),
+			Version:       getEnvWithDefault("BOT_VERSION", "1.0.0"),
+			DevGuilds:     parseSnowflakes(os.Getenv("DEV_GUILDS")),
+			AdminUsers:    parseSnowflakes(os.Getenv("ADMIN_USERS")),
+			RankedChannel: parseSnowflake(os.Getenv("RANKED_CHANNEL_ID")),
+			RatingSystem:  getEnvWithDefault("RATING_SYSTEM", "elo"),
 		},
 		Database: db.Config{
 			Host:     getEnvWithDefault("DB_HOST", "localhost"),
@@ -46,6 +49,12 @@ func LoadConfig() (*Config, error) {
 		},
 	}
 
+	rarityWeights, err := game.ParseRarityWeights(os.Getenv("RARITY_WEIGHTS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RARITY_WEIGHTS: %w", err)
+	}
+	cfg.Bot.RarityWeights = rarityWeights
+
 	// Validate required fields
 	if err := cfg.validate(); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
@@ -72,6 +81,13 @@ type BotConfig struct {
 	SyncCommands bool
 	DevMode      bool
 	Version      string
+
+	// Channel ranked matches are announced in, ranked matchmaking is disabled when unset
+	RankedChannel snowflake.ID
+	// Rating system used for ranked battles, elo or glicko2
+	RatingSystem string
+	// Spawn weights of rarity tiers that differ from the defaults
+	RarityWeights map[game.Rarity]int
 }
 
 // RedisConfig holds Redis connection configuration
@@ -169,6 +185,15 @@ func parseSnowflakes(snowflakeStr string) []snowflake.ID {
 	return snowflakes
 }
 
+// parseSnowflake parses a single snowflake ID, returning 0 if it is empty or invalid
+func parseSnowflake(snowflakeStr string) snowflake.ID {
+	id, err := snowflake.Parse(strings.TrimSpace(snowflakeStr))
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
 // contains checks if a slice contains a string
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func init() {
+	Commands["auction"] = cmdAuction
+}
+
+var cmdAuction = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "auction",
+		Description: "Sell characters to the highest bidder",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "start",
+				Description: "Put one of your characters up for auction",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:         "character",
+						Description:  "The character to auction",
+						Required:     true,
+						Autocomplete: true,
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "duration",
+						Description: "How long the auction runs, in minutes",
+						Required:    true,
+						MinValue:    json.Ptr(int(game.MinAuctionDuration / time.Minute)),
+						MaxValue:    json.Ptr(int(game.MaxAuctionDuration / time.Minute)),
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "starting_bid",
+						Description: "The lowest first bid in coins",
+						Required:    true,
+						MinValue:    json.Ptr(1),
+						MaxValue:    json.Ptr(game.MaxMarketPrice),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "bid",
+				Description: "Bid on an auction",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name:        "id",
+						Description: "The auction ID",
+						Required:    true,
+						MinValue:    json.Ptr(1),
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "amount",
+						Description: "How many coins to bid, the minimum bid by default",
+						MinValue:    json.Ptr(1),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "view",
+				Description: "Show an auction",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name:        "id",
+						Description: "The auction ID",
+						Required:    true,
+						MinValue:    json.Ptr(1),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "cancel",
+				Description: "Cancel one of your auctions nobody has bid on",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name:        "id",
+						Description: "The auction ID",
+						Required:    true,
+						MinValue:    json.Ptr(1),
+					},
+				},
+			},
+		},
+	},
+	Handler:      HandleAuction,
+	Autocomplete: handleGetCharacterAutocomplete,
+	Category:     "Friemon",
+}
+
+func HandleAuction(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+		if data.SubCommandName == nil {
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+
+		switch *data.SubCommandName {
+		case "start":
+			return handleAuctionStart(b, e)
+		case "bid":
+			return handleAuctionBid(b, e)
+		case "view":
+			return handleAuctionView(b, e)
+		case "cancel":
+			return handleAuctionCancel(b, e)
+		default:
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+	}
+}
+
+func handleAuctionStart(b *bot.Bot, e *handler.CommandEvent) error {
+	data := e.SlashCommandInteractionData()
+
+	characterID, err := uuid.Parse(data.String("character"))
+	if err != nil {
+		return e.CreateMessage(ErrorMessage("Select a valid character"))
+	}
+
+	duration := time.Duration(data.Int("duration")) * time.Minute
+	auction, err := services.StartAuction(e.Ctx, b, e.User().ID, e.Channel().ID(), characterID, duration, data.Int("starting_bid"))
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	err = e.CreateMessage(discord.MessageCreate{
+		Embeds:     []discord.Embed{services.AuctionEmbed(auction)},
+		Components: services.AuctionComponents(auction),
+	})
+	if err != nil {
+		return err
+	}
+
+	if message, err := e.GetInteractionResponse(); err == nil {
+		if err := services.SetAuctionMessage(e.Ctx, b, auction, message.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func handleAuctionBid(b *bot.Bot, e *handler.CommandEvent) error {
+	data := e.SlashCommandInteractionData()
+
+	auction, err := services.PlaceAuctionBid(e.Ctx, b, e.User().ID, data.Int("id"), data.Int("amount"))
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(SuccessMessage("Bid placed", fmt.Sprintf(
+		"You have the top bid of **%d coins** on **%s**.\nThe coins are held until you are outbid or the auction ends <t:%d:R>.",
+		auction.CurrentBid, auction.Character.Format("l"), auction.EndsAt.Unix(),
+	)))
+}
+
+func handleAuctionView(b *bot.Bot, e *handler.CommandEvent) error {
+	id := e.SlashCommandInteractionData().Int("id")
+
+	auction, err := b.DB.GetAuction(e.Ctx, id)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+	if auction == nil {
+		return e.CreateMessage(ErrorMessage(fmt.Sprintf("There is no auction with ID %d", id)))
+	}
+
+	return e.CreateMessage(discord.MessageCreate{
+		Embeds:     []discord.Embed{services.AuctionEmbed(auction)},
+		Components: services.AuctionComponents(auction),
+	})
+}
+
+func handleAuctionCancel(b *bot.Bot, e *handler.CommandEvent) error {
+	auction, err := services.CancelAuction(e.Ctx, b, e.User().ID, e.SlashCommandInteractionData().Int("id"))
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(SuccessMessage("Auction cancelled", fmt.Sprintf("Your **%s No. %d** is back in your collection.",
+		auction.Character.Format("ln"), auction.Character.IDX)))
+}
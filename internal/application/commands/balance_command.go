@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+)
+
+const balanceHistorySize = 10
+
+func init() {
+	Commands["balance"] = cmdBalance
+}
+
+var cmdBalance = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "balance",
+		Description: "View your coins and recent transactions",
+	},
+	Handler:  HandleBalance,
+	Category: "Friemon",
+}
+
+func HandleBalance(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		user, err := b.DB.GetUser(e.Ctx, e.User().ID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+		if user == nil {
+			return e.CreateMessage(ErrorMessage("You haven't started playing yet"))
+		}
+
+		transactions, err := b.DB.GetTransactions(e.Ctx, user.ID, balanceHistorySize)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		history := "No transactions yet"
+		if len(transactions) > 0 {
+			lines := make([]string, len(transactions))
+			for i, transaction := range transactions {
+				line := fmt.Sprintf("`%+d`　%s", transaction.Amount, transaction.Reason.Name())
+				if transaction.Reference != "" {
+					line += fmt.Sprintf(" (%s)", transaction.Reference)
+				}
+				lines[i] = line + fmt.Sprintf("　<t:%d:R>", transaction.CreatedAt.Unix())
+			}
+			history = strings.Join(lines, "\n")
+		}
+
+		embed := discord.NewEmbedBuilder().
+			SetTitle("Balance").
+			SetDescription(fmt.Sprintf("You have **%d coins**.", user.Balance)).
+			AddField("Recent Transactions", history, false).
+			SetColor(constants.ColorInfo).
+			Build()
+
+		return e.CreateMessage(discord.MessageCreate{Embeds: []discord.Embed{embed}})
+	}
+}
@@ -1,13 +1,20 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+	"github.com/disgoorg/paginator"
 	"github.com/theoreotm/friemon/constants"
 	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
 	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/memstore"
 )
 
 func init() {
@@ -17,12 +24,43 @@ func init() {
 var cmdBattle = &Command{
 	Cmd: discord.SlashCommandCreate{
 		Name:        "battle",
-		Description: "Challenge another user to a battle.",
+		Description: "Battle other users.",
 		Options: []discord.ApplicationCommandOption{
-			discord.ApplicationCommandOptionUser{
-				Name:        "user",
-				Description: "The user you want to challenge.",
-				Required:    true,
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "challenge",
+				Description: "Challenge another user to a battle.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionUser{
+						Name:        "user",
+						Description: "The user you want to challenge.",
+						Required:    true,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "watch",
+				Description: "Watch the battle running in this channel.",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "queue",
+				Description: "Join the ranked queue and get matched with a similarly rated player.",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "leave-queue",
+				Description: "Leave the ranked queue.",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "replay",
+				Description: "Step through a finished battle turn by turn.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:        "id",
+						Description: "The 8 character battle ID.",
+						Required:    true,
+						MinLength:   json.Ptr(8),
+						MaxLength:   json.Ptr(8),
+					},
+				},
 			},
 		},
 	},
@@ -32,55 +70,166 @@ var cmdBattle = &Command{
 
 func HandleBattle(b *bot.Bot) handler.CommandHandler {
 	return func(e *handler.CommandEvent) error {
-		challenger := e.User()
-		challenged := e.SlashCommandInteractionData().User("user")
-
-		if challenger.ID == challenged.ID {
-			e.CreateMessage(ErrorMessage("You cannot challenge yourself!"))
-			return nil
-		}
-		if challenged.Bot {
-			e.CreateMessage(ErrorMessage("You cannot challenge a bot!"))
-			return nil
+		data := e.SlashCommandInteractionData()
+		if data.SubCommandName == nil {
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
 		}
 
-		// Check if either player is already in a battle
-		if _, inBattle := b.BattleManager.GetPlayerBattle(challenger.ID); inBattle {
-			e.CreateMessage(ErrorMessage("You are already in a battle!"))
-			return nil
-		}
-		if _, inBattle := b.BattleManager.GetPlayerBattle(challenged.ID); inBattle {
-			e.CreateMessage(ErrorMessage(fmt.Sprintf("%s is already in a battle!", challenged.Username)))
-			return nil
+		switch *data.SubCommandName {
+		case "challenge":
+			return handleBattleChallenge(b, e)
+		case "watch":
+			return handleBattleWatch(b, e)
+		case "queue":
+			return handleBattleQueue(b, e)
+		case "leave-queue":
+			return handleBattleLeaveQueue(b, e)
+		case "replay":
+			return handleBattleReplay(b, e)
+		default:
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
 		}
+	}
+}
 
-		// Create the challenge
-		challenge, err := b.BattleManager.CreateChallenge(challenger.ID, challenged.ID, e.ChannelID(), game.DefaultGameSettings())
-		if err != nil {
-			e.CreateMessage(ErrorMessage(err.Error()))
-			return nil
-		}
+func handleBattleWatch(b *bot.Bot, e *handler.CommandEvent) error {
+	battle, exists := b.BattleManager.GetChannelBattle(e.Channel().ID())
+	if !exists {
+		return e.CreateMessage(InfoMessage("There is no battle running in this channel."))
+	}
+
+	content := fmt.Sprintf("<@%s> vs <@%s> • %s", battle.Player1.ID, battle.Player2.ID, battle.State.String())
+	if battle.ThreadID != 0 {
+		content += fmt.Sprintf("\nFollow the action in <#%s>!", battle.ThreadID)
+	}
+
+	message := discord.MessageCreate{Content: content}
+	if battle.State == game.BattleStateInProgress {
+		message.Embeds = []discord.Embed{services.TurnSummaryEmbed(battle.TurnSummary())}
+	}
+
+	return e.CreateMessage(message)
+}
+
+func handleBattleQueue(b *bot.Bot, e *handler.CommandEvent) error {
+	if b.Cfg.Bot.RankedChannel == 0 {
+		return e.CreateMessage(ErrorMessage("Ranked matchmaking is not enabled."))
+	}
+
+	userID := e.User().ID
+	if b.BattleManager.IsPlayerInBattle(userID) {
+		return e.CreateMessage(ErrorMessage("You are already in a battle!"))
+	}
 
-		// Send challenge embed
-		embed := discord.NewEmbedBuilder().
-			SetTitle("⚔️ Battle Challenge!").
-			SetDescription(fmt.Sprintf("%s has challenged %s to a battle!", challenger.Mention(), challenged.Mention())).
-			SetColor(constants.ColorInfo).
-			AddField("Rules", "3v3, Level 100", true).
-			AddField("Expires", discord.TimestampStyleRelative.Format(challenge.ExpiresAt.Unix()), true).
-			Build()
-
-		components := discord.NewActionRow(
-			discord.NewSuccessButton("Accept", fmt.Sprintf("battle_challenge_accept/%s", challenge.ID)),
-			discord.NewDangerButton("Decline", fmt.Sprintf("battle_challenge_decline/%s", challenge.ID)),
-		)
-
-		e.CreateMessage(discord.MessageCreate{
-			Embeds:     []discord.Embed{embed},
-			Components: []discord.ContainerComponent{components},
-		})
+	user, err := b.DB.EnsureUser(e.Ctx, userID)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	// Uncertain ratings are matched conservatively so new players don't get
+	// paired above their real skill
+	err = b.RankedQueue.Join(e.Ctx, game.QueueEntry{
+		PlayerID: userID,
+		Rating:   b.RatingSystem.Conservative(user.Rating()),
+		JoinedAt: time.Now(),
+	})
+	if errors.Is(err, memstore.ErrAlreadyQueued) {
+		return e.CreateMessage(InfoMessage("You are already in the ranked queue."))
+	}
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
 
+	return e.CreateMessage(SuccessMessage("Queued", fmt.Sprintf("You joined the ranked queue with a rating of **%d**. Your match will be announced in <#%s>.", user.ELO, b.Cfg.Bot.RankedChannel)))
+}
+
+func handleBattleLeaveQueue(b *bot.Bot, e *handler.CommandEvent) error {
+	left, err := b.RankedQueue.Leave(e.Ctx, e.User().ID)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	if !left {
+		return e.CreateMessage(InfoMessage("You are not in the ranked queue."))
+	}
+
+	return e.CreateMessage(SuccessMessage("Left Queue", "You left the ranked queue."))
+}
+
+func handleBattleReplay(b *bot.Bot, e *handler.CommandEvent) error {
+	shortID := strings.ToLower(e.SlashCommandInteractionData().String("id"))
+
+	replay, err := b.DB.GetBattleReplay(e.Ctx, shortID)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(fmt.Sprintf("Could not find a replay with ID `%s`.", shortID)))
+	}
+
+	frames, err := game.Simulate(replay)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(fmt.Sprintf("Failed to replay battle: %s", err)))
+	}
+
+	return b.Paginator.Create(e.Respond, paginator.Pages{
+		ID: e.ID().String(),
+		PageFunc: func(page int, embed *discord.EmbedBuilder) {
+			services.WriteTurnSummary(embed, frames[page])
+			embed.SetAuthorName(fmt.Sprintf("Replay %s", replay.ShortID()))
+			embed.SetFooterTextf("Frame %d of %d • Played %s", page+1, len(frames), replay.FinishedAt.Format("2006-01-02"))
+		},
+		Pages:      len(frames),
+		Creator:    e.User().ID,
+		ExpireMode: paginator.ExpireModeAfterLastUsage,
+	}, false)
+}
+
+func handleBattleChallenge(b *bot.Bot, e *handler.CommandEvent) error {
+	challenger := e.User()
+	challenged := e.SlashCommandInteractionData().User("user")
+
+	if challenger.ID == challenged.ID {
+		e.CreateMessage(ErrorMessage("You cannot challenge yourself!"))
 		return nil
 	}
+	if challenged.Bot {
+		e.CreateMessage(ErrorMessage("You cannot challenge a bot!"))
+		return nil
+	}
+
+	// Check if either player is already in a battle
+	if _, inBattle := b.BattleManager.GetPlayerBattle(challenger.ID); inBattle {
+		e.CreateMessage(ErrorMessage("You are already in a battle!"))
+		return nil
+	}
+	if _, inBattle := b.BattleManager.GetPlayerBattle(challenged.ID); inBattle {
+		e.CreateMessage(ErrorMessage(fmt.Sprintf("%s is already in a battle!", challenged.Username)))
+		return nil
+	}
+
+	// Create the challenge
+	challenge, err := b.BattleManager.CreateChallenge(challenger.ID, challenged.ID, e.ChannelID(), game.DefaultGameSettings())
+	if err != nil {
+		e.CreateMessage(ErrorMessage(err.Error()))
+		return nil
+	}
+
+	// Send challenge embed
+	embed := discord.NewEmbedBuilder().
+		SetTitle("⚔️ Battle Challenge!").
+		SetDescription(fmt.Sprintf("%s has challenged %s to a battle!", challenger.Mention(), challenged.Mention())).
+		SetColor(constants.ColorInfo).
+		AddField("Rules", "3v3, Level 100", true).
+		AddField("Expires", discord.TimestampStyleRelative.Format(challenge.ExpiresAt.Unix()), true).
+		Build()
+
+	components := discord.NewActionRow(
+		discord.NewSuccessButton("Accept", fmt.Sprintf("battle_challenge_accept/%s", challenge.ID)),
+		discord.NewDangerButton("Decline", fmt.Sprintf("battle_challenge_decline/%s", challenge.ID)),
+	)
+
+	e.CreateMessage(discord.MessageCreate{
+		Embeds:     []discord.Embed{embed},
+		Components: []discord.ContainerComponent{components},
+	})
 
+	return nil
 }
@@ -25,7 +25,7 @@ var cmdCharacter = &Command{
 func HandleCharacter(b *bot.Bot) handler.CommandHandler {
 
 	return func(e *handler.CommandEvent) error {
-		character := game.RandomCharacterSpawn()
+		character := game.RandomCharacterSpawn(game.DefaultSpawnModifiers())
 		user, err := b.DB.EnsureUser(e.Ctx, e.User().ID)
 		if err != nil {
 			return e.CreateMessage(ErrorMessage(err.Error()))
@@ -39,8 +39,7 @@ func HandleCharacter(b *bot.Bot) handler.CommandHandler {
 		}
 
 		// Update the user's selected character
-		user.SelectedID = dbCharacter.ID
-		if _, err := b.DB.UpdateUser(e.Ctx, *user); err != nil {
+		if err := b.DB.SetSelectedCharacter(e.Ctx, user.ID, dbCharacter.ID); err != nil {
 			return e.CreateMessage(ErrorMessage(err.Error()))
 		}
 
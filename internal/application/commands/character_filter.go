@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/json"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+// characterFilterOptions are the options commands use to pick characters from
+// a player's collection.
+func characterFilterOptions() []discord.ApplicationCommandOption {
+	return []discord.ApplicationCommandOption{
+		discord.ApplicationCommandOptionString{
+			Name:        "name",
+			Description: "Only characters whose name starts with this",
+		},
+		discord.ApplicationCommandOptionString{
+			Name:        "nickname",
+			Description: "Only characters whose nickname contains this",
+		},
+		discord.ApplicationCommandOptionBool{
+			Name:        "shiny",
+			Description: "Only shiny (or non-shiny) characters",
+		},
+		discord.ApplicationCommandOptionBool{
+			Name:        "favourite",
+			Description: "Only favourite (or non-favourite) characters",
+		},
+		discord.ApplicationCommandOptionString{
+			Name:        "personality",
+			Description: "Only characters with this personality",
+			Choices:     personalityChoices(),
+		},
+		discord.ApplicationCommandOptionString{
+			Name:        "type",
+			Description: "Only characters of this type",
+			Choices:     typeChoices(),
+		},
+		discord.ApplicationCommandOptionInt{
+			Name:        "min_level",
+			Description: "Lowest level to include",
+			MinValue:    json.Ptr(1),
+			MaxValue:    json.Ptr(100),
+		},
+		discord.ApplicationCommandOptionInt{
+			Name:        "max_level",
+			Description: "Highest level to include",
+			MinValue:    json.Ptr(1),
+			MaxValue:    json.Ptr(100),
+		},
+		discord.ApplicationCommandOptionFloat{
+			Name:        "min_iv",
+			Description: "Lowest IV percentage to include",
+			MinValue:    json.Ptr(0.0),
+			MaxValue:    json.Ptr(100.0),
+		},
+		discord.ApplicationCommandOptionFloat{
+			Name:        "max_iv",
+			Description: "Highest IV percentage to include",
+			MinValue:    json.Ptr(0.0),
+			MaxValue:    json.Ptr(100.0),
+		},
+	}
+}
+
+// characterFilter reads the options added by characterFilterOptions.
+func characterFilter(data discord.SlashCommandInteractionData) game.CharacterFilter {
+	filter := game.CharacterFilter{
+		Name:        data.String("name"),
+		Nickname:    data.String("nickname"),
+		Personality: data.String("personality"),
+		MinLevel:    data.Int("min_level"),
+		MaxLevel:    data.Int("max_level"),
+		MinIV:       data.Float("min_iv"),
+		MaxIV:       data.Float("max_iv"),
+	}
+
+	if t, ok := game.ParseType(data.String("type")); ok {
+		filter.Type = t
+	}
+	if shiny, ok := data.OptBool("shiny"); ok {
+		filter.Shiny = &shiny
+	}
+	if favourite, ok := data.OptBool("favourite"); ok {
+		filter.Favourite = &favourite
+	}
+
+	return filter
+}
+
+func personalityChoices() []discord.ApplicationCommandOptionChoiceString {
+	choices := make([]discord.ApplicationCommandOptionChoiceString, len(constants.Personalities))
+	for i, personality := range constants.Personalities {
+		choices[i] = discord.ApplicationCommandOptionChoiceString{Name: personality.String(), Value: personality.String()}
+	}
+	return choices
+}
+
+func typeChoices() []discord.ApplicationCommandOptionChoiceString {
+	choices := make([]discord.ApplicationCommandOptionChoiceString, 0, game.TypeFairy)
+	for t := game.TypeNormal; t <= game.TypeFairy; t++ {
+		choices = append(choices, discord.ApplicationCommandOptionChoiceString{Name: t.String(), Value: t.String()})
+	}
+	return choices
+}
@@ -0,0 +1,491 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func init() {
+	Commands["config"] = cmdConfig
+}
+
+var spawnChannelTypes = []discord.ChannelType{discord.ChannelTypeGuildText}
+
+var cmdConfig = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:                     "config",
+		Description:              "Configure the bot for this server",
+		DefaultMemberPermissions: json.NewNullablePtr(discord.PermissionManageGuild),
+		Contexts:                 []discord.InteractionContextType{discord.InteractionContextTypeGuild},
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommandGroup{
+				Name:        "spawn",
+				Description: "Configure where and how often characters spawn",
+				Options: []discord.ApplicationCommandOptionSubCommand{
+					{
+						Name:        "show",
+						Description: "Show the spawn settings",
+					},
+					{
+						Name:        "channel",
+						Description: "Allow or block spawns in a channel",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionChannel{
+								Name:         "channel",
+								Description:  "The channel",
+								Required:     true,
+								ChannelTypes: spawnChannelTypes,
+							},
+							discord.ApplicationCommandOptionString{
+								Name:        "mode",
+								Description: "Allowing any channel limits spawns to allowed channels",
+								Required:    true,
+								Choices: []discord.ApplicationCommandOptionChoiceString{
+									{Name: "Allow", Value: "allow"},
+									{Name: "Block", Value: "block"},
+									{Name: "Default", Value: "default"},
+								},
+							},
+						},
+					},
+					{
+						Name:        "redirect",
+						Description: "Spawn every character in one channel",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionChannel{
+								Name:         "channel",
+								Description:  "The channel, leave empty to spawn where people chat",
+								ChannelTypes: spawnChannelTypes,
+							},
+						},
+					},
+					{
+						Name:        "threshold",
+						Description: "Set how many messages it takes for a character to spawn",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionInt{
+								Name:        "min",
+								Description: "Fewest messages",
+								Required:    true,
+								MinValue:    json.Ptr(1),
+								MaxValue:    json.Ptr(game.MaxSpawnThreshold),
+							},
+							discord.ApplicationCommandOptionInt{
+								Name:        "max",
+								Description: "Most messages, the minimum by default",
+								MinValue:    json.Ptr(1),
+								MaxValue:    json.Ptr(game.MaxSpawnThreshold),
+							},
+						},
+					},
+					{
+						Name:        "cooldown",
+						Description: "Set the least time between spawns in a channel",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionInt{
+								Name:        "seconds",
+								Description: "The cooldown in seconds, 0 for none",
+								Required:    true,
+								MinValue:    json.Ptr(0),
+								MaxValue:    json.Ptr(int(game.MaxSpawnCooldown / time.Second)),
+							},
+						},
+					},
+					{
+						Name:        "shiny",
+						Description: "Multiply the shiny rate, for events",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionFloat{
+								Name:        "multiplier",
+								Description: "The multiplier, 1 for the normal rate",
+								Required:    true,
+								MinValue:    json.Ptr(1.0),
+								MaxValue:    json.Ptr(game.MaxShinyMultiplier),
+							},
+						},
+					},
+					{
+						Name:        "catch",
+						Description: "Choose how spawned characters are caught",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name:        "mode",
+								Description: "Guessing hides the name until someone types it",
+								Required:    true,
+								Choices: []discord.ApplicationCommandOptionChoiceString{
+									{Name: "Claim button", Value: "button"},
+									{Name: "Guess the name", Value: "guess"},
+								},
+							},
+						},
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommandGroup{
+				Name:        "spam",
+				Description: "Configure which messages count toward spawns and XP",
+				Options: []discord.ApplicationCommandOptionSubCommand{
+					{
+						Name:        "show",
+						Description: "Show the spam filter settings",
+					},
+					{
+						Name:        "length",
+						Description: "Set how many letters a message needs to count",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionInt{
+								Name:        "letters",
+								Description: "The fewest letters, 0 to count every message",
+								Required:    true,
+								MinValue:    json.Ptr(0),
+								MaxValue:    json.Ptr(game.MaxMinMessageLength),
+							},
+						},
+					},
+					{
+						Name:        "duplicates",
+						Description: "Choose whether repeating your last message counts",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionBool{
+								Name:        "block",
+								Description: "Ignore repeated messages",
+								Required:    true,
+							},
+						},
+					},
+					{
+						Name:        "ratelimit",
+						Description: "Set how many messages a minute count for each user",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionInt{
+								Name:        "messages",
+								Description: "The most messages a minute, 0 for no limit",
+								Required:    true,
+								MinValue:    json.Ptr(0),
+								MaxValue:    json.Ptr(game.MaxMessageRateLimit),
+							},
+						},
+					},
+					{
+						Name:        "share",
+						Description: "Set how much of a spawn's messages one user can send",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionInt{
+								Name:        "percent",
+								Description: "The largest share in percent, 100 for no limit",
+								Required:    true,
+								MinValue:    json.Ptr(1),
+								MaxValue:    json.Ptr(100),
+							},
+						},
+					},
+					{
+						Name:        "log",
+						Description: "Report filtered spam to a moderation channel",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionChannel{
+								Name:         "channel",
+								Description:  "The channel, leave empty to stop reporting",
+								ChannelTypes: spawnChannelTypes,
+							},
+						},
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommandGroup{
+				Name:        "levelup",
+				Description: "Configure how level-ups are announced",
+				Options: []discord.ApplicationCommandOptionSubCommand{
+					{
+						Name:        "show",
+						Description: "Show the level-up settings",
+					},
+					{
+						Name:        "notify",
+						Description: "Choose where level-ups are announced",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionInt{
+								Name:        "mode",
+								Description: "Where to announce level-ups",
+								Required:    true,
+								Choices: []discord.ApplicationCommandOptionChoiceInt{
+									{Name: "In the channel", Value: int(game.LevelUpInChannel)},
+									{Name: "In a direct message", Value: int(game.LevelUpInDM)},
+									{Name: "Off", Value: int(game.LevelUpOff)},
+								},
+							},
+						},
+					},
+					{
+						Name:        "channel",
+						Description: "Announce level-ups in one channel",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionChannel{
+								Name:         "channel",
+								Description:  "The channel, leave empty to announce where XP was earned",
+								ChannelTypes: spawnChannelTypes,
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+	Handler:  HandleConfig,
+	Category: "Bot",
+}
+
+func HandleConfig(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+		if data.SubCommandGroupName == nil || data.SubCommandName == nil {
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+		group := *data.SubCommandGroupName
+
+		if member := e.Member(); member == nil || !member.Permissions.Has(discord.PermissionManageGuild) {
+			return e.CreateMessage(ErrorMessage("You need the Manage Server permission to change the bot's settings."))
+		}
+		guildID := *e.GuildID()
+
+		if *data.SubCommandName == "show" {
+			settings, err := services.GuildSettings(e.Ctx, b, guildID)
+			if err != nil {
+				return e.CreateMessage(ErrorMessage(err.Error()))
+			}
+			return e.CreateMessage(discord.MessageCreate{Embeds: []discord.Embed{settingsEmbed(group, settings)}})
+		}
+
+		var update func(*game.GuildSettings)
+		switch group + " " + *data.SubCommandName {
+		case "spawn channel":
+			channelID := data.Channel("channel").ID
+			mode := data.String("mode")
+			update = func(s *game.GuildSettings) {
+				s.AllowedChannels = removeChannel(s.AllowedChannels, channelID)
+				s.BlockedChannels = removeChannel(s.BlockedChannels, channelID)
+				switch mode {
+				case "allow":
+					s.AllowedChannels = append(s.AllowedChannels, channelID)
+				case "block":
+					s.BlockedChannels = append(s.BlockedChannels, channelID)
+				}
+			}
+		case "spawn redirect":
+			var channelID snowflake.ID
+			if channel, ok := data.OptChannel("channel"); ok {
+				channelID = channel.ID
+			}
+			update = func(s *game.GuildSettings) {
+				s.RedirectChannelID = channelID
+			}
+		case "spawn threshold":
+			minimum := data.Int("min")
+			maximum, ok := data.OptInt("max")
+			if !ok {
+				maximum = minimum
+			}
+			update = func(s *game.GuildSettings) {
+				s.SpawnThresholdMin = minimum
+				s.SpawnThresholdMax = maximum
+			}
+		case "spawn cooldown":
+			cooldown := time.Duration(data.Int("seconds")) * time.Second
+			update = func(s *game.GuildSettings) {
+				s.SpawnCooldown = cooldown
+			}
+		case "spawn shiny":
+			multiplier := data.Float("multiplier")
+			update = func(s *game.GuildSettings) {
+				s.ShinyMultiplier = multiplier
+			}
+		case "spawn catch":
+			catchByName := data.String("mode") == "guess"
+			update = func(s *game.GuildSettings) {
+				s.CatchByName = catchByName
+			}
+		case "spam length":
+			length := data.Int("letters")
+			update = func(s *game.GuildSettings) {
+				s.MinMessageLength = length
+			}
+		case "spam duplicates":
+			block := data.Bool("block")
+			update = func(s *game.GuildSettings) {
+				s.BlockDuplicates = block
+			}
+		case "spam ratelimit":
+			limit := data.Int("messages")
+			update = func(s *game.GuildSettings) {
+				s.MessageRateLimit = limit
+			}
+		case "spam share":
+			share := float64(data.Int("percent")) / 100
+			update = func(s *game.GuildSettings) {
+				s.MaxUserSpawnShare = share
+			}
+		case "spam log":
+			var channelID snowflake.ID
+			if channel, ok := data.OptChannel("channel"); ok {
+				channelID = channel.ID
+			}
+			update = func(s *game.GuildSettings) {
+				s.ModLogChannelID = channelID
+			}
+		case "levelup notify":
+			notify := game.LevelUpNotify(data.Int("mode"))
+			update = func(s *game.GuildSettings) {
+				s.LevelUpNotify = notify
+			}
+		case "levelup channel":
+			var channelID snowflake.ID
+			if channel, ok := data.OptChannel("channel"); ok {
+				channelID = channel.ID
+			}
+			update = func(s *game.GuildSettings) {
+				s.LevelUpChannelID = channelID
+			}
+		default:
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+
+		settings, err := services.UpdateGuildSettings(e.Ctx, b, guildID, update)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		embed := settingsEmbed(group, settings)
+		embed.Title += " updated"
+		embed.Color = constants.ColorSuccess
+		return e.CreateMessage(discord.MessageCreate{Embeds: []discord.Embed{embed}})
+	}
+}
+
+// settingsEmbed shows the settings of one subcommand group.
+func settingsEmbed(group string, settings game.GuildSettings) discord.Embed {
+	switch group {
+	case "spam":
+		return spamSettingsEmbed(settings)
+	case "levelup":
+		return levelUpSettingsEmbed(settings)
+	default:
+		return spawnSettingsEmbed(settings)
+	}
+}
+
+func levelUpSettingsEmbed(settings game.GuildSettings) discord.Embed {
+	channel := "Where XP was earned"
+	if settings.LevelUpChannelID != 0 {
+		channel = fmt.Sprintf("<#%s>", settings.LevelUpChannelID)
+	}
+
+	return discord.NewEmbedBuilder().
+		SetTitle("Level-up settings").
+		SetColor(constants.ColorInfo).
+		AddField("Announcements", settings.LevelUpNotify.String(), true).
+		AddField("Channel", channel, true).
+		Build()
+}
+
+func spamSettingsEmbed(settings game.GuildSettings) discord.Embed {
+	length := "Every message counts"
+	if settings.MinMessageLength > 0 {
+		length = fmt.Sprintf("%d letters", settings.MinMessageLength)
+	}
+
+	duplicates := "Counted"
+	if settings.BlockDuplicates {
+		duplicates = "Ignored"
+	}
+
+	rateLimit := "None"
+	if settings.MessageRateLimit > 0 {
+		rateLimit = fmt.Sprintf("%d messages a minute", settings.MessageRateLimit)
+	}
+
+	share := "No limit"
+	if limit := settings.MaxUserSpawnMessages(); limit > 0 {
+		share = fmt.Sprintf("%g%%, %d of every %d messages when others are talking", settings.MaxUserSpawnShare*100, limit, settings.SpawnThresholdMax)
+	}
+
+	modLog := "Off"
+	if settings.ModLogChannelID != 0 {
+		modLog = fmt.Sprintf("<#%s>", settings.ModLogChannelID)
+	}
+
+	return discord.NewEmbedBuilder().
+		SetTitle("Spam settings").
+		SetColor(constants.ColorInfo).
+		AddField("Minimum Length", length, true).
+		AddField("Repeated Messages", duplicates, true).
+		AddField("Rate Limit", rateLimit, true).
+		AddField("Share of a Spawn", share, true).
+		AddField("Moderation Log", modLog, true).
+		Build()
+}
+
+func spawnSettingsEmbed(settings game.GuildSettings) discord.Embed {
+	channels := func(ids []snowflake.ID, empty string) string {
+		if len(ids) == 0 {
+			return empty
+		}
+		mentions := make([]string, len(ids))
+		for i, id := range ids {
+			mentions[i] = fmt.Sprintf("<#%s>", id)
+		}
+		return strings.Join(mentions, ", ")
+	}
+
+	redirect := "Spawns where people chat"
+	if settings.RedirectChannelID != 0 {
+		redirect = fmt.Sprintf("<#%s>", settings.RedirectChannelID)
+	}
+
+	threshold := fmt.Sprintf("%d messages", settings.SpawnThresholdMin)
+	if settings.SpawnThresholdMax != settings.SpawnThresholdMin {
+		threshold = fmt.Sprintf("%d to %d messages", settings.SpawnThresholdMin, settings.SpawnThresholdMax)
+	}
+
+	cooldown := "None"
+	if settings.SpawnCooldown > 0 {
+		cooldown = settings.SpawnCooldown.String()
+	}
+
+	catching := "Claim button"
+	if settings.CatchByName {
+		catching = "Guess the name"
+	}
+
+	return discord.NewEmbedBuilder().
+		SetTitle("Spawn settings").
+		SetColor(constants.ColorInfo).
+		AddField("Allowed Channels", channels(settings.AllowedChannels, "All channels"), false).
+		AddField("Blocked Channels", channels(settings.BlockedChannels, "None"), false).
+		AddField("Redirect", redirect, false).
+		AddField("Threshold", threshold, true).
+		AddField("Cooldown", cooldown, true).
+		AddField("Shiny Rate", fmt.Sprintf("×%g", settings.ShinyMultiplier), true).
+		AddField("Catching", catching, true).
+		Build()
+}
+
+// removeChannel returns ids without channelID.
+func removeChannel(ids []snowflake.ID, channelID snowflake.ID) []snowflake.ID {
+	kept := make([]snowflake.ID, 0, len(ids))
+	for _, id := range ids {
+		if id != channelID {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
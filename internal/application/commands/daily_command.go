@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func init() {
+	Commands["daily"] = cmdDaily
+}
+
+var cmdDaily = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "daily",
+		Description: "Claim your daily coins, more for every day in a row",
+	},
+	Handler:  HandleDaily,
+	Category: "Friemon",
+}
+
+func HandleDaily(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		claim, err := services.ClaimDaily(e.Ctx, b, e.User().ID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		description := fmt.Sprintf("You received **%d coins** and now have **%d coins**.\nStreak: **%d day(s)**", claim.Reward, claim.Balance, claim.Streak)
+		if claim.Streak%7 == 0 {
+			description += fmt.Sprintf("\nA full week in a row earned you a bonus of %d coins!", game.DailyWeeklyBonus)
+		}
+
+		return e.CreateMessage(SuccessMessage("Daily reward", description))
+	}
+}
@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+)
+
+func init() {
+	Commands["dex"] = cmdDex
+}
+
+var cmdDex = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "dex",
+		Description: "Look up a species, or see how much of the dex you've caught",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionString{
+				Name:         "species",
+				Description:  "The species to look up, your completion if empty",
+				Autocomplete: true,
+			},
+		},
+	},
+	Handler:      HandleDex,
+	Autocomplete: handleSpeciesAutocomplete,
+	Category:     "Friemon",
+}
+
+func HandleDex(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		dex, err := b.DB.GetDex(e.Ctx, e.User().ID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		var message discord.MessageCreate
+		if name, ok := e.SlashCommandInteractionData().OptString("species"); ok {
+			character, found := game.FindBaseCharacter(name)
+			if !found {
+				return e.CreateMessage(ErrorMessage(fmt.Sprintf("There's no species called %s", name)))
+			}
+			message = speciesMessage(character, dex[character.ID])
+		} else {
+			user, err := b.DB.GetUser(e.Ctx, e.User().ID)
+			if err != nil {
+				return e.CreateMessage(ErrorMessage(err.Error()))
+			}
+			claimed := 0
+			if user != nil {
+				claimed = user.DexMilestones
+			}
+			message = discord.MessageCreate{Embeds: []discord.Embed{services.DexCompletionEmbed(e.User(), dex, claimed)}}
+			// Milestones reached before the dex was kept, or missed by a
+			// failed catch, are claimed by hand
+			if len(dex.ReachedDexMilestones(claimed)) > 0 {
+				message.Components = []discord.ContainerComponent{services.DexClaimButton(e.User().ID)}
+			}
+		}
+
+		return e.CreateMessage(message)
+	}
+}
+
+// speciesMessage shows a species' base stats, types, matchups and learnset,
+// and how many of it the user caught.
+func speciesMessage(character game.BaseCharacter, entry game.DexEntry) discord.MessageCreate {
+	types := character.Type0.String()
+	if character.Type1 != game.TypeNone {
+		types += " / " + character.Type1.String()
+	}
+
+	embed := discord.NewEmbedBuilder().
+		SetTitlef("#%03d %s", character.ID, character.Name).
+		SetColor(character.Rarity.Color()).
+		AddField("Types", types, true).
+		AddField("Rarity", character.Rarity.String(), true)
+
+	if evolution := speciesEvolution(character); evolution != "" {
+		embed.AddField("Evolution", evolution, true)
+	}
+
+	stats := []struct {
+		name  string
+		value int
+	}{
+		{"HP", character.HP},
+		{"Attack", character.Atk},
+		{"Defense", character.Def},
+		{"Sp. Atk", character.SpAtk},
+		{"Sp. Def", character.SpDef},
+		{"Speed", character.Spd},
+	}
+	lines := make([]string, len(stats))
+	for i, stat := range stats {
+		lines[i] = fmt.Sprintf("%-8s %3d %s", stat.name, stat.value, game.StatBar(stat.value))
+	}
+	lines = append(lines, fmt.Sprintf("%-8s %3d", "Total", character.BaseStatTotal()))
+	embed.AddField("Base Stats", "```\n"+strings.Join(lines, "\n")+"\n```", false)
+
+	matchups := character.TypeMatchups()
+	embed.AddField("Weak to", formatMatchups(matchups, game.SuperEffective*game.SuperEffective, game.SuperEffective), true).
+		AddField("Resists", formatMatchups(matchups, game.NotVeryEffective*game.NotVeryEffective, game.NotVeryEffective), true).
+		AddField("Immune to", formatMatchups(matchups, game.NoEffect), true)
+
+	learnset := character.Learnset()
+	moves := make([]string, len(learnset))
+	for i, move := range learnset {
+		moves[i] = fmt.Sprintf("`Lv. %2d` %s (%s)", move.Level, move.Move.Name, move.Move.Type)
+	}
+	embed.AddField("Learnset", strings.Join(moves, "\n"), false)
+
+	caught := "Not caught yet"
+	if entry.Caught > 0 {
+		caught = fmt.Sprintf("%d caught, %d ✨ shiny\nFirst caught %s", entry.Caught, entry.Shinies, entry.FirstCaughtAt.Format("January 2, 2006"))
+	}
+	embed.AddField("Your Dex", caught, false)
+
+	message := discord.MessageCreate{}
+	image, err := character.Image()
+	if err != nil {
+		logger.NewLogger("commands.dex").Warn("Failed to get character image for dex",
+			logger.CharacterName(character.Name),
+			logger.ErrorField(err),
+		)
+	} else {
+		embed.SetImage("attachment://" + image.Name)
+		message.Files = append(message.Files, image)
+	}
+
+	message.Embeds = []discord.Embed{embed.Build()}
+	return message
+}
+
+// speciesEvolution describes what a species evolves into or from.
+func speciesEvolution(character game.BaseCharacter) string {
+	var lines []string
+	for _, evolution := range character.Evolutions() {
+		lines = append(lines, fmt.Sprintf("Into **%s** %s", evolution.Form().Name, evolution.Requirement()))
+	}
+	if from, ok := character.EvolvesFrom(); ok {
+		for _, evolution := range from.Evolutions() {
+			if evolution.FormID == character.ID {
+				lines = append(lines, fmt.Sprintf("From **%s** %s", from.Name, evolution.Requirement()))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatMatchups lists the attacking types with the given effectiveness,
+// marking the ones that are doubled.
+func formatMatchups(matchups map[float64][]game.Type, effectiveness ...float64) string {
+	var names []string
+	for i, e := range effectiveness {
+		for _, t := range matchups[e] {
+			if i == 0 && len(effectiveness) > 1 {
+				names = append(names, fmt.Sprintf("**%s** (×%g)", t, e))
+			} else {
+				names = append(names, t.String())
+			}
+		}
+	}
+	if len(names) == 0 {
+		return "None"
+	}
+	return strings.Join(names, ", ")
+}
+
+// handleSpeciesAutocomplete suggests species by name.
+func handleSpeciesAutocomplete(b *bot.Bot) handler.AutocompleteHandler {
+	return func(e *handler.AutocompleteEvent) error {
+		query := strings.ToLower(e.Data.String("species"))
+
+		choices := make([]discord.AutocompleteChoice, 0, 25)
+		for _, character := range game.DexCharacters() {
+			if len(choices) == 25 {
+				break
+			}
+			if query != "" && !strings.Contains(strings.ToLower(character.Name), query) {
+				continue
+			}
+			choices = append(choices, discord.AutocompleteChoiceString{
+				Name:  fmt.Sprintf("#%03d %s", character.ID, character.Name),
+				Value: character.Name,
+			})
+		}
+
+		return e.AutocompleteResult(choices)
+	}
+}
@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func init() {
+	Commands["event"] = cmdEvent
+}
+
+var cmdEvent = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "event",
+		Description: "Events that boost characters, shinies, IVs and XP",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "list",
+				Description: "Show running and upcoming events",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "create",
+				Description: "Schedule an event, bot admins only",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:        "name",
+						Description: "The event name",
+						Required:    true,
+						MaxLength:   json.Ptr(game.MaxEventNameLength),
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "duration",
+						Description: "How many hours the event runs",
+						Required:    true,
+						MinValue:    json.Ptr(1),
+						MaxValue:    json.Ptr(int(game.MaxEventDuration / time.Hour)),
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        "characters",
+						Description: "Comma separated names of characters to boost",
+					},
+					discord.ApplicationCommandOptionFloat{
+						Name:        "boost",
+						Description: "How many times as often boosted characters spawn",
+						MinValue:    json.Ptr(1.0),
+						MaxValue:    json.Ptr(game.MaxEventBoost),
+					},
+					discord.ApplicationCommandOptionFloat{
+						Name:        "shiny",
+						Description: "Shiny rate multiplier",
+						MinValue:    json.Ptr(1.0),
+						MaxValue:    json.Ptr(game.MaxShinyMultiplier),
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "min_iv",
+						Description: "The lowest any IV of a spawn can be",
+						MinValue:    json.Ptr(0),
+						MaxValue:    json.Ptr(game.MaxEventMinimumIV),
+					},
+					discord.ApplicationCommandOptionFloat{
+						Name:        "xp",
+						Description: "XP multiplier",
+						MinValue:    json.Ptr(1.0),
+						MaxValue:    json.Ptr(game.MaxXPMultiplier),
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "starts_in",
+						Description: "Hours until the event starts, now by default",
+						MinValue:    json.Ptr(0),
+						MaxValue:    json.Ptr(24 * 30),
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "repeat_days",
+						Description: "Repeat the event every this many days",
+						MinValue:    json.Ptr(1),
+						MaxValue:    json.Ptr(365),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "end",
+				Description: "Remove an event, bot admins only",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name:        "id",
+						Description: "The event ID",
+						Required:    true,
+					},
+				},
+			},
+		},
+	},
+	Handler:  HandleEvent,
+	Category: "Bot",
+}
+
+func HandleEvent(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+		if data.SubCommandName == nil {
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+
+		if *data.SubCommandName == "list" {
+			events, err := b.DB.GetSpawnEvents(e.Ctx, time.Now())
+			if err != nil {
+				return e.CreateMessage(ErrorMessage(err.Error()))
+			}
+			return e.CreateMessage(discord.MessageCreate{Embeds: []discord.Embed{spawnEventsEmbed(events)}})
+		}
+
+		if !slices.Contains(b.Cfg.Bot.AdminUsers, e.User().ID) {
+			return e.CreateMessage(ErrorMessage("Only bot admins can manage events"))
+		}
+
+		switch *data.SubCommandName {
+		case "create":
+			event := game.SpawnEvent{
+				Name:            data.String("name"),
+				BoostMultiplier: 1,
+				ShinyMultiplier: 1,
+				MinIV:           data.Int("min_iv"),
+				XPMultiplier:    1,
+			}
+			if boost, ok := data.OptFloat("boost"); ok {
+				event.BoostMultiplier = boost
+			}
+			if shiny, ok := data.OptFloat("shiny"); ok {
+				event.ShinyMultiplier = shiny
+			}
+			if xp, ok := data.OptFloat("xp"); ok {
+				event.XPMultiplier = xp
+			}
+			for _, name := range strings.Split(data.String("characters"), ",") {
+				if strings.TrimSpace(name) == "" {
+					continue
+				}
+				character, ok := game.FindBaseCharacter(name)
+				if !ok {
+					return e.CreateMessage(ErrorMessage(fmt.Sprintf("There's no character called %s", strings.TrimSpace(name))))
+				}
+				if character.IsForm() {
+					return e.CreateMessage(ErrorMessage(fmt.Sprintf("%s only comes from evolving and doesn't spawn", character.Name)))
+				}
+				event.BoostedCharacters = append(event.BoostedCharacters, character.ID)
+			}
+
+			event.StartsAt = time.Now().Add(time.Duration(data.Int("starts_in")) * time.Hour).Truncate(time.Minute)
+			event.EndsAt = event.StartsAt.Add(time.Duration(data.Int("duration")) * time.Hour)
+			event.RecurEvery = time.Duration(data.Int("repeat_days")) * 24 * time.Hour
+
+			created, err := services.CreateSpawnEvent(e.Ctx, b, event)
+			if err != nil {
+				return e.CreateMessage(ErrorMessage(err.Error()))
+			}
+
+			return e.CreateMessage(SuccessMessage("Event scheduled", spawnEventLine(*created)))
+		case "end":
+			deleted, err := services.DeleteSpawnEvent(e.Ctx, b, data.Int("id"))
+			if err != nil {
+				return e.CreateMessage(ErrorMessage(err.Error()))
+			}
+			if !deleted {
+				return e.CreateMessage(ErrorMessage("There's no event with that ID"))
+			}
+			return e.CreateMessage(SuccessMessage("Event removed", fmt.Sprintf("Event #%d won't run again.", data.Int("id"))))
+		default:
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+	}
+}
+
+func spawnEventsEmbed(events []game.SpawnEvent) discord.Embed {
+	embed := discord.NewEmbedBuilder().
+		SetTitle("Events").
+		SetColor(constants.ColorInfo)
+
+	if len(events) == 0 {
+		return embed.SetDescription("No events are running or planned.").Build()
+	}
+
+	lines := make([]string, len(events))
+	for i, event := range events {
+		lines[i] = spawnEventLine(event)
+	}
+	return embed.SetDescription(strings.Join(lines, "\n\n")).Build()
+}
+
+// spawnEventLine describes an event's effects and when it runs.
+func spawnEventLine(event game.SpawnEvent) string {
+	var effects []string
+	if len(event.BoostedCharacters) > 0 && event.BoostMultiplier > 1 {
+		names := make([]string, len(event.BoostedCharacters))
+		for i, id := range event.BoostedCharacters {
+			names[i] = (&game.Character{CharacterID: id}).CharacterName()
+		}
+		effects = append(effects, fmt.Sprintf("×%g %s", event.BoostMultiplier, strings.Join(names, ", ")))
+	}
+	if event.ShinyMultiplier > 1 {
+		effects = append(effects, fmt.Sprintf("×%g shiny rate", event.ShinyMultiplier))
+	}
+	if event.MinIV > 0 {
+		effects = append(effects, fmt.Sprintf("IVs of at least %d", event.MinIV))
+	}
+	if event.XPMultiplier > 1 {
+		effects = append(effects, fmt.Sprintf("×%g XP", event.XPMultiplier))
+	}
+	if len(effects) == 0 {
+		effects = append(effects, "No effects")
+	}
+
+	when := fmt.Sprintf("<t:%d:f> to <t:%d:f>", event.StartsAt.Unix(), event.EndsAt.Unix())
+	if event.Active(time.Now()) {
+		when = fmt.Sprintf("Running, ends <t:%d:R>", event.EndsAt.Unix())
+	}
+	if event.RecurEvery > 0 {
+		when += fmt.Sprintf(", repeats every %d day(s)", int(event.RecurEvery/(24*time.Hour)))
+	}
+
+	return fmt.Sprintf("**#%d %s**\n%s\n%s", event.ID, event.Name, strings.Join(effects, " • "), when)
+}
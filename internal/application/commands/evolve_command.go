@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+)
+
+func init() {
+	Commands["evolve"] = cmdEvolve
+}
+
+var cmdEvolve = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "evolve",
+		Description: "Evolve a character into its next form",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionString{
+				Name:         "character",
+				Description:  "The character to evolve",
+				Required:     true,
+				Autocomplete: true,
+			},
+		},
+	},
+	Handler:      HandleEvolve,
+	Autocomplete: handleGetCharacterAutocomplete,
+	Category:     "Friemon",
+}
+
+func HandleEvolve(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		characterID, err := uuid.Parse(e.SlashCommandInteractionData().String("character"))
+		if err != nil {
+			return e.CreateMessage(ErrorMessage("Select a valid character"))
+		}
+
+		character, evolution, err := services.PrepareEvolution(e.Ctx, b, e.User().ID, characterID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		return e.CreateMessage(discord.NewMessageCreateBuilder().
+			AddEmbeds(services.EvolutionEmbed(character, evolution)).
+			AddContainerComponents(services.EvolutionButtons(character.ID, evolution)).
+			Build())
+	}
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/internal/application/bot"
+)
+
+func init() {
+	Commands["fav"] = cmdFav
+}
+
+var cmdFav = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "fav",
+		Description: "Mark characters as favourites so they can't be released",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "toggle",
+				Description: "Favourite or unfavourite one character",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:         "character",
+						Description:  "The character to toggle",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "add",
+				Description: "Favourite every character matching the filters",
+				Options:     characterFilterOptions(),
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "remove",
+				Description: "Unfavourite every character matching the filters",
+				Options:     characterFilterOptions(),
+			},
+		},
+	},
+	Handler:      HandleFav,
+	Autocomplete: handleGetCharacterAutocomplete,
+	Category:     "Friemon",
+}
+
+func HandleFav(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+		if data.SubCommandName == nil {
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+
+		switch *data.SubCommandName {
+		case "toggle":
+			return handleFavToggle(b, e)
+		case "add":
+			return handleFavBulk(b, e, true)
+		case "remove":
+			return handleFavBulk(b, e, false)
+		default:
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+	}
+}
+
+func handleFavToggle(b *bot.Bot, e *handler.CommandEvent) error {
+	character, err := ownedCharacter(e, b, e.SlashCommandInteractionData().String("character"))
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	favourite := !character.Favourite
+	if err := b.DB.SetCharacterFavourite(e.Ctx, character.ID, favourite); err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	if favourite {
+		return e.CreateMessage(SuccessMessage("Favourite added", fmt.Sprintf("Your **%s No. %d** is now a favourite ❤️", character.Format("n"), character.IDX)))
+	}
+	return e.CreateMessage(SuccessMessage("Favourite removed", fmt.Sprintf("Your **%s No. %d** is no longer a favourite.", character.Format("n"), character.IDX)))
+}
+
+func handleFavBulk(b *bot.Bot, e *handler.CommandEvent, favourite bool) error {
+	filter := characterFilter(e.SlashCommandInteractionData())
+
+	changed, err := b.DB.SetFavourite(e.Ctx, e.User().ID, filter, favourite)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	if changed == 0 {
+		return e.CreateMessage(InfoMessage("No characters needed changing"))
+	}
+
+	if favourite {
+		return e.CreateMessage(SuccessMessage("Favourites added", fmt.Sprintf("Favourited **%d** characters ❤️", changed)))
+	}
+	return e.CreateMessage(SuccessMessage("Favourites removed", fmt.Sprintf("Unfavourited **%d** characters.", changed)))
+}
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+)
+
+func init() {
+	Commands["hint"] = cmdHint
+}
+
+var cmdHint = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "hint",
+		Description: "Reveal some letters of the wild character's name",
+		Contexts:    []discord.InteractionContextType{discord.InteractionContextTypeGuild},
+	},
+	Handler:  HandleHint,
+	Category: "Friemon",
+}
+
+func HandleHint(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		hint, err := services.SpawnHint(e.Ctx, b, *e.GuildID(), e.Channel().ID(), e.User().ID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		return e.CreateMessage(InfoMessage(fmt.Sprintf("The wild character is `%s`", hint)))
+	}
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func init() {
+	Commands["inventory"] = cmdInventory
+}
+
+var cmdInventory = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "inventory",
+		Description: "View your items",
+	},
+	Handler:  HandleInventory,
+	Category: "Friemon",
+}
+
+func HandleInventory(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		items, err := b.DB.GetInventory(e.Ctx, e.User().ID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+		if len(items) == 0 {
+			return e.CreateMessage(InfoMessage("Your inventory is empty, buy items with /shop"))
+		}
+
+		lines := make([]string, len(items))
+		for i, entry := range items {
+			item := entry.Item()
+			lines[i] = fmt.Sprintf("%s **%s** ×%d\n%s", item.Emoji, item.Name, entry.Quantity, item.Description)
+		}
+
+		embed := discord.NewEmbedBuilder().
+			SetTitle("Inventory").
+			SetDescription(strings.Join(lines, "\n\n")).
+			SetColor(constants.ColorDefault).
+			SetFooterText("Use items with /use").
+			Build()
+
+		return e.CreateMessage(discord.MessageCreate{Embeds: []discord.Embed{embed}})
+	}
+}
+
+// handleInventoryAutocomplete suggests items from the user's inventory.
+func handleInventoryAutocomplete(b *bot.Bot, e *handler.AutocompleteEvent) error {
+	items, err := b.DB.GetInventory(e.Ctx, e.User().ID)
+	if err != nil {
+		return e.AutocompleteResult([]discord.AutocompleteChoice{
+			discord.AutocompleteChoiceString{Name: "Error fetching your items", Value: "db_error"},
+		})
+	}
+
+	query := strings.ToLower(e.Data.String("item"))
+	choices := make([]discord.AutocompleteChoice, 0, len(items))
+	for _, entry := range items {
+		item := entry.Item()
+		if !strings.Contains(strings.ToLower(item.Name), query) {
+			continue
+		}
+		choices = append(choices, discord.AutocompleteChoiceString{
+			Name:  fmt.Sprintf("%s (×%d)", item.Name, entry.Quantity),
+			Value: item.Name,
+		})
+		if len(choices) == 25 {
+			break
+		}
+	}
+
+	if len(choices) == 0 {
+		choices = append(choices, discord.AutocompleteChoiceString{Name: "You don't have any matching items", Value: "no_items"})
+	}
+
+	return e.AutocompleteResult(choices)
+}
+
+// itemUseSummary describes what using an item did to a character.
+func itemUseSummary(item game.Item, character *game.Character, use game.ItemUse) string {
+	name := character.Format("ln")
+
+	switch {
+	case use.LevelUp.ToLevel > 0:
+		summary := fmt.Sprintf("Your **%s** grew to level **%d**!", name, character.Level)
+		for _, move := range use.LevelUp.Moves {
+			summary += fmt.Sprintf("\nIt %s.", move)
+		}
+		summary += fmt.Sprintf("\nYou earned **%d** coins", use.LevelUp.Coins)
+		for _, id := range use.LevelUp.Items {
+			reward := game.ItemsRegistry[id]
+			summary += fmt.Sprintf(", %s %s", reward.Emoji, reward.Name)
+		}
+		summary += "."
+		if use.LevelUp.Evolution != nil {
+			summary += fmt.Sprintf("\nIt's ready to evolve into **%s**!", use.LevelUp.Evolution.Form().Name)
+		}
+		return summary
+	case item.XP > 0:
+		return fmt.Sprintf("Your **%s** gained %d XP.", name, item.XP)
+	case item.RerollIVs:
+		return fmt.Sprintf("Your **%s** now has **%s** IVs.", name, character.IvPercentage())
+	case item.Category == game.ItemCategoryMint:
+		return fmt.Sprintf("Your **%s** now has the **%s** personality.", name, character.Personality)
+	case item.Category == game.ItemCategoryHeld:
+		summary := fmt.Sprintf("Your **%s** is now holding the **%s**.", name, item.Name)
+		if returned, ok := game.ItemsRegistry[use.ReturnedItem]; ok {
+			summary += fmt.Sprintf("\nIts **%s** went back to your inventory.", returned.Name)
+		}
+		return summary
+	default:
+		return fmt.Sprintf("You used a **%s** on your **%s**.", item.Name, name)
+	}
+}
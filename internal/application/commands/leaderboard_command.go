@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/paginator"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+const leaderboardPageSize = 10
+
+func init() {
+	Commands["leaderboard"] = cmdLeaderboard
+}
+
+var cmdLeaderboard = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "leaderboard",
+		Description: "View the top players.",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionString{
+				Name:        "category",
+				Description: "What players are ranked by.",
+				Choices:     leaderboardCategoryChoices(),
+			},
+			discord.ApplicationCommandOptionString{
+				Name:        "scope",
+				Description: "Rank everyone or only players in this server.",
+				Choices: []discord.ApplicationCommandOptionChoiceString{
+					{Name: "Global", Value: "global"},
+					{Name: "This server", Value: "server"},
+				},
+			},
+		},
+	},
+	Handler:  HandleLeaderboard,
+	Category: "Friemon",
+}
+
+func leaderboardCategoryChoices() []discord.ApplicationCommandOptionChoiceString {
+	choices := make([]discord.ApplicationCommandOptionChoiceString, 0, len(game.LeaderboardCategories))
+	for _, category := range game.LeaderboardCategories {
+		choices = append(choices, discord.ApplicationCommandOptionChoiceString{
+			Name:  category.String(),
+			Value: string(category),
+		})
+	}
+	return choices
+}
+
+func HandleLeaderboard(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+
+		category := game.LeaderboardRating
+		if c, ok := data.OptString("category"); ok {
+			category = game.LeaderboardCategory(c)
+		}
+
+		scope := "Global"
+		var guildID snowflake.ID
+		if data.String("scope") == "server" {
+			if e.GuildID() == nil {
+				return e.CreateMessage(ErrorMessage("Server leaderboards can only be viewed in a server."))
+			}
+			scope = "Server"
+			guildID = *e.GuildID()
+		}
+
+		entries, err := services.GetLeaderboard(e.Ctx, b, category, guildID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		title := fmt.Sprintf("%s Leaderboard: %s", scope, category)
+		if len(entries) == 0 {
+			return e.CreateMessage(InfoMessage(fmt.Sprintf("**%s**\nNobody is ranked yet!", title)))
+		}
+
+		footer := "You are not ranked yet"
+		own, err := services.GetLeaderboardRank(e.Ctx, b, category, guildID, e.User().ID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+		if own != nil {
+			footer = fmt.Sprintf("You are #%d with %s", own.Rank, category.FormatScore(own.Score))
+		}
+
+		pages := (len(entries) + leaderboardPageSize - 1) / leaderboardPageSize
+
+		return b.Paginator.Create(e.Respond, paginator.Pages{
+			ID: e.ID().String(),
+			PageFunc: func(page int, embed *discord.EmbedBuilder) {
+				start := page * leaderboardPageSize
+				end := min(start+leaderboardPageSize, len(entries))
+
+				lines := make([]string, 0, end-start)
+				for _, entry := range entries[start:end] {
+					lines = append(lines, fmt.Sprintf("**#%d** <@%s> • %s", entry.Rank, entry.UserID, category.FormatScore(entry.Score)))
+				}
+
+				embed.SetTitle(title).
+					SetDescription(strings.Join(lines, "\n")).
+					SetColor(constants.ColorInfo).
+					SetFooterTextf("%s • Page %d of %d", footer, page+1, pages)
+			},
+			Pages:      pages,
+			Creator:    e.User().ID,
+			ExpireMode: paginator.ExpireModeAfterLastUsage,
+		}, false)
+	}
+}
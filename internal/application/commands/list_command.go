@@ -7,6 +7,7 @@ import (
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
 	"github.com/disgoorg/paginator"
 	"github.com/theoreotm/friemon/constants"
 	"github.com/theoreotm/friemon/internal/application/bot"
@@ -25,13 +26,24 @@ var cmdList = &Command{
 	Cmd: discord.SlashCommandCreate{
 		Name:        "list",
 		Description: "Get a list of characters you own",
-		Options: []discord.ApplicationCommandOption{
-			discord.ApplicationCommandOptionString{
+		Options: append(append([]discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionInt{
 				Name:        "page",
 				Description: "The page you want to view",
 				Required:    false,
+				MinValue:    json.Ptr(1),
+			},
+		}, characterFilterOptions()...),
+			discord.ApplicationCommandOptionString{
+				Name:        "order",
+				Description: "What to sort by, defaults to your /order setting",
+				Choices:     orderChoices(),
 			},
-		},
+			discord.ApplicationCommandOptionBool{
+				Name:        "descending",
+				Description: "Sort from highest to lowest, defaults to your /order setting",
+			},
+		),
 	},
 	Handler:  HandleList,
 	Category: "Friemon",
@@ -39,63 +51,100 @@ var cmdList = &Command{
 
 func HandleList(b *bot.Bot) handler.CommandHandler {
 	return func(e *handler.CommandEvent) error {
-		characters, err := b.DB.GetCharactersForUser(e.Ctx, e.Member().User.ID)
+		data := e.SlashCommandInteractionData()
+		userID := e.User().ID
+
+		dbUser, err := b.DB.EnsureUser(e.Ctx, userID)
 		if err != nil {
 			return e.CreateMessage(ErrorMessage(err.Error()))
 		}
 
-		if len(characters) == 0 {
-			return e.CreateMessage(InfoMessage("You don't have any characters"))
+		filter := characterFilter(data)
+
+		order := dbUser.Order
+		if name, ok := data.OptString("order"); ok {
+			orderBy, ok := game.ParseOrderBy(name)
+			if !ok {
+				return e.CreateMessage(ErrorMessage("Unknown sort order"))
+			}
+			order.OrderBy = orderBy
+		}
+		if desc, ok := data.OptBool("descending"); ok {
+			order.Desc = desc
 		}
 
-		dbUser, err := b.DB.GetUser(e.Ctx, e.Member().User.ID)
+		total, err := b.DB.CountCharacters(e.Ctx, userID, filter)
 		if err != nil {
 			return e.CreateMessage(ErrorMessage(err.Error()))
 		}
 
-		return b.Paginator.Create(e.Respond, paginator.Pages{
-			ID: e.ID().String(),
-			PageFunc: func(page int, embed *discord.EmbedBuilder) {
-				characterStart := page * characterPerPage
-				characterEnd := characterStart + characterPerPage
+		if total == 0 {
+			if !filter.IsZero() {
+				return e.CreateMessage(InfoMessage("None of your characters match those filters"))
+			}
+			return e.CreateMessage(InfoMessage("You don't have any characters"))
+		}
 
-				if characterEnd > len(characters) {
-					characterEnd = len(characters)
+		pages := int(math.Ceil(float64(total) / characterPerPage))
+		pageFunc := func(page int, embed *discord.EmbedBuilder) {
+			characterStart := page * characterPerPage
+			charactersInPage, err := b.DB.ListCharacters(b.Context, userID, filter, order, characterStart, characterPerPage)
+			if err != nil {
+				embed.SetDescription("Failed to load characters")
+				return
+			}
+
+			highestIdx := maxIDX(charactersInPage)
+			if highestIdx == -1 {
+				embed.SetDescription("No characters found")
+				return
+			}
+
+			description := ""
+			for i := 0; i < len(charactersInPage); i++ {
+				character := charactersInPage[i]
+				idx := padIdx(character.IDX, len(fmt.Sprint(highestIdx)))
+				name := character.Format("inf")
+				if character.ID == dbUser.SelectedID {
+					idx = fmt.Sprintf("**`%v`**", idx)
+				} else {
+					idx = fmt.Sprintf("`%v`", idx)
 				}
 
-				charactersInPage := characters[characterStart:characterEnd]
-
-				highestIdx := maxIDX(charactersInPage)
-				if highestIdx == -1 {
-					embed.SetDescription("No characters found")
-					return
-				}
+				description += fmt.Sprintf("%v　%v　•　Lvl. %v　•　%v\n", idx, name, character.Level, character.IvPercentage())
+			}
+			embed.SetDescription(description)
+			embed.SetColor(constants.ColorDefault)
+			embed.SetFooterTextf("Showing entries %v-%v out of %v • Page %v of %v • Sorted by %s",
+				characterStart+1, characterStart+len(charactersInPage), total, page+1, pages, order)
+		}
 
-				description := ""
-				for i := 0; i < len(charactersInPage); i++ {
-					character := charactersInPage[i]
-					idx := padIdx(character.IDX, len(fmt.Sprint(highestIdx)))
-					name := character.Format("inf")
-					if character.ID == dbUser.SelectedID {
-						idx = fmt.Sprintf("**`%v`**", idx)
-					} else {
-						idx = fmt.Sprintf("`%v`", idx)
-					}
-
-					description += fmt.Sprintf("%v　%v　•　Lvl. %v　•　%v\n", idx, name, character.Level, character.IvPercentage())
-				}
-				embed.SetDescription(description)
-				embed.SetColor(constants.ColorDefault)
-				embed.SetFooterTextf("Showing entries %v-%v out of %v", characterStart+1, characterEnd, len(characters))
-			},
+		// The paginator always opens on its first page, so a requested page
+		// is shown on its own
+		if page, ok := data.OptInt("page"); ok {
+			embed := discord.NewEmbedBuilder()
+			pageFunc(min(page, pages)-1, embed)
+			return e.CreateMessage(discord.MessageCreate{Embeds: []discord.Embed{embed.Build()}})
+		}
 
-			Pages:      int(math.Ceil(float64(len(characters)) / characterPerPage)),
+		return b.Paginator.Create(e.Respond, paginator.Pages{
+			ID:         e.ID().String(),
+			PageFunc:   pageFunc,
+			Pages:      pages,
 			Creator:    e.User().ID,
 			ExpireMode: paginator.ExpireModeAfterLastUsage,
 		}, false)
 	}
 }
 
+func orderChoices() []discord.ApplicationCommandOptionChoiceString {
+	return []discord.ApplicationCommandOptionChoiceString{
+		{Name: "Number", Value: game.OrderByName(game.OrderByIDX)},
+		{Name: "IV", Value: game.OrderByName(game.OrderByIV)},
+		{Name: "Level", Value: game.OrderByName(game.OrderByLevel)},
+	}
+}
+
 func maxIDX(characters []game.Character) int {
 	if len(characters) == 0 {
 		return -1
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func init() {
+	Commands["lure"] = cmdLure
+}
+
+var cmdLure = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "lure",
+		Description: "Place a lure to make a character spawn here soon",
+		Contexts:    []discord.InteractionContextType{discord.InteractionContextTypeGuild},
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionInt{
+				Name:        "item",
+				Description: "The lure to place",
+				Required:    true,
+				Choices:     lureChoices(),
+			},
+		},
+	},
+	Handler:  HandleLure,
+	Category: "Friemon",
+}
+
+func HandleLure(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		item, ok := game.ItemsRegistry[e.SlashCommandInteractionData().Int("item")]
+		if !ok {
+			return e.CreateMessage(ErrorMessage("Select a valid lure"))
+		}
+
+		channelID, err := services.UseLure(e.Ctx, b, e.User().ID, *e.GuildID(), e.Channel().ID(), item)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		return e.CreateMessage(SuccessMessage(
+			fmt.Sprintf("Placed %s %s", item.Emoji, item.Name),
+			fmt.Sprintf("A character will spawn in <#%s> within %d messages. If nothing spawns in time, you get the lure back.", channelID, item.LureMessages),
+		))
+	}
+}
+
+func lureChoices() []discord.ApplicationCommandOptionChoiceInt {
+	var choices []discord.ApplicationCommandOptionChoiceInt
+	for _, item := range game.ItemsRegistry {
+		if item.Category == game.ItemCategoryLure {
+			choices = append(choices, discord.ApplicationCommandOptionChoiceInt{Name: item.Name, Value: item.ID})
+		}
+	}
+	sort.Slice(choices, func(i, j int) bool {
+		return choices[i].Value < choices[j].Value
+	})
+	return choices
+}
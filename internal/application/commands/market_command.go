@@ -0,0 +1,222 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+	"github.com/disgoorg/paginator"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+const marketPageSize = 10
+
+func init() {
+	Commands["market"] = cmdMarket
+}
+
+var cmdMarket = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "market",
+		Description: "Buy and sell characters on the global market",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "list",
+				Description: "Put one of your characters up for sale",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:         "character",
+						Description:  "The character to sell",
+						Required:     true,
+						Autocomplete: true,
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "price",
+						Description: "The price in coins",
+						Required:    true,
+						MinValue:    json.Ptr(1),
+						MaxValue:    json.Ptr(game.MaxMarketPrice),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "browse",
+				Description: "Browse characters for sale",
+				Options: append(characterFilterOptions(),
+					discord.ApplicationCommandOptionInt{
+						Name:        "min_price",
+						Description: "Lowest price to include",
+						MinValue:    json.Ptr(1),
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "max_price",
+						Description: "Highest price to include",
+						MinValue:    json.Ptr(1),
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        "order",
+						Description: "How listings are sorted, cheapest first by default",
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Cheapest", Value: game.MarketOrderPriceAsc.String()},
+							{Name: "Most expensive", Value: game.MarketOrderPriceDesc.String()},
+							{Name: "Newest", Value: game.MarketOrderNewest.String()},
+							{Name: "Highest IV", Value: game.MarketOrderIV.String()},
+							{Name: "Highest level", Value: game.MarketOrderLevel.String()},
+						},
+					},
+					discord.ApplicationCommandOptionBool{
+						Name:        "mine",
+						Description: "Only show your own listings",
+					},
+				),
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "buy",
+				Description: "Buy a character from the market",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name:        "id",
+						Description: "The listing ID",
+						Required:    true,
+						MinValue:    json.Ptr(1),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "unlist",
+				Description: "Take one of your listings off the market",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name:        "id",
+						Description: "The listing ID",
+						Required:    true,
+						MinValue:    json.Ptr(1),
+					},
+				},
+			},
+		},
+	},
+	Handler:      HandleMarket,
+	Autocomplete: handleGetCharacterAutocomplete,
+	Category:     "Friemon",
+}
+
+func HandleMarket(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+		if data.SubCommandName == nil {
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+
+		switch *data.SubCommandName {
+		case "list":
+			return handleMarketList(b, e)
+		case "browse":
+			return handleMarketBrowse(b, e)
+		case "buy":
+			return handleMarketBuy(b, e)
+		case "unlist":
+			return handleMarketUnlist(b, e)
+		default:
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+	}
+}
+
+func handleMarketList(b *bot.Bot, e *handler.CommandEvent) error {
+	data := e.SlashCommandInteractionData()
+
+	characterID, err := uuid.Parse(data.String("character"))
+	if err != nil {
+		return e.CreateMessage(ErrorMessage("Select a valid character"))
+	}
+
+	listing, err := services.ListCharacter(e.Ctx, b, e.User().ID, characterID, data.Int("price"))
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(SuccessMessage("Character listed", fmt.Sprintf(
+		"Your **%s No. %d** is listed as **#%d** for **%d coins**.\nYou'll receive **%d coins** after the %d%% market fee if it sells before <t:%d:R>.",
+		listing.Character.Format("ln"), listing.Character.IDX, listing.ID, listing.Price,
+		listing.SellerProceeds(), game.MarketFeePercent, listing.ExpiresAt.Unix(),
+	)))
+}
+
+func handleMarketBrowse(b *bot.Bot, e *handler.CommandEvent) error {
+	data := e.SlashCommandInteractionData()
+
+	filter := game.MarketFilter{
+		CharacterFilter: characterFilter(data),
+		MinPrice:        data.Int("min_price"),
+		MaxPrice:        data.Int("max_price"),
+	}
+	if order, ok := game.ParseMarketOrder(data.String("order")); ok {
+		filter.Order = order
+	}
+	if data.Bool("mine") {
+		filter.SellerID = e.User().ID
+	}
+
+	total, err := b.DB.CountListings(e.Ctx, filter)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+	if total == 0 {
+		return e.CreateMessage(InfoMessage("No listings match those filters"))
+	}
+
+	pages := int(math.Ceil(float64(total) / marketPageSize))
+
+	return b.Paginator.Create(e.Respond, paginator.Pages{
+		ID: e.ID().String(),
+		PageFunc: func(page int, embed *discord.EmbedBuilder) {
+			listings, err := b.DB.ListListings(b.Context, filter, page*marketPageSize, marketPageSize)
+			if err != nil {
+				embed.SetDescription("Failed to load listings")
+				return
+			}
+
+			lines := make([]string, len(listings))
+			for i, listing := range listings {
+				lines[i] = fmt.Sprintf("`#%d`　%s　•　Lvl. %d　•　%s　•　**%d** coins",
+					listing.ID, listing.Character.Format("i"), listing.Character.Level, listing.Character.IvPercentage(), listing.Price)
+			}
+
+			embed.SetTitle("Market").
+				SetDescription(strings.Join(lines, "\n")).
+				SetColor(constants.ColorDefault).
+				SetFooterTextf("Buy with /market buy • Page %d of %d • %d listings", page+1, pages, total)
+		},
+		Pages:      pages,
+		Creator:    e.User().ID,
+		ExpireMode: paginator.ExpireModeAfterLastUsage,
+	}, false)
+}
+
+func handleMarketBuy(b *bot.Bot, e *handler.CommandEvent) error {
+	listing, err := services.BuyListing(e.Ctx, b, e.User().ID, e.SlashCommandInteractionData().Int("id"))
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(SuccessMessage("Purchase complete", fmt.Sprintf("You bought **%s** from <@%s> for **%d coins**.",
+		listing.Character.Format("l"), listing.SellerID, listing.Price)))
+}
+
+func handleMarketUnlist(b *bot.Bot, e *handler.CommandEvent) error {
+	listing, err := services.UnlistCharacter(e.Ctx, b, e.User().ID, e.SlashCommandInteractionData().Int("id"))
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(SuccessMessage("Listing removed", fmt.Sprintf("Your **%s No. %d** is back in your collection.",
+		listing.Character.Format("ln"), listing.Character.IDX)))
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+const maxNicknameLength = 32
+
+func init() {
+	Commands["nick"] = cmdNick
+}
+
+var cmdNick = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "nick",
+		Description: "Give one of your characters a nickname",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionString{
+				Name:         "character",
+				Description:  "The character to nickname",
+				Required:     true,
+				Autocomplete: true,
+			},
+			discord.ApplicationCommandOptionString{
+				Name:        "nickname",
+				Description: "The new nickname, leave empty to remove it",
+				MaxLength:   json.Ptr(maxNicknameLength),
+			},
+		},
+	},
+	Handler:      HandleNick,
+	Autocomplete: handleGetCharacterAutocomplete,
+	Category:     "Friemon",
+}
+
+func HandleNick(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+
+		character, err := ownedCharacter(e, b, data.String("character"))
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		nickname := strings.TrimSpace(data.String("nickname"))
+		if len([]rune(nickname)) > maxNicknameLength {
+			return e.CreateMessage(ErrorMessage(fmt.Sprintf("Nicknames can be at most %d characters long", maxNicknameLength)))
+		}
+
+		if err := b.DB.SetNickname(e.Ctx, character.ID, nickname); err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		if nickname == "" {
+			return e.CreateMessage(SuccessMessage("Nickname removed", fmt.Sprintf("Your **%s No. %d** no longer has a nickname.", character.CharacterName(), character.IDX)))
+		}
+
+		return e.CreateMessage(SuccessMessage("Nickname changed", fmt.Sprintf("Your **%s No. %d** is now called **%s**.", character.CharacterName(), character.IDX, nickname)))
+	}
+}
+
+// ownedCharacter looks up a character picked with handleGetCharacterAutocomplete
+// and makes sure it belongs to the user running the command.
+func ownedCharacter(e *handler.CommandEvent, b *bot.Bot, id string) (*game.Character, error) {
+	characterID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("Select a valid character")
+	}
+
+	character, err := b.DB.GetCharacter(e.Ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if character == nil || character.OwnerID != e.User().ID.String() {
+		return nil, fmt.Errorf("You don't own that character")
+	}
+
+	return character, nil
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func init() {
+	Commands["order"] = cmdOrder
+}
+
+var cmdOrder = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "order",
+		Description: "Change how /list sorts your characters by default",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionString{
+				Name:        "by",
+				Description: "What to sort by",
+				Required:    true,
+				Choices:     orderChoices(),
+			},
+			discord.ApplicationCommandOptionBool{
+				Name:        "descending",
+				Description: "Sort from highest to lowest",
+			},
+		},
+	},
+	Handler:  HandleOrder,
+	Category: "Friemon",
+}
+
+func HandleOrder(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+
+		orderBy, ok := game.ParseOrderBy(data.String("by"))
+		if !ok {
+			return e.CreateMessage(ErrorMessage("Unknown sort order"))
+		}
+		order := game.OrderOptions{OrderBy: orderBy, Desc: data.Bool("descending")}
+
+		if _, err := b.DB.EnsureUser(e.Ctx, e.User().ID); err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		if err := b.DB.UpdateOrder(e.Ctx, e.User().ID, order); err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		return e.CreateMessage(SuccessMessage("Order updated", fmt.Sprintf("`/list` now sorts your characters by %s.", order)))
+	}
+}
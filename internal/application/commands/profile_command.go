@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func init() {
+	Commands["profile"] = cmdProfile
+}
+
+var cmdProfile = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "profile",
+		Description: "View a player's progress.",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionUser{
+				Name:        "user",
+				Description: "The player to view, yourself if empty.",
+			},
+		},
+	},
+	Handler:  HandleProfile,
+	Category: "Friemon",
+}
+
+func HandleProfile(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		target := e.User()
+		if u, ok := e.SlashCommandInteractionData().OptUser("user"); ok {
+			target = u
+		}
+
+		user, err := b.DB.GetUser(e.Ctx, target.ID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+		if user == nil {
+			return e.CreateMessage(ErrorMessage(fmt.Sprintf("%s hasn't started playing yet!", target.Username)))
+		}
+
+		collection, err := b.DB.GetCollectionStats(e.Ctx, user.ID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		badges, err := b.DB.GetUserBadges(e.Ctx, user.ID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		season, err := services.CurrentSeason(e.Ctx, b)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		rating := fmt.Sprintf("%d", user.ELO)
+		standing, err := b.DB.GetSeasonStanding(e.Ctx, season.ID, user.ID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+		if standing != nil {
+			rank, err := b.DB.GetSeasonRank(e.Ctx, season.ID, standing.Rating)
+			if err != nil {
+				return e.CreateMessage(ErrorMessage(err.Error()))
+			}
+
+			bracket := game.BracketForRating(standing.Rating)
+			rating += fmt.Sprintf("\n%s %s • #%d in Season %d", bracket.Emoji(), bracket, rank, season.Number)
+		}
+
+		embed := discord.NewEmbedBuilder().
+			SetTitlef("%s's Profile", target.Username).
+			SetThumbnail(target.EffectiveAvatarURL()).
+			SetColor(constants.ColorInfo).
+			AddField("Balance", fmt.Sprintf("%d coins", user.Balance), true).
+			AddField("Rating", rating, true).
+			AddField("Battles", fmt.Sprintf("%dW / %dL", user.BattlesWon, user.BattlesLost), true).
+			AddField("Characters", fmt.Sprintf("%d (%d favourites)", collection.Characters, collection.Favourites), true).
+			AddField("Shinies Caught", fmt.Sprint(user.ShiniesCaught), true).
+			AddField("Total Levels", fmt.Sprint(collection.TotalLevels), true)
+
+		if selected, err := b.DB.GetSelectedCharacter(e.Ctx, user.ID); err == nil && selected != nil {
+			embed.AddField("Selected Character", fmt.Sprintf("%s **%s** • Level %d • %s IV",
+				selected.Sprite(), selected.CharacterName(), selected.Level, selected.IvPercentage()), false)
+		}
+
+		if len(badges) > 0 {
+			lines := make([]string, len(badges))
+			for i, badge := range badges {
+				lines[i] = badge.String()
+			}
+			embed.AddField("Badges", services.TruncateField(strings.Join(lines, "\n")), false)
+		}
+
+		if !user.JoinedAt.IsZero() {
+			embed.SetFooterTextf("Playing since %s", user.JoinedAt.Format("January 2, 2006"))
+		}
+
+		return e.CreateMessage(discord.MessageCreate{
+			Embeds: []discord.Embed{embed.Build()},
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+const releasePreviewSize = 10 // Characters listed in a release confirmation
+
+func init() {
+	Commands["release"] = cmdRelease
+}
+
+var cmdRelease = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "release",
+		Description: "Release characters in exchange for coins",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "character",
+				Description: "Release one character",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:         "character",
+						Description:  "The character to release",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "all",
+				Description: "Release every character matching the filters, except favourites and your selected one",
+				Options:     characterFilterOptions(),
+			},
+		},
+	},
+	Handler:      HandleRelease,
+	Autocomplete: handleGetCharacterAutocomplete,
+	Category:     "Friemon",
+}
+
+func HandleRelease(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+		if data.SubCommandName == nil {
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+
+		var release *game.PendingRelease
+		var characters []game.Character
+		var err error
+		switch *data.SubCommandName {
+		case "character":
+			release, characters, err = releaseCharacter(b, e)
+		case "all":
+			release, characters, err = services.PrepareBulkRelease(e.Ctx, b, e.User().ID, characterFilter(data))
+		default:
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		lines := make([]string, 0, releasePreviewSize+1)
+		for i, character := range characters {
+			if i == releasePreviewSize {
+				lines = append(lines, fmt.Sprintf("…and %d more", len(characters)-releasePreviewSize))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("`%d`　%s　•　Lvl. %d　•　%s", character.IDX, character.Format("in"), character.Level, character.IvPercentage()))
+		}
+
+		embed := discord.NewEmbedBuilder().
+			SetTitlef("Release %d character(s)?", len(characters)).
+			SetDescription(strings.Join(lines, "\n")).
+			SetColor(constants.ColorWarn).
+			AddField("Reward", fmt.Sprintf("%d coins", release.Value), true).
+			SetFooterTextf("This can't be undone. Expires in %s.", services.ReleaseConfirmTimeout)
+
+		return e.CreateMessage(discord.NewMessageCreateBuilder().
+			AddEmbeds(embed.Build()).
+			AddActionRow(
+				discord.NewDangerButton("Release", fmt.Sprintf("/release_confirm/%s", release.ID)),
+				discord.NewSecondaryButton("Cancel", fmt.Sprintf("/release_cancel/%s", release.ID)),
+			).
+			Build())
+	}
+}
+
+func releaseCharacter(b *bot.Bot, e *handler.CommandEvent) (*game.PendingRelease, []game.Character, error) {
+	character, err := ownedCharacter(e, b, e.SlashCommandInteractionData().String("character"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return services.PrepareRelease(e.Ctx, b, e.User().ID, character.ID)
+}
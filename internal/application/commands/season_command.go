@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func init() {
+	Commands["season"] = cmdSeason
+}
+
+var cmdSeason = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "season",
+		Description: "View the current ranked season and your bracket.",
+	},
+	Handler:  HandleSeason,
+	Category: "Battle",
+}
+
+func HandleSeason(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		season, err := services.CurrentSeason(e.Ctx, b)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		standing, err := b.DB.GetSeasonStanding(e.Ctx, season.ID, e.User().ID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		embed := discord.NewEmbedBuilder().
+			SetTitlef("Ranked Season %d", season.Number).
+			SetDescriptionf("Ends <t:%d:R> (<t:%d:f>)", season.EndsAt.Unix(), season.EndsAt.Unix()).
+			SetColor(constants.ColorInfo)
+
+		if standing == nil {
+			embed.AddField("Your Standing", "You haven't played a ranked battle this season. Use `/battle queue` to get started!", false)
+		} else {
+			rank, err := b.DB.GetSeasonRank(e.Ctx, season.ID, standing.Rating)
+			if err != nil {
+				return e.CreateMessage(ErrorMessage(err.Error()))
+			}
+
+			bracket := game.BracketForRating(standing.Rating)
+			embed.AddField("Bracket", fmt.Sprintf("%s %s", bracket.Emoji(), bracket), true).
+				AddField("Rating", fmt.Sprintf("%d (peak %d)", standing.Rating, standing.PeakRating), true).
+				AddField("Rank", fmt.Sprintf("#%d", rank), true).
+				AddField("Record", fmt.Sprintf("%dW / %dL / %dD", standing.Wins, standing.Losses, standing.Draws), true)
+
+			if remaining := game.MinSeasonGames - standing.Games(); remaining > 0 {
+				embed.AddField("Rewards", fmt.Sprintf("Play %d more ranked battles to qualify for rewards.", remaining), true)
+			}
+		}
+
+		embed.AddField("Season Rewards", seasonRewardsDescription(), false).
+			SetFooterTextf("Ratings are soft reset toward %d when the season ends", game.DefaultRating)
+
+		return e.CreateMessage(discord.MessageCreate{
+			Embeds: []discord.Embed{embed.Build()},
+		})
+	}
+}
+
+func seasonRewardsDescription() string {
+	brackets := []game.Bracket{game.BracketDiamond, game.BracketPlatinum, game.BracketGold, game.BracketSilver}
+
+	lines := make([]string, 0, len(brackets))
+	for _, bracket := range brackets {
+		reward := bracket.Reward()
+
+		parts := []string{fmt.Sprintf("%d coins", reward.Balance)}
+		if reward.Shiny {
+			parts = append(parts, "exclusive shiny")
+		}
+		if reward.Badge {
+			parts = append(parts, "season badge")
+		}
+
+		lines = append(lines, fmt.Sprintf("%s **%s**: %s", bracket.Emoji(), bracket, strings.Join(parts, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/disgoorg/disgo/handler"
 	"github.com/google/uuid"
 	"github.com/theoreotm/friemon/internal/application/bot"
-	"github.com/theoreotm/friemon/internal/core/game"
 )
 
 func init() {
@@ -44,10 +43,16 @@ func handleSelect(b *bot.Bot) handler.CommandHandler {
 			return e.CreateMessage(ErrorMessage(err.Error()))
 		}
 
-		_, err = b.DB.UpdateUser(b.Context, game.User{
-			ID:         e.Member().User.ID,
-			SelectedID: targetChar.ID,
-		})
+		if targetChar.Locked {
+			return e.CreateMessage(ErrorMessage("That character is up for sale on the market or in an auction"))
+		}
+
+		user, err := b.DB.EnsureUser(e.Ctx, e.Member().User.ID)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		err = b.DB.SetSelectedCharacter(e.Ctx, user.ID, targetChar.ID)
 
 		if err != nil {
 			return e.CreateMessage(ErrorMessage(err.Error()))
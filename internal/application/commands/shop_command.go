@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func init() {
+	Commands["shop"] = cmdShop
+}
+
+var cmdShop = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "shop",
+		Description: "Spend your coins on items",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "browse",
+				Description: "See what the shop sells",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "buy",
+				Description: "Buy an item",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name:        "item",
+						Description: "The item to buy",
+						Required:    true,
+						Choices:     shopItemChoices(),
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "quantity",
+						Description: "How many to buy, 1 by default",
+						MinValue:    json.Ptr(1),
+						MaxValue:    json.Ptr(game.MaxShopQuantity),
+					},
+				},
+			},
+		},
+	},
+	Handler:  HandleShop,
+	Category: "Friemon",
+}
+
+func HandleShop(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+		if data.SubCommandName == nil {
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+
+		switch *data.SubCommandName {
+		case "browse":
+			return handleShopBrowse(b, e)
+		case "buy":
+			return handleShopBuy(b, e)
+		default:
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+	}
+}
+
+func handleShopBrowse(b *bot.Bot, e *handler.CommandEvent) error {
+	embed := discord.NewEmbedBuilder().
+		SetTitle("Shop").
+		SetColor(constants.ColorDefault).
+		SetFooterText("Buy with /shop buy")
+
+	for _, item := range game.ShopItems() {
+		embed.AddField(fmt.Sprintf("%s %s • %d coins", item.Emoji, item.Name, item.Price),
+			fmt.Sprintf("*%s*\n%s", item.Category, item.Description), false)
+	}
+
+	if user, err := b.DB.GetUser(e.Ctx, e.User().ID); err == nil && user != nil {
+		embed.SetDescription(fmt.Sprintf("You have **%d coins**.", user.Balance))
+	}
+
+	return e.CreateMessage(discord.MessageCreate{Embeds: []discord.Embed{embed.Build()}})
+}
+
+func handleShopBuy(b *bot.Bot, e *handler.CommandEvent) error {
+	data := e.SlashCommandInteractionData()
+
+	item, ok := game.ItemsRegistry[data.Int("item")]
+	if !ok {
+		return e.CreateMessage(ErrorMessage("That item doesn't exist"))
+	}
+
+	quantity, ok := data.OptInt("quantity")
+	if !ok {
+		quantity = 1
+	}
+
+	price, err := services.BuyItem(e.Ctx, b, e.User().ID, item, quantity)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(SuccessMessage("Purchase complete", fmt.Sprintf("You bought **%dx %s %s** for **%d coins**.",
+		quantity, item.Emoji, item.Name, price)))
+}
+
+func shopItemChoices() []discord.ApplicationCommandOptionChoiceInt {
+	items := game.ShopItems()
+	choices := make([]discord.ApplicationCommandOptionChoiceInt, len(items))
+	for i, item := range items {
+		choices[i] = discord.ApplicationCommandOptionChoiceInt{
+			Name:  fmt.Sprintf("%s (%d coins)", item.Name, item.Price),
+			Value: item.ID,
+		}
+	}
+	return choices
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+const (
+	defaultSpawnSamples = 100000
+	maxSpawnSamples     = 1000000
+)
+
+func init() {
+	Commands["spawnstats"] = cmdSpawnStats
+}
+
+var cmdSpawnStats = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "spawnstats",
+		Description: "Simulate spawns and compare them to the configured rarity weights",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionInt{
+				Name:        "samples",
+				Description: "How many spawns to simulate",
+				MinValue:    json.Ptr(1000),
+				MaxValue:    json.Ptr(maxSpawnSamples),
+			},
+		},
+	},
+	Handler:  HandleSpawnStats,
+	Category: "Bot",
+}
+
+func HandleSpawnStats(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		if !slices.Contains(b.Cfg.Bot.AdminUsers, e.User().ID) {
+			return e.CreateMessage(ErrorMessage("Only bot admins can run this command"))
+		}
+
+		samples, ok := e.SlashCommandInteractionData().OptInt("samples")
+		if !ok {
+			samples = defaultSpawnSamples
+		}
+
+		counts := map[int]int{}
+		for i := 0; i < samples; i++ {
+			counts[game.RandomBaseCharacter().ID]++
+		}
+
+		expected := game.SpawnChances()
+		characters := make([]game.BaseCharacter, 0, len(game.Characters))
+		for _, character := range game.Characters {
+			if !character.IsForm() {
+				characters = append(characters, character)
+			}
+		}
+		sort.Slice(characters, func(i, j int) bool {
+			if characters[i].Rarity != characters[j].Rarity {
+				return characters[i].Rarity < characters[j].Rarity
+			}
+			return characters[i].ID < characters[j].ID
+		})
+
+		lines := make([]string, len(characters))
+		for i, character := range characters {
+			observed := float64(counts[character.ID]) / float64(samples)
+			lines[i] = fmt.Sprintf("`%-9s` **%s**　%.2f%% (expected %.2f%%)",
+				character.Rarity, character.Name, observed*100, expected[character.ID]*100)
+		}
+
+		embed := discord.NewEmbedBuilder().
+			SetTitle("Spawn Distribution").
+			SetDescription(strings.Join(lines, "\n")).
+			SetColor(constants.ColorInfo).
+			SetFooterTextf("%d simulated spawns", samples).
+			Build()
+
+		return e.CreateMessage(discord.MessageCreate{Embeds: []discord.Embed{embed}, Flags: discord.MessageFlagEphemeral})
+	}
+}
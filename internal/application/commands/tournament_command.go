@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func init() {
+	Commands["tournament"] = cmdTournament
+}
+
+var cmdTournament = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "tournament",
+		Description: "Run and take part in community tournaments.",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "create",
+				Description: "Open sign-ups for a tournament in this channel.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:        "name",
+						Description: "The name of the tournament.",
+						Required:    true,
+						MaxLength:   json.Ptr(50),
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        "format",
+						Description: "How players are paired each round.",
+						Required:    true,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Single Elimination", Value: "single"},
+							{Name: "Double Elimination", Value: "double"},
+							{Name: "Swiss", Value: "swiss"},
+						},
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "signup_minutes",
+						Description: "How long sign-ups stay open.",
+						Required:    true,
+						MinValue:    json.Ptr(5),
+						MaxValue:    json.Ptr(7 * 24 * 60),
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        "preset",
+						Description: "The battle settings matches are played with.",
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Standard (3v3)", Value: "standard"},
+							{Name: "Quick (1v1, 15 turns)", Value: "quick"},
+							{Name: "Level 50 cap", Value: "level50"},
+						},
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "join",
+				Description: "Sign up for the tournament in this channel.",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "leave",
+				Description: "Withdraw from the tournament in this channel before it starts.",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "view",
+				Description: "View the bracket of the tournament in this channel.",
+			},
+		},
+	},
+	Handler:  HandleTournament,
+	Category: "Battle",
+}
+
+func HandleTournament(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+		if data.SubCommandName == nil {
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+
+		if e.GuildID() == nil {
+			return e.CreateMessage(ErrorMessage("Tournaments can only be run in servers."))
+		}
+
+		switch *data.SubCommandName {
+		case "create":
+			return handleTournamentCreate(b, e)
+		case "join":
+			return handleTournamentJoin(b, e)
+		case "leave":
+			return handleTournamentLeave(b, e)
+		case "view":
+			return handleTournamentView(b, e)
+		default:
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+	}
+}
+
+func handleTournamentCreate(b *bot.Bot, e *handler.CommandEvent) error {
+	data := e.SlashCommandInteractionData()
+
+	if member := e.Member(); member == nil || !member.Permissions.Has(discord.PermissionManageGuild) {
+		return e.CreateMessage(ErrorMessage("You need the Manage Server permission to create tournaments."))
+	}
+
+	format, err := game.ParseTournamentFormat(data.String("format"))
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	preset := "standard"
+	if p, ok := data.OptString("preset"); ok {
+		preset = p
+	}
+
+	deadline := time.Now().Add(time.Duration(data.Int("signup_minutes")) * time.Minute)
+
+	tournament, err := game.NewTournament(data.String("name"), format, preset, *e.GuildID(), e.Channel().ID(), e.User().ID, deadline)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	if err := services.CreateTournament(e.Ctx, b, tournament); err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(SuccessMessage(
+		fmt.Sprintf("🏆 %s", tournament.Name),
+		fmt.Sprintf("%s tournament with %s settings. Sign up with `/tournament join`, sign-ups close <t:%d:R>. Follow the bracket in <#%s>.",
+			tournament.Format, tournament.Preset, deadline.Unix(), tournament.ThreadID),
+	))
+}
+
+func handleTournamentJoin(b *bot.Bot, e *handler.CommandEvent) error {
+	tournament, err := b.DB.GetChannelTournament(e.Ctx, e.Channel().ID())
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+	if tournament == nil || tournament.State != game.TournamentStateSignUp {
+		return e.CreateMessage(ErrorMessage("There is no tournament taking sign-ups in this channel."))
+	}
+
+	tournament, err = services.JoinTournament(e.Ctx, b, tournament.ID, e.User().ID)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(SuccessMessage("Signed up!", fmt.Sprintf(
+		"You're in **%s** (%d players). Matches start <t:%d:R>, keep an eye on <#%s>.",
+		tournament.Name, len(tournament.Participants), tournament.SignupDeadline.Unix(), tournament.ThreadID,
+	)))
+}
+
+func handleTournamentLeave(b *bot.Bot, e *handler.CommandEvent) error {
+	tournament, err := b.DB.GetChannelTournament(e.Ctx, e.Channel().ID())
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+	if tournament == nil {
+		return e.CreateMessage(ErrorMessage("There is no tournament in this channel."))
+	}
+
+	tournament, err = services.LeaveTournament(e.Ctx, b, tournament.ID, e.User().ID)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(InfoMessage(fmt.Sprintf("You have left **%s**.", tournament.Name)))
+}
+
+func handleTournamentView(b *bot.Bot, e *handler.CommandEvent) error {
+	tournament, err := b.DB.GetChannelTournament(e.Ctx, e.Channel().ID())
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+	if tournament == nil {
+		return e.CreateMessage(ErrorMessage("There is no tournament in this channel."))
+	}
+
+	return e.CreateMessage(discord.MessageCreate{
+		Embeds: []discord.Embed{services.TournamentEmbed(tournament)},
+	})
+}
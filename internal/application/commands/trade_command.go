@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+)
+
+func init() {
+	Commands["trade"] = cmdTrade
+}
+
+var cmdTrade = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "trade",
+		Description: "Trade characters and coins with another player",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "start",
+				Description: "Open a trade with another player",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionUser{
+						Name:        "user",
+						Description: "The player to trade with",
+						Required:    true,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "add",
+				Description: "Offer one of your characters",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:         "character",
+						Description:  "The character to offer",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "remove",
+				Description: "Take one of your characters out of the trade",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:         "character",
+						Description:  "The character to take back",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "coins",
+				Description: "Set how many coins you offer",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name:        "amount",
+						Description: "The number of coins, 0 to offer none",
+						Required:    true,
+						MinValue:    json.Ptr(0),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "confirm",
+				Description: "Accept the current offer",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "cancel",
+				Description: "Close the trade without exchanging anything",
+			},
+		},
+	},
+	Handler:      HandleTrade,
+	Autocomplete: handleGetCharacterAutocomplete,
+	Category:     "Friemon",
+}
+
+func HandleTrade(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+		if data.SubCommandName == nil {
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+
+		switch *data.SubCommandName {
+		case "start":
+			return handleTradeStart(b, e)
+		case "add":
+			return handleTradeAdd(b, e)
+		case "remove":
+			return handleTradeRemove(b, e)
+		case "coins":
+			return handleTradeCoins(b, e)
+		case "confirm":
+			return handleTradeConfirm(b, e)
+		case "cancel":
+			return handleTradeCancel(b, e)
+		default:
+			return e.CreateMessage(ErrorMessage("Unknown subcommand"))
+		}
+	}
+}
+
+func handleTradeStart(b *bot.Bot, e *handler.CommandEvent) error {
+	partner := e.SlashCommandInteractionData().User("user")
+	if partner.Bot {
+		return e.CreateMessage(ErrorMessage("You can't trade with bots"))
+	}
+
+	trade, err := services.OpenTrade(e.Ctx, b, e.User().ID, partner.ID, e.Channel().ID())
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	err = e.CreateMessage(discord.MessageCreate{
+		Content:    partner.Mention(),
+		Embeds:     []discord.Embed{services.TradeEmbed(trade, constants.ColorInfo)},
+		Components: services.TradeComponents(trade),
+	})
+	if err != nil {
+		b.TradeManager.Finish(trade.ID)
+		return err
+	}
+
+	if message, err := e.GetInteractionResponse(); err == nil {
+		b.TradeManager.SetMessage(trade.ID, message.ID)
+	}
+
+	return nil
+}
+
+func handleTradeAdd(b *bot.Bot, e *handler.CommandEvent) error {
+	characterID, err := uuid.Parse(e.SlashCommandInteractionData().String("character"))
+	if err != nil {
+		return e.CreateMessage(ErrorMessage("Select a valid character"))
+	}
+
+	_, err = services.AddTradeCharacter(e.Ctx, b, e.User().ID, characterID)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(tradeUpdatedMessage("Character added to the trade."))
+}
+
+func handleTradeRemove(b *bot.Bot, e *handler.CommandEvent) error {
+	characterID, err := uuid.Parse(e.SlashCommandInteractionData().String("character"))
+	if err != nil {
+		return e.CreateMessage(ErrorMessage("Select a valid character"))
+	}
+
+	_, err = services.RemoveTradeCharacter(b, e.User().ID, characterID)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(tradeUpdatedMessage("Character removed from the trade."))
+}
+
+func handleTradeCoins(b *bot.Bot, e *handler.CommandEvent) error {
+	amount := e.SlashCommandInteractionData().Int("amount")
+
+	_, err := services.SetTradeCoins(e.Ctx, b, e.User().ID, amount)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(tradeUpdatedMessage(fmt.Sprintf("You now offer %d coins.", amount)))
+}
+
+func handleTradeConfirm(b *bot.Bot, e *handler.CommandEvent) error {
+	_, completed, err := services.ConfirmTrade(e.Ctx, b, e.User().ID)
+	if err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	if completed {
+		return e.CreateMessage(SuccessMessage("Trade completed", "Everything has been exchanged."))
+	}
+	return e.CreateMessage(tradeUpdatedMessage("You confirmed the trade. Waiting for the other player."))
+}
+
+func handleTradeCancel(b *bot.Bot, e *handler.CommandEvent) error {
+	if _, err := services.CancelTrade(b, e.User().ID); err != nil {
+		return e.CreateMessage(ErrorMessage(err.Error()))
+	}
+
+	return e.CreateMessage(InfoMessage("Trade cancelled"))
+}
+
+// tradeUpdatedMessage acknowledges a change privately, the shared trade
+// message shows the new offer to both players.
+func tradeUpdatedMessage(message string) discord.MessageCreate {
+	reply := InfoMessage(message)
+	reply.Flags = discord.MessageFlagEphemeral
+	return reply
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func init() {
+	Commands["use"] = cmdUse
+}
+
+var cmdUse = &Command{
+	Cmd: discord.SlashCommandCreate{
+		Name:        "use",
+		Description: "Use an item from your inventory",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionString{
+				Name:         "item",
+				Description:  "The item to use",
+				Required:     true,
+				Autocomplete: true,
+			},
+			discord.ApplicationCommandOptionString{
+				Name:         "character",
+				Description:  "The character to use it on",
+				Required:     true,
+				Autocomplete: true,
+			},
+			discord.ApplicationCommandOptionString{
+				Name:        "personality",
+				Description: "The new personality, for mints",
+				Choices:     personalityChoices(),
+			},
+		},
+	},
+	Handler:      HandleUse,
+	Autocomplete: handleUseAutocomplete,
+	Category:     "Friemon",
+}
+
+func HandleUse(b *bot.Bot) handler.CommandHandler {
+	return func(e *handler.CommandEvent) error {
+		data := e.SlashCommandInteractionData()
+
+		item, ok := game.FindItem(data.String("item"))
+		if !ok {
+			return e.CreateMessage(ErrorMessage("Select a valid item"))
+		}
+
+		characterID, err := uuid.Parse(data.String("character"))
+		if err != nil {
+			return e.CreateMessage(ErrorMessage("Select a valid character"))
+		}
+
+		var personality constants.Personality
+		if item.Category == game.ItemCategoryMint {
+			personality, ok = constants.ParsePersonality(data.String("personality"))
+			if !ok {
+				return e.CreateMessage(ErrorMessage("Choose the personality the mint should give"))
+			}
+		}
+
+		character, use, err := services.UseItem(e.Ctx, b, e.User().ID, item, characterID, personality)
+		if err != nil {
+			return e.CreateMessage(ErrorMessage(err.Error()))
+		}
+
+		message := SuccessMessage(fmt.Sprintf("Used %s %s", item.Emoji, item.Name), itemUseSummary(item, character, use))
+		if use.LevelUp.Evolution != nil {
+			message.Components = []discord.ContainerComponent{services.EvolutionButtons(character.ID, *use.LevelUp.Evolution)}
+		}
+		return e.CreateMessage(message)
+	}
+}
+
+func handleUseAutocomplete(b *bot.Bot) handler.AutocompleteHandler {
+	characterAutocomplete := handleGetCharacterAutocomplete(b)
+
+	return func(e *handler.AutocompleteEvent) error {
+		if e.Data.Focused().Name == "item" {
+			return handleInventoryAutocomplete(b, e)
+		}
+		return characterAutocomplete(e)
+	}
+}
@@ -0,0 +1,35 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+)
+
+func init() {
+	Components["/auction_bid/{auction_id}"] = HandleAuctionBid
+}
+
+// HandleAuctionBid places the minimum next bid on an auction.
+func HandleAuctionBid(b *bot.Bot) handler.ComponentHandler {
+	return func(e *handler.ComponentEvent) error {
+		auctionID, err := strconv.Atoi(e.Vars["auction_id"])
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: "Invalid auction.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		auction, err := services.PlaceAuctionBid(e.Ctx, b, e.User().ID, auctionID, 0)
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: err.Error(), Flags: discord.MessageFlagEphemeral})
+		}
+
+		return e.CreateMessage(discord.MessageCreate{
+			Content: fmt.Sprintf("You bid **%d coins**, they are held until you are outbid or the auction ends.", auction.CurrentBid),
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	}
+}
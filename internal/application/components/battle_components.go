@@ -2,20 +2,25 @@ package components
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/snowflake/v2"
 	"github.com/google/uuid"
-	"github.com/theoreotm/friemon/constants"
 	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
 	"github.com/theoreotm/friemon/internal/core/game"
 )
 
 func init() {
 	Components["/battle_challenge_accept"] = HandleChallengeAccept
 	Components["/battle_challenge_decline"] = HandleChallengeDecline
-	// We will add more handlers here as we create them
+	Components["/battle_team_select/{battle_id}/{player_id}"] = HandleTeamSelect
+	Components["/battle_move/{battle_id}/{player_id}/{move_id}"] = HandleBattleMove
+	Components["/battle_switch/{battle_id}/{player_id}/{team_index}"] = HandleBattleSwitch
+	Components["/battle_skip/{battle_id}/{player_id}"] = HandleBattleSkip
 }
 
 func HandleChallengeAccept(b *bot.Bot) handler.ComponentHandler {
@@ -72,18 +77,15 @@ func HandleChallengeAccept(b *bot.Bot) handler.ComponentHandler {
 			e.UpdateMessage(discord.NewMessageUpdateBuilder().SetContentf("Failed to create battle thread: %s", err.Error()).Build())
 			return err
 		}
-		battle.ThreadID = thread.ID()
+		if err := b.BattleManager.SetBattleThread(battle.ID, thread.ID()); err != nil {
+			e.UpdateMessage(discord.NewMessageUpdateBuilder().SetContentf("Failed to start battle: %s", err.Error()).Build())
+			return err
+		}
 
 		// Update original message
 		e.UpdateMessage(discord.NewMessageUpdateBuilder().SetContentf("Battle accepted! Go to %s to watch!", thread.Mention()).Build())
 
-		// Send initial messages to the threads
-		sendTeamSelection(b, battle, battle.Player1)
-		sendTeamSelection(b, battle, battle.Player2)
-
-		b.Client.Rest().CreateMessage(thread.ID(), discord.MessageCreate{
-			Content: "The battle is about to begin! Players are selecting their teams.",
-		})
+		services.IntroduceBattle(b, battle)
 
 		return nil
 	}
@@ -92,7 +94,7 @@ func HandleChallengeAccept(b *bot.Bot) handler.ComponentHandler {
 func HandleChallengeDecline(b *bot.Bot) handler.ComponentHandler {
 	return func(e *handler.ComponentEvent) error {
 		// Similar logic to accept, but decline the challenge
-		challengeIDStr := strings.Split(e.Data.CustomID(), ":")[1]
+		challengeIDStr := strings.SplitN(e.Data.CustomID(), "/", 2)[1]
 		challengeID, err := uuid.Parse(challengeIDStr)
 		if err != nil {
 			e.CreateMessage(discord.MessageCreate{Content: "Invalid challenge ID.", Flags: discord.MessageFlagEphemeral})
@@ -118,66 +120,138 @@ func HandleChallengeDecline(b *bot.Bot) handler.ComponentHandler {
 	}
 }
 
-func sendTeamSelection(b *bot.Bot, battle *game.Battle, player *game.BattlePlayer) {
-	// This would create a private thread for each player
-	// For simplicity, we'll just send an ephemeral message for now.
-	// In a real implementation, you'd create a private thread.
+func HandleTeamSelect(b *bot.Bot) handler.ComponentHandler {
+	return func(e *handler.ComponentEvent) error {
+		battle, playerID, ok := battleFromVars(b, e)
+		if !ok {
+			return nil
+		}
 
-	userChars, err := b.DB.GetCharactersForUser(b.Context, player.ID)
-	if err != nil {
-		b.Client.Rest().CreateMessage(battle.ThreadID, discord.MessageCreate{Content: fmt.Sprintf("Failed to get characters for %s", player.ID)})
-		return
+		var team []*game.Character
+		for _, value := range e.StringSelectMenuInteractionData().Values {
+			characterID, err := uuid.Parse(value)
+			if err != nil {
+				return e.CreateMessage(discord.MessageCreate{Content: "Invalid character selected.", Flags: discord.MessageFlagEphemeral})
+			}
+
+			character, err := b.DB.GetCharacter(e.Ctx, characterID)
+			if err != nil || character == nil || character.OwnerID != playerID.String() {
+				return e.CreateMessage(discord.MessageCreate{Content: "You can only battle with characters you own.", Flags: discord.MessageFlagEphemeral})
+			}
+			team = append(team, character)
+		}
+
+		if err := b.BattleManager.SetTeam(playerID, team); err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: fmt.Sprintf("Failed to set team: %s", err.Error()), Flags: discord.MessageFlagEphemeral})
+		}
+
+		if err := e.UpdateMessage(discord.NewMessageUpdateBuilder().
+			SetContentf("%s has locked in their team!", e.User().Mention()).
+			ClearEmbeds().
+			ClearContainerComponents().
+			Build()); err != nil {
+			return err
+		}
+
+		if !battle.CanStart() {
+			return nil
+		}
+
+		// Only the caller that actually starts the battle publishes it
+		if err := b.BattleManager.StartBattle(battle.ID); err != nil {
+			return nil
+		}
+
+		services.PublishTurn(b, battle)
+		services.SendActionPrompt(b, battle)
+		return nil
 	}
+}
 
-	if len(userChars) < battle.Settings.TeamSize {
-		b.Client.Rest().CreateMessage(battle.ThreadID, discord.MessageCreate{Content: fmt.Sprintf("%s does not have enough characters to battle!", player.ID)})
-		// Here you would cancel the battle
-		return
+func HandleBattleMove(b *bot.Bot) handler.ComponentHandler {
+	return func(e *handler.ComponentEvent) error {
+		moveID, err := strconv.Atoi(e.Vars["move_id"])
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: "Invalid move.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		return handleBattleAction(b, e, game.PlayerAction{Action: game.ActionAttack, MoveID: moveID})
 	}
+}
 
-	options := make([]discord.StringSelectMenuOption, 0, len(userChars))
-	for _, char := range userChars {
-		options = append(options, discord.NewStringSelectMenuOption(
-			fmt.Sprintf("Lvl %d %s", char.Level, char.CharacterName()),
-			char.ID.String(),
-		).WithEmoji(discord.ComponentEmoji{Name: "⚔️"}))
+func HandleBattleSwitch(b *bot.Bot) handler.ComponentHandler {
+	return func(e *handler.ComponentEvent) error {
+		teamIndex, err := strconv.Atoi(e.Vars["team_index"])
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: "Invalid switch target.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		return handleBattleAction(b, e, game.PlayerAction{Action: game.ActionSwitch, SwitchTo: teamIndex})
 	}
+}
 
-	selectMenu := discord.NewStringSelectMenu(
-		fmt.Sprintf("battle_team_select:%s", battle.ID),
-		"Select your team...",
-		options...,
-	).WithMaxValues(battle.Settings.TeamSize).WithMinValues(battle.Settings.TeamSize)
-
-	embed := discord.NewEmbedBuilder().
-		SetTitle("Team Selection").
-		SetDescription(fmt.Sprintf("Choose your team of %d characters for the battle.", battle.Settings.TeamSize)).
-		SetColor(constants.ColorInfo).
-		Build()
-
-	// This message should be ephemeral and sent to the player
-	b.Client.Rest().CreateMessage(battle.ChannelID, discord.MessageCreate{
-		Content:    fmt.Sprintf("<@%s>, it's your turn to select a team.", player.ID),
-		Embeds:     []discord.Embed{embed},
-		Components: []discord.ContainerComponent{discord.NewActionRow(selectMenu)},
-		Flags:      discord.MessageFlagEphemeral,
-	})
+func HandleBattleSkip(b *bot.Bot) handler.ComponentHandler {
+	return func(e *handler.ComponentEvent) error {
+		return handleBattleAction(b, e, game.PlayerAction{Action: game.ActionSkip})
+	}
 }
 
-// NOTE: The full implementation would require many more component handlers for:
-// - battle_team_select
-// - battle_team_confirm
-// - battle_action_attack
-// - battle_action_switch
-// - battle_exec_move
-// - battle_exec_switch
-//
-// Each of these would interact with the BattleManager and send updated embeds.
-// This file would become very large. For brevity, I've shown the initial challenge flow.
-// The subsequent flows would follow a similar pattern of:
-// 1. Parsing the custom ID.
-// 2. Finding the battle in the BattleManager.
-// 3. Performing the action (e.g., adding a character to a team, submitting a move).
-// 4. Checking if the battle state can advance (e.g., both players confirmed teams, both players chose a move).
-// 5. If so, advance the state (e.g., start the battle, process the turn).
-// 6. Update the Discord messages with new embeds and components.
+// handleBattleAction submits a player's action and, once both players have
+// acted, processes the turn and publishes it to the battle thread.
+func handleBattleAction(b *bot.Bot, e *handler.ComponentEvent, action game.PlayerAction) error {
+	battle, playerID, ok := battleFromVars(b, e)
+	if !ok {
+		return nil
+	}
+
+	action.PlayerID = playerID
+	if err := b.BattleManager.SubmitAction(playerID, action); err != nil {
+		return e.CreateMessage(discord.MessageCreate{Content: fmt.Sprintf("❌ %s", err.Error()), Flags: discord.MessageFlagEphemeral})
+	}
+
+	if err := e.CreateMessage(discord.MessageCreate{Content: "✅ Action locked in!", Flags: discord.MessageFlagEphemeral}); err != nil {
+		return err
+	}
+
+	processed, err := b.BattleManager.ProcessTurnIfReady(battle.ID)
+	if err != nil {
+		return err
+	}
+	if !processed {
+		return nil
+	}
+
+	services.PublishTurn(b, battle)
+	if battle.State == game.BattleStateFinished {
+		services.FinishBattle(b, battle)
+		return nil
+	}
+
+	services.SendActionPrompt(b, battle)
+	return nil
+}
+
+// battleFromVars resolves the battle and player from the component's custom ID
+// and makes sure the player is the one interacting. It responds to the
+// interaction itself when ok is false.
+func battleFromVars(b *bot.Bot, e *handler.ComponentEvent) (battle *game.Battle, playerID snowflake.ID, ok bool) {
+	battleID, err := uuid.Parse(e.Vars["battle_id"])
+	if err != nil {
+		e.CreateMessage(discord.MessageCreate{Content: "Invalid battle ID.", Flags: discord.MessageFlagEphemeral})
+		return nil, 0, false
+	}
+
+	playerID, err = snowflake.Parse(e.Vars["player_id"])
+	if err != nil || playerID != e.User().ID {
+		e.CreateMessage(discord.MessageCreate{Content: "These controls are not for you.", Flags: discord.MessageFlagEphemeral})
+		return nil, 0, false
+	}
+
+	battle, exists := b.BattleManager.GetBattle(battleID)
+	if !exists || battle.State == game.BattleStateFinished || battle.State == game.BattleStateCancelled {
+		e.CreateMessage(discord.MessageCreate{Content: "This battle is no longer running.", Flags: discord.MessageFlagEphemeral})
+		return nil, 0, false
+	}
+
+	return battle, playerID, true
+}
@@ -0,0 +1,44 @@
+package components
+
+import (
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+)
+
+func init() {
+	Components["/dex_claim/{user_id}"] = HandleDexClaim
+}
+
+func HandleDexClaim(b *bot.Bot) handler.ComponentHandler {
+	return func(e *handler.ComponentEvent) error {
+		if e.Vars["user_id"] != e.User().ID.String() {
+			return e.CreateMessage(discord.MessageCreate{Content: "This isn't your dex.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		reached, err := services.ClaimDexMilestones(e.Ctx, b, e.User().ID)
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: err.Error(), Flags: discord.MessageFlagEphemeral})
+		}
+
+		dex, err := b.DB.GetDex(e.Ctx, e.User().ID)
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: err.Error(), Flags: discord.MessageFlagEphemeral})
+		}
+		user, err := b.DB.GetUser(e.Ctx, e.User().ID)
+		if err != nil || user == nil {
+			return e.CreateMessage(discord.MessageCreate{Content: "You haven't started playing yet.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		embeds := []discord.Embed{services.DexCompletionEmbed(e.User(), dex, user.DexMilestones)}
+		if len(reached) > 0 {
+			embeds = append(embeds, services.DexMilestoneEmbed(reached))
+		}
+
+		return e.UpdateMessage(discord.NewMessageUpdateBuilder().
+			SetEmbeds(embeds...).
+			ClearContainerComponents().
+			Build())
+	}
+}
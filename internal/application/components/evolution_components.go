@@ -0,0 +1,73 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+)
+
+func init() {
+	Components["/evolve_confirm/{character_id}/{form_id}"] = HandleEvolveConfirm
+	Components["/evolve_cancel/{character_id}"] = HandleEvolveCancel
+}
+
+func HandleEvolveConfirm(b *bot.Bot) handler.ComponentHandler {
+	return func(e *handler.ComponentEvent) error {
+		characterID, err := uuid.Parse(e.Vars["character_id"])
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: "Invalid character.", Flags: discord.MessageFlagEphemeral})
+		}
+		formID, err := strconv.Atoi(e.Vars["form_id"])
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: "Invalid form.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		character, learned, err := services.ConfirmEvolution(e.Ctx, b, e.User().ID, characterID, formID)
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: err.Error(), Flags: discord.MessageFlagEphemeral})
+		}
+
+		description := fmt.Sprintf("Your character evolved into **%s**!", character.Format("ln"))
+		for _, move := range learned {
+			description += fmt.Sprintf("\nIt %s.", move)
+		}
+
+		return e.UpdateMessage(discord.NewMessageUpdateBuilder().
+			SetEmbeds(discord.NewEmbedBuilder().
+				SetTitle("✨ Evolved!").
+				SetDescription(description).
+				SetColor(constants.ColorSuccess).
+				Build()).
+			ClearContainerComponents().
+			Build())
+	}
+}
+
+func HandleEvolveCancel(b *bot.Bot) handler.ComponentHandler {
+	return func(e *handler.ComponentEvent) error {
+		characterID, err := uuid.Parse(e.Vars["character_id"])
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: "Invalid character.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		character, err := b.DB.GetCharacter(e.Ctx, characterID)
+		if err != nil || character == nil || character.OwnerID != e.User().ID.String() {
+			return e.CreateMessage(discord.MessageCreate{Content: "This isn't your character.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		return e.UpdateMessage(discord.NewMessageUpdateBuilder().
+			SetEmbeds(discord.NewEmbedBuilder().
+				SetTitle("Evolution cancelled").
+				SetDescription(fmt.Sprintf("Your **%s** stays as it is. Use `/evolve` whenever you're ready.", character.Format("n"))).
+				SetColor(constants.ColorInfo).
+				Build()).
+			ClearContainerComponents().
+			Build())
+	}
+}
@@ -0,0 +1,71 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+)
+
+func init() {
+	Components["/release_confirm/{release_id}"] = HandleReleaseConfirm
+	Components["/release_cancel/{release_id}"] = HandleReleaseCancel
+}
+
+func HandleReleaseConfirm(b *bot.Bot) handler.ComponentHandler {
+	return func(e *handler.ComponentEvent) error {
+		releaseID, err := uuid.Parse(e.Vars["release_id"])
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: "Invalid release.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		released, coins, err := services.ConfirmRelease(e.Ctx, b, releaseID, e.User().ID)
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: err.Error(), Flags: discord.MessageFlagEphemeral})
+		}
+
+		description := fmt.Sprintf("You released **%d** character(s) and received **%d coins**.", released, coins)
+		if released == 0 {
+			description = "None of those characters could be released anymore."
+		}
+
+		return e.UpdateMessage(discord.NewMessageUpdateBuilder().
+			SetEmbeds(discord.NewEmbedBuilder().
+				SetTitle("Characters released").
+				SetDescription(description).
+				SetColor(constants.ColorSuccess).
+				Build()).
+			ClearContainerComponents().
+			Build())
+	}
+}
+
+func HandleReleaseCancel(b *bot.Bot) handler.ComponentHandler {
+	return func(e *handler.ComponentEvent) error {
+		releaseID, err := uuid.Parse(e.Vars["release_id"])
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: "Invalid release.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		cancelled, err := services.CancelRelease(b, releaseID, e.User().ID)
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: err.Error(), Flags: discord.MessageFlagEphemeral})
+		}
+		if !cancelled {
+			return e.CreateMessage(discord.MessageCreate{Content: "This isn't your release or it has expired.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		return e.UpdateMessage(discord.NewMessageUpdateBuilder().
+			SetEmbeds(discord.NewEmbedBuilder().
+				SetTitle("Release cancelled").
+				SetDescription("Your characters are safe.").
+				SetColor(constants.ColorInfo).
+				Build()).
+			ClearContainerComponents().
+			Build())
+	}
+}
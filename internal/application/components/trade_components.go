@@ -0,0 +1,47 @@
+package components
+
+import (
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+)
+
+func init() {
+	Components["/trade_confirm/{trade_id}"] = HandleTradeConfirm
+	Components["/trade_cancel/{trade_id}"] = HandleTradeCancel
+}
+
+func HandleTradeConfirm(b *bot.Bot) handler.ComponentHandler {
+	return func(e *handler.ComponentEvent) error {
+		if !inTrade(b, e) {
+			return e.CreateMessage(discord.MessageCreate{Content: "This isn't your trade or it has closed.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		if _, _, err := services.ConfirmTrade(e.Ctx, b, e.User().ID); err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: err.Error(), Flags: discord.MessageFlagEphemeral})
+		}
+
+		return e.DeferUpdateMessage()
+	}
+}
+
+func HandleTradeCancel(b *bot.Bot) handler.ComponentHandler {
+	return func(e *handler.ComponentEvent) error {
+		if !inTrade(b, e) {
+			return e.CreateMessage(discord.MessageCreate{Content: "This isn't your trade or it has closed.", Flags: discord.MessageFlagEphemeral})
+		}
+
+		if _, err := services.CancelTrade(b, e.User().ID); err != nil {
+			return e.CreateMessage(discord.MessageCreate{Content: err.Error(), Flags: discord.MessageFlagEphemeral})
+		}
+
+		return e.DeferUpdateMessage()
+	}
+}
+
+// inTrade reports whether the user pressing a trade button is part of that trade.
+func inTrade(b *bot.Bot, e *handler.ComponentEvent) bool {
+	trade, ok := b.TradeManager.GetPlayerTrade(e.User().ID)
+	return ok && trade.ID.String() == e.Vars["trade_id"]
+}
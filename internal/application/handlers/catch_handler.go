@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+)
+
+// catchByName catches the character spawned in a channel when a message names
+// it, in guilds that catch by guessing.
+func catchByName(b *bot.Bot, e *events.MessageCreate) {
+	log := logger.NewLogger("handlers.catch")
+
+	if e.GuildID == nil || e.Message.Content == "" {
+		return
+	}
+	channelID := e.ChannelID
+	userID := e.Message.Author.ID
+
+	settings, err := services.GuildSettings(b.Context, b, *e.GuildID)
+	if err != nil || !settings.CatchByName {
+		return
+	}
+
+	character, err := b.Cache.GetChannelCharacter(channelID)
+	if err != nil || character == nil {
+		return
+	}
+	if !game.NameMatches(e.Message.Content, character.CharacterName()) {
+		return
+	}
+
+	character, err = services.ClaimSpawn(b.Context, b, channelID, character.ID, userID)
+	if err != nil {
+		if !errors.Is(err, services.ErrAlreadyClaimed) {
+			log.Error("Failed to catch character",
+				logger.Handler("catch"),
+				logger.DiscordUserID(userID),
+				logger.DiscordChannelID(channelID),
+				logger.ErrorField(err),
+			)
+		}
+		return
+	}
+
+	shiny := ""
+	if character.Shiny {
+		shiny = " ✨ Shiny!"
+	}
+
+	messageID := e.MessageID
+	if _, err := b.Client.Rest().CreateMessage(channelID, discord.MessageCreate{
+		Content: fmt.Sprintf("🎉 Congratulations <@%s>! You caught a level %d **%s** (%s IV)!%s",
+			userID,
+			character.Level,
+			character.CharacterName(),
+			character.IvPercentage(),
+			shiny,
+		),
+		MessageReference: &discord.MessageReference{MessageID: &messageID},
+		AllowedMentions:  &discord.AllowedMentions{Users: []snowflake.ID{userID}},
+	}); err != nil {
+		log.Warn("Failed to announce catch",
+			logger.DiscordChannelID(channelID),
+			logger.ErrorField(err),
+		)
+	}
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"github.com/disgoorg/disgo/events"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+)
+
+// trackGuildMember records the guilds users chat in for per guild leaderboards.
+func trackGuildMember(b *bot.Bot, e *events.MessageCreate) {
+	if e.GuildID == nil {
+		return
+	}
+
+	if err := services.TrackGuildMember(b.Context, b, *e.GuildID, e.Message.Author.ID); err != nil {
+		logger.NewLogger("handlers.guild_member").Error("Failed to track guild member",
+			logger.Handler("guild_member"),
+			logger.DiscordUserID(e.Message.Author.ID),
+			logger.DiscordGuildID(*e.GuildID),
+			logger.ErrorField(err),
+		)
+	}
+}
@@ -12,6 +12,13 @@ func OnMessage(b *bot.Bot) disgobot.EventListener {
 			return
 		}
 
+		trackGuildMember(b, e)
+		catchByName(b, e)
+
+		if !countsMessage(b, e) {
+			return
+		}
+
 		spawnCharacter(b, e)
 		incrementXp(b, e)
 	})
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	disgobot "github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/events"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+)
+
+// OnReaction records fan favourite votes cast by spectators reacting to the
+// latest turn summary in battle threads.
+func OnReaction(b *bot.Bot) disgobot.EventListener {
+	log := logger.NewLogger("handlers.reaction")
+
+	return &events.ListenerAdapter{
+		OnGuildMessageReactionAdd: func(e *events.GuildMessageReactionAdd) {
+			if e.Member.User.Bot || e.Emoji.Name == nil {
+				return
+			}
+
+			battle, exists := b.BattleManager.GetChannelBattle(e.ChannelID)
+			if !exists {
+				return
+			}
+
+			playerID, ok := services.FanVotePlayer(battle, *e.Emoji.Name)
+			if !ok {
+				return
+			}
+
+			if err := b.BattleManager.VoteFanFavourite(e.ChannelID, e.MessageID, e.UserID, playerID); err != nil {
+				log.Debug("Fan vote rejected",
+					logger.DiscordUserID(e.UserID),
+					logger.DiscordChannelID(e.ChannelID),
+					logger.ErrorField(err),
+				)
+			}
+		},
+		OnGuildMessageReactionRemove: func(e *events.GuildMessageReactionRemove) {
+			if e.Emoji.Name == nil {
+				return
+			}
+
+			battle, exists := b.BattleManager.GetChannelBattle(e.ChannelID)
+			if !exists {
+				return
+			}
+
+			if playerID, ok := services.FanVotePlayer(battle, *e.Emoji.Name); ok {
+				b.BattleManager.RemoveFanVote(e.ChannelID, e.MessageID, e.UserID, playerID)
+			}
+		},
+	}
+}
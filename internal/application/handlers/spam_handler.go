@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"github.com/disgoorg/disgo/events"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+)
+
+// countsMessage filters out spam, so only real chat counts toward spawns and XP.
+func countsMessage(b *bot.Bot, e *events.MessageCreate) bool {
+	log := logger.NewLogger("handlers.spam")
+	userID := e.Message.Author.ID
+
+	settings := game.DefaultGuildSettings(0)
+	if e.GuildID != nil {
+		var err error
+		settings, err = services.GuildSettings(b.Context, b, *e.GuildID)
+		if err != nil {
+			log.Error("Failed to load guild settings, using defaults",
+				logger.DiscordGuildID(*e.GuildID),
+				logger.ErrorField(err),
+			)
+		}
+	}
+
+	reason, filtered, err := services.CheckMessage(b, settings, userID, e.Message.Content)
+	if err != nil {
+		// Don't punish chat for a cache outage
+		log.Warn("Failed to check message for spam",
+			logger.Handler("spam"),
+			logger.DiscordUserID(userID),
+			logger.ErrorField(err),
+		)
+		return true
+	}
+	if filtered {
+		services.ReportSpam(b, settings, e.ChannelID, userID, reason)
+		return false
+	}
+
+	return true
+}
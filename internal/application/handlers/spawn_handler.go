@@ -2,27 +2,29 @@ package handlers
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
 	"github.com/theoreotm/friemon/constants"
 	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/application/services"
 	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/scheduler/tasks"
 	"github.com/theoreotm/friemon/internal/pkg/logger"
 	"go.uber.org/zap"
 )
 
-const (
-	spawnThreshold = 4
-)
-
 func spawnCharacter(b *bot.Bot, e *events.MessageCreate) {
 	log := logger.NewLogger("handlers.spawn")
 
 	start := time.Now()
 	channelID := e.ChannelID
 	guildID := e.GuildID
+	if guildID == nil {
+		return
+	}
 
 	log.Info("Spawn handler triggered",
 		logger.Handler("spawn"),
@@ -38,20 +40,65 @@ func spawnCharacter(b *bot.Bot, e *events.MessageCreate) {
 		)
 	}()
 
+	settings, err := services.GuildSettings(b.Context, b, *guildID)
+	if err != nil {
+		log.Error("Failed to load guild settings, using defaults",
+			logger.DiscordGuildID(*guildID),
+			logger.ErrorField(err),
+		)
+	}
+
+	if !settings.CountsMessages(channelID) {
+		log.Debug("Spawns are disabled in channel",
+			logger.DiscordChannelID(channelID),
+		)
+		return
+	}
+
+	// Messages count toward spawns in the channel the character would appear in
+	channelID = settings.SpawnChannel(channelID)
+
+	// A lure forces a spawn within a few messages, even during the cooldown
+	lure, err := b.Cache.GetChannelLure(channelID)
+	if err != nil {
+		log.Warn("Failed to get channel lure",
+			logger.DiscordChannelID(channelID),
+			logger.ErrorField(err),
+		)
+	}
+
+	if lure == nil && b.Cache.IsSpawnOnCooldown(channelID) {
+		log.Debug("Spawn cooldown running, skipping spawn",
+			logger.DiscordChannelID(channelID),
+		)
+		return
+	}
+
+	// One user can't send every message toward a spawn
+	if counts, err := services.CountSpawnContribution(b, settings, channelID, e.Message.Author.ID); err != nil {
+		log.Warn("Failed to count spawn contribution",
+			logger.DiscordChannelID(channelID),
+			logger.ErrorField(err),
+		)
+	} else if !counts {
+		services.ReportSpam(b, settings, channelID, e.Message.Author.ID, game.SpamSpawnShare)
+		return
+	}
+
 	// Get and log current interaction count
 	count := b.Cache.GetInteractionCount(channelID)
 	log.Info("Checking interaction count",
 		logger.DiscordChannelID(channelID),
 		zap.Int("current_count", count),
-		zap.Int("required_threshold", spawnThreshold),
+		zap.Int("threshold_min", settings.SpawnThresholdMin),
+		zap.Int("threshold_max", settings.SpawnThresholdMax),
 		logger.CacheHit(count > 0),
 	)
 
-	if count < spawnThreshold {
+	if !settings.ShouldSpawn(count) && (lure == nil || !lure.ForcesSpawn(count)) {
 		log.Info("Threshold not met, skipping spawn",
 			logger.DiscordChannelID(channelID),
 			zap.Int("count", count),
-			zap.Int("needed", spawnThreshold-count),
 		)
 		err := b.Cache.IncrementInteractionCount(channelID)
 		if err != nil {
@@ -79,7 +126,8 @@ func spawnCharacter(b *bot.Bot, e *events.MessageCreate) {
 		)
 	}
 
-	// Check for existing character
+	// Check for existing character, which saves generating one. The cache
+	// below has the final say, as another message may spawn one meanwhile
 	if existingChar, err := b.Cache.GetChannelCharacter(channelID); err == nil && existingChar != nil {
 		log.Info("Character already exists in channel",
 			logger.DiscordChannelID(channelID),
@@ -90,20 +138,35 @@ func spawnCharacter(b *bot.Bot, e *events.MessageCreate) {
 	}
 
 	// Generate character
-	character := game.RandomCharacterSpawn()
+	modifiers, events, err := services.ActiveSpawnModifiers(b.Context, b)
+	if err != nil {
+		log.Error("Failed to load spawn events, spawning without them",
+			logger.ErrorField(err),
+		)
+	}
+	modifiers.ShinyMultiplier *= settings.ShinyMultiplier
+	if lure != nil {
+		modifiers.ApplyLure(*lure)
+	}
+
+	character := game.RandomCharacterSpawn(modifiers)
 
 	log.Info("Spawning character",
 		logger.Handler("spawn"),
 		logger.DiscordChannelID(channelID),
 		logger.CharacterName(character.CharacterName()),
 		logger.CharacterLevel(character.Level),
+		zap.String("rarity", character.Data().Rarity.String()),
 		zap.Bool("shiny", character.Shiny),
 		zap.String("personality", character.Personality.String()),
 		zap.String("iv_percentage", character.IvPercentage()),
+		zap.Int("active_events", len(events)),
+		zap.Bool("lured", lure != nil),
 	)
 
-	// Cache character
-	if err := b.Cache.SetChannelCharacter(channelID, character); err != nil {
+	// Cache character, unless another message spawned one first
+	stored, err := b.Cache.SetChannelCharacter(channelID, character)
+	if err != nil {
 		log.Error("Failed to cache spawned character",
 			logger.DiscordChannelID(channelID),
 			logger.CharacterID(character.ID),
@@ -111,6 +174,12 @@ func spawnCharacter(b *bot.Bot, e *events.MessageCreate) {
 		)
 		return
 	}
+	if !stored {
+		log.Info("Character already exists in channel",
+			logger.DiscordChannelID(channelID),
+		)
+		return
+	}
 
 	log.Info("Character cached successfully",
 		logger.DiscordChannelID(channelID),
@@ -131,18 +200,41 @@ func spawnCharacter(b *bot.Bot, e *events.MessageCreate) {
 	}
 
 	// Build spawn message
+	rarity := character.Data().Rarity
+	var tags []string
+	for _, event := range events {
+		tags = append(tags, event.Name)
+	}
+	if lure != nil {
+		tags = append(tags, "Lured")
+	}
+
 	embed := discord.NewEmbedBuilder().
 		SetTitle(fmt.Sprintf("A wild %s appeared!", character.CharacterName())).
 		SetDescription("Click the button below to claim it!").
-		SetColor(constants.ColorInfo).
+		SetColor(rarity.Color()).
+		SetFooterText(strings.Join(append([]string{rarity.String()}, tags...), " • ")).
 		Build()
 
 	components := []discord.ContainerComponent{
 		discord.NewActionRow(
-			discord.NewPrimaryButton("Claim", "claim"),
+			discord.NewPrimaryButton("Claim", tasks.SpawnButtonID(character.ID)),
 		),
 	}
 
+	// Guessing servers catch by typing the name, so hide it, its rarity and
+	// the button
+	if settings.CatchByName {
+		embed.Title = "A wild character appeared!"
+		embed.Description = "Type its name to catch it! Stuck? Use `/hint`."
+		embed.Color = constants.ColorDefault
+		embed.Footer = nil
+		if len(tags) > 0 {
+			embed.Footer = &discord.EmbedFooter{Text: strings.Join(tags, " • ")}
+		}
+		components = nil
+	}
+
 	// Handle image
 	var files []*discord.File
 	if img, err := character.Image(); err == nil {
@@ -181,31 +273,54 @@ func spawnCharacter(b *bot.Bot, e *events.MessageCreate) {
 		logger.DiscordChannelID(channelID),
 		logger.DiscordMessageID(msg.ID),
 		logger.CharacterName(character.CharacterName()),
-		zap.String("message_url", func() string {
-			if guildID == nil {
-				return ""
-			}
-			return fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, channelID, msg.ID)
-		}()),
+		zap.String("message_url", fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, channelID, msg.ID)),
 	)
 
-	// Schedule cleanup
-	if _, err := b.Scheduler.After(3*time.Minute).
-		Type("cleanup_character").
+	// Taking the lure stops it being refunded
+	if lure != nil {
+		if _, err := b.Cache.TakeChannelLure(channelID, lure.ID); err != nil {
+			log.Warn("Failed to remove used lure",
+				logger.DiscordChannelID(channelID),
+				logger.ErrorField(err),
+			)
+		}
+	}
+
+	if settings.SpawnCooldown > 0 {
+		if err := b.Cache.SetSpawnCooldown(channelID, settings.SpawnCooldown); err != nil {
+			log.Warn("Failed to start spawn cooldown",
+				logger.DiscordChannelID(channelID),
+				logger.ErrorField(err),
+			)
+		}
+	}
+
+	if err := b.Cache.SetSpawnMessage(character.ID, msg.ID); err != nil {
+		log.Warn("Failed to cache spawn message",
+			logger.DiscordMessageID(msg.ID),
+			logger.ErrorField(err),
+		)
+	}
+
+	// Schedule expiry, keyed by the message so a claim can cancel it
+	if _, err := b.Scheduler.After(game.SpawnLifetime).
+		ID(tasks.SpawnTaskID(msg.ID)).
 		With("channel_id", channelID.String()).
 		With("message_id", msg.ID.String()).
-		Emit("cleanup_character"); err != nil {
+		With("spawn_id", character.ID.String()).
+		With("character_name", character.CharacterName()).
+		Emit(tasks.SpawnExpiryTask); err != nil {
 
-		log.Error("Failed to schedule cleanup task",
+		log.Error("Failed to schedule spawn expiry",
 			logger.DiscordChannelID(channelID),
 			logger.DiscordMessageID(msg.ID),
 			logger.ErrorField(err),
 		)
 	} else {
-		log.Info("Cleanup task scheduled",
+		log.Info("Spawn expiry scheduled",
 			logger.DiscordChannelID(channelID),
 			logger.DiscordMessageID(msg.ID),
-			zap.Duration("cleanup_in", 3*time.Minute),
+			zap.Duration("expires_in", game.SpawnLifetime),
 		)
 	}
 }
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/db"
+	"github.com/theoreotm/friemon/internal/infrastructure/scheduler"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"github.com/theoreotm/friemon/internal/types"
+	"go.uber.org/zap"
+)
+
+const AuctionSettleTask = "auction_settle"
+
+// SetupAuctions registers the settlement task and schedules settlement of
+// every running auction, which only exist in the database after a restart.
+func SetupAuctions(b *bot.Bot) error {
+	b.Scheduler.On(AuctionSettleTask, func(ctx context.Context, data types.TaskData) error {
+		id, ok := data.Int("auction_id")
+		if !ok {
+			return fmt.Errorf("missing auction_id")
+		}
+		return SettleAuction(ctx, b, id)
+	})
+
+	auctions, err := b.DB.GetActiveAuctions(b.Context)
+	if err != nil {
+		return err
+	}
+
+	for i := range auctions {
+		if err := scheduleAuctionSettlement(b, &auctions[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StartAuction puts one of the seller's characters up for auction. The
+// character is locked until the auction settles.
+func StartAuction(ctx context.Context, b *bot.Bot, sellerID, channelID snowflake.ID, characterID uuid.UUID, duration time.Duration, startingBid int) (*game.Auction, error) {
+	if duration < game.MinAuctionDuration || duration > game.MaxAuctionDuration {
+		return nil, fmt.Errorf("auctions must last between %s and %s", game.MinAuctionDuration, game.MaxAuctionDuration)
+	}
+	if startingBid <= 0 || startingBid > game.MaxMarketPrice {
+		return nil, fmt.Errorf("the starting bid must be between 1 and %d coins", game.MaxMarketPrice)
+	}
+
+	character, err := escrowableCharacter(ctx, b, sellerID, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	auction, err := b.DB.CreateAuction(ctx, game.Auction{
+		SellerID:    sellerID,
+		Character:   *character,
+		ChannelID:   channelID,
+		StartingBid: startingBid,
+		EndsAt:      time.Now().Add(duration),
+	})
+	if errors.Is(err, db.ErrCharacterLocked) {
+		return nil, fmt.Errorf("that character is already up for sale")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := scheduleAuctionSettlement(b, auction); err != nil {
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+// SetAuctionMessage records the message showing an auction, so it can be kept up to date.
+func SetAuctionMessage(ctx context.Context, b *bot.Bot, auction *game.Auction, messageID snowflake.ID) error {
+	auction.MessageID = messageID
+	return b.DB.SetAuctionMessage(ctx, auction.ID, messageID)
+}
+
+// PlaceAuctionBid bids on an auction, the minimum next bid if amount is 0. The
+// bid is taken from the bidder's balance and the previous top bid refunded in
+// the same transaction.
+func PlaceAuctionBid(ctx context.Context, b *bot.Bot, bidderID snowflake.ID, auctionID, amount int) (*game.Auction, error) {
+	if _, err := b.DB.EnsureUser(ctx, bidderID); err != nil {
+		return nil, err
+	}
+
+	var auction *game.Auction
+	var previousBidder snowflake.ID
+	var previousBid int
+	var extended bool
+
+	err := b.DB.Tx(ctx, func(tx db.Store) error {
+		var err error
+		auction, err = tx.GetAuction(ctx, auctionID)
+		if err != nil {
+			return err
+		}
+		if auction == nil {
+			return fmt.Errorf("there is no auction with ID %d", auctionID)
+		}
+
+		previousBidder, previousBid = auction.BidderID, auction.CurrentBid
+		if amount == 0 {
+			amount = auction.MinimumBid()
+		}
+
+		extended, err = auction.PlaceBid(bidderID, amount, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.AdjustBalance(ctx, bidderID, -amount, game.ReasonAuctionBid, auctionReference(auction)); err != nil {
+			if errors.Is(err, db.ErrInsufficientBalance) {
+				return fmt.Errorf("you don't have %d coins", amount)
+			}
+			return err
+		}
+		if previousBidder != 0 {
+			if _, err := tx.AdjustBalance(ctx, previousBidder, previousBid, game.ReasonAuctionRefund, auctionReference(auction)); err != nil {
+				return err
+			}
+		}
+
+		return tx.UpdateAuctionBid(ctx, auction)
+	})
+	if errors.Is(err, db.ErrAuctionChanged) {
+		return nil, fmt.Errorf("someone else just bid, check the new price and try again")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if extended {
+		if err := scheduleAuctionSettlement(b, auction); err != nil {
+			return nil, err
+		}
+	}
+
+	if previousBidder != 0 {
+		notifyUser(b, previousBidder, discord.MessageCreate{
+			Embeds: []discord.Embed{{
+				Title: "You were outbid",
+				Description: fmt.Sprintf("Someone bid **%d coins** on auction **#%d** for **%s**. Your %d coins have been refunded.",
+					auction.CurrentBid, auction.ID, auction.Character.Format("l"), previousBid),
+				Color: constants.ColorWarn,
+			}},
+		})
+	}
+
+	updateAuctionMessage(b, auction)
+	return auction, nil
+}
+
+// CancelAuction ends one of the seller's auctions early. Only auctions
+// nobody has bid on can be cancelled.
+func CancelAuction(ctx context.Context, b *bot.Bot, sellerID snowflake.ID, auctionID int) (*game.Auction, error) {
+	auction, err := b.DB.GetAuction(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if auction == nil || auction.SellerID != sellerID || auction.State != game.AuctionActive {
+		return nil, fmt.Errorf("you don't have a running auction with ID %d", auctionID)
+	}
+	if auction.HasBids() {
+		return nil, fmt.Errorf("auctions can't be cancelled once someone has bid")
+	}
+
+	cancelled, err := b.DB.FinishAuction(ctx, auction, game.AuctionCancelled)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, fmt.Errorf("someone just bid on that auction")
+	}
+
+	auction.State = game.AuctionCancelled
+	updateAuctionMessage(b, auction)
+	return auction, nil
+}
+
+// SettleAuction ends an auction once its time is up. The character goes to
+// the top bidder and their held bid, minus the market fee, to the seller in one
+// transaction. Auctions nobody bid on return the character to the seller.
+func SettleAuction(ctx context.Context, b *bot.Bot, auctionID int) error {
+	auction, err := b.DB.GetAuction(ctx, auctionID)
+	if err != nil || auction == nil || auction.State != game.AuctionActive {
+		return err
+	}
+
+	// A late bid pushed the end back, a newer task settles it
+	if time.Now().Before(auction.EndsAt) {
+		return scheduleAuctionSettlement(b, auction)
+	}
+
+	state := game.AuctionUnsold
+	if auction.HasBids() {
+		state = game.AuctionSold
+	}
+
+	settled := false
+	err = b.DB.Tx(ctx, func(tx db.Store) error {
+		var err error
+		settled, err = tx.FinishAuction(ctx, auction, state)
+		if err != nil || !settled || state != game.AuctionSold {
+			return err
+		}
+
+		if err := tx.TransferCharacter(ctx, auction.Character.ID, auction.BidderID); err != nil {
+			return err
+		}
+
+		proceeds := auction.CurrentBid - game.MarketFee(auction.CurrentBid)
+		_, err = tx.AdjustBalance(ctx, auction.SellerID, proceeds, game.ReasonAuctionSale, auctionReference(auction))
+		return err
+	})
+	if err != nil || !settled {
+		return err
+	}
+	auction.State = state
+
+	logger.NewLogger("services.auction").Info("Auction settled",
+		zap.Int("auction_id", auction.ID),
+		zap.String("state", state.String()),
+		logger.DiscordUserID(auction.SellerID),
+		zap.Int("price", auction.CurrentBid),
+	)
+
+	updateAuctionMessage(b, auction)
+
+	if state == game.AuctionUnsold {
+		notifyUser(b, auction.SellerID, discord.MessageCreate{
+			Embeds: []discord.Embed{{
+				Title:       "Auction ended",
+				Description: fmt.Sprintf("Nobody bid on your **%s No. %d**, it has been returned to you.", auction.Character.Format("ln"), auction.Character.IDX),
+				Color:       constants.ColorInfo,
+			}},
+		})
+		return nil
+	}
+
+	notifyUser(b, auction.SellerID, discord.MessageCreate{
+		Embeds: []discord.Embed{{
+			Title: "Auction sold",
+			Description: fmt.Sprintf("Your **%s No. %d** sold to <@%s> for **%d coins**. You received **%d coins** after the %d%% market fee.",
+				auction.Character.Format("ln"), auction.Character.IDX, auction.BidderID, auction.CurrentBid,
+				auction.CurrentBid-game.MarketFee(auction.CurrentBid), game.MarketFeePercent),
+			Color: constants.ColorSuccess,
+		}},
+	})
+	notifyUser(b, auction.BidderID, discord.MessageCreate{
+		Embeds: []discord.Embed{{
+			Title:       "Auction won",
+			Description: fmt.Sprintf("You won auction **#%d** and **%s** is now yours for **%d coins**.", auction.ID, auction.Character.Format("l"), auction.CurrentBid),
+			Color:       constants.ColorSuccess,
+		}},
+	})
+
+	refreshLeaderboards(b, auction.SellerID, auction.BidderID)
+	return nil
+}
+
+// AuctionMessage renders an auction, with a bid button while it runs.
+func AuctionMessage(auction *game.Auction) discord.MessageUpdate {
+	return discord.NewMessageUpdateBuilder().
+		SetEmbeds(AuctionEmbed(auction)).
+		SetContainerComponents(AuctionComponents(auction)...).
+		Build()
+}
+
+// AuctionComponents are the buttons of an auction, none once it has ended.
+func AuctionComponents(auction *game.Auction) []discord.ContainerComponent {
+	if auction.State != game.AuctionActive {
+		return []discord.ContainerComponent{}
+	}
+
+	return []discord.ContainerComponent{
+		discord.NewActionRow(
+			discord.NewPrimaryButton(fmt.Sprintf("Bid %d coins", auction.MinimumBid()), fmt.Sprintf("/auction_bid/%d", auction.ID)),
+		),
+	}
+}
+
+// AuctionEmbed shows the character up for auction and the state of the bidding.
+func AuctionEmbed(auction *game.Auction) discord.Embed {
+	character := auction.Character
+
+	embed := discord.NewEmbedBuilder().
+		SetTitlef("Auction #%d: %s", auction.ID, character.Format("l")).
+		SetDescription(fmt.Sprintf("%s Sold by <@%s>\nIV: **%s**", character.Sprite(), auction.SellerID, character.IvPercentage())).
+		SetColor(constants.ColorInfo)
+
+	if auction.HasBids() {
+		embed.AddField("Top Bid", fmt.Sprintf("**%d coins** by <@%s>", auction.CurrentBid, auction.BidderID), true)
+	} else {
+		embed.AddField("Starting Bid", fmt.Sprintf("%d coins", auction.StartingBid), true)
+	}
+	embed.AddField("Bids", fmt.Sprint(auction.Bids), true)
+
+	switch auction.State {
+	case game.AuctionActive:
+		embed.AddField("Ends", fmt.Sprintf("<t:%d:R>", auction.EndsAt.Unix()), true)
+		embed.SetFooterTextf("Bid with the button or /auction bid • Late bids extend the auction to %s", game.AuctionSnipeWindow)
+	case game.AuctionSold:
+		embed.SetColor(constants.ColorSuccess)
+		embed.SetFooterTextf("Sold to the top bidder")
+	default:
+		embed.SetColor(constants.ColorDefault)
+		embed.SetFooterTextf("Auction %s", auction.State)
+	}
+
+	return embed.Build()
+}
+
+// auctionReference identifies an auction in the transactions ledger.
+func auctionReference(auction *game.Auction) string {
+	return fmt.Sprintf("auction #%d", auction.ID)
+}
+
+func scheduleAuctionSettlement(b *bot.Bot, auction *game.Auction) error {
+	_, err := b.Scheduler.At(auction.EndsAt).
+		With("auction_id", auction.ID).
+		ID(fmt.Sprintf("%s:%d:%d", AuctionSettleTask, auction.ID, auction.EndsAt.Unix())).
+		Emit(AuctionSettleTask)
+	if errors.Is(err, scheduler.ErrDuplicateTask) {
+		return nil
+	}
+	return err
+}
+
+// updateAuctionMessage refreshes the auction message after a bid or once it ended.
+func updateAuctionMessage(b *bot.Bot, auction *game.Auction) {
+	if auction.MessageID == 0 {
+		return
+	}
+
+	if _, err := b.Client.Rest().UpdateMessage(auction.ChannelID, auction.MessageID, AuctionMessage(auction)); err != nil {
+		logger.NewLogger("services.auction").Error("Failed to update auction message",
+			zap.Int("auction_id", auction.ID),
+			logger.DiscordChannelID(auction.ChannelID),
+			logger.ErrorField(err),
+		)
+	}
+}
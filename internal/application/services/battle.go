@@ -0,0 +1,466 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"github.com/theoreotm/friemon/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// Reactions spectators use to vote for the fan favourite
+	FanVoteEmojiPlayer1 = "1️⃣"
+	FanVoteEmojiPlayer2 = "2️⃣"
+
+	TeamSelectionTask    = "battle_team_selection"
+	TeamSelectionTimeout = 10 * time.Minute // Time players have to pick their teams before the battle is cancelled
+
+	hpBarLength       = 10
+	maxTeamSelectSize = 25 // Discord select menu option limit
+)
+
+var statusIcons = map[constants.StatusEffect]string{
+	constants.StatusPoison:   "☠️",
+	constants.StatusBurn:     "🔥",
+	constants.StatusParalyze: "⚡",
+	constants.StatusSleep:    "💤",
+	constants.StatusFreeze:   "🧊",
+	constants.StatusConfuse:  "💫",
+}
+
+// FanVotePlayer returns the player a fan favourite reaction votes for.
+func FanVotePlayer(battle *game.Battle, emoji string) (snowflake.ID, bool) {
+	switch emoji {
+	case FanVoteEmojiPlayer1:
+		return battle.Player1.ID, true
+	case FanVoteEmojiPlayer2:
+		return battle.Player2.ID, true
+	default:
+		return 0, false
+	}
+}
+
+// SetupBattles registers the task that cancels battles whose players do not
+// pick their teams in time.
+func SetupBattles(b *bot.Bot) error {
+	b.Scheduler.On(TeamSelectionTask, func(ctx context.Context, data types.TaskData) error {
+		id, ok := data.String("battle_id")
+		if !ok {
+			return fmt.Errorf("missing battle_id")
+		}
+		battleID, err := uuid.Parse(id)
+		if err != nil {
+			return err
+		}
+
+		battle, exists := b.BattleManager.GetBattle(battleID)
+		if !exists || !b.BattleManager.EndTeamSelection(battleID) {
+			return nil
+		}
+
+		_, err = b.Client.Rest().CreateMessage(battle.ThreadID, discord.MessageCreate{
+			Content: "Team selection timed out. The battle has been cancelled.",
+		})
+		return err
+	})
+
+	return nil
+}
+
+// IntroduceBattle posts the opening message in a new battle thread and asks
+// both players to pick their teams. Tournament battles are timed out by the
+// tournament's no-show check, other battles are cancelled after
+// TeamSelectionTimeout.
+func IntroduceBattle(b *bot.Bot, battle *game.Battle) {
+	b.Client.Rest().CreateMessage(battle.ThreadID, discord.MessageCreate{
+		Content: fmt.Sprintf("The battle is about to begin! Players are selecting their teams.\nSpectators can react %s or %s on the latest turn to vote for the fan favourite.", FanVoteEmojiPlayer1, FanVoteEmojiPlayer2),
+	})
+
+	if !SendTeamSelection(b, battle, battle.Player1) || !SendTeamSelection(b, battle, battle.Player2) {
+		return
+	}
+
+	if battle.TournamentID != uuid.Nil {
+		return
+	}
+
+	if _, err := b.Scheduler.After(TeamSelectionTimeout).
+		With("battle_id", battle.ID.String()).
+		Emit(TeamSelectionTask); err != nil {
+		logger.NewLogger("services.battle").Error("Failed to schedule team selection timeout",
+			zap.String("battle_id", battle.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// cancelTeamSelection ends a battle that cannot go ahead because a player
+// cannot pick a team. Tournament battles are left to the no-show check, which
+// awards the other player a walkover.
+func cancelTeamSelection(b *bot.Bot, battle *game.Battle) {
+	if battle.TournamentID != uuid.Nil {
+		return
+	}
+	b.BattleManager.EndBattle(battle.ID)
+}
+
+// SendTeamSelection posts the team selection menu for a player in the battle
+// thread. It reports false when the player cannot pick a team, in which case
+// the battle is cancelled.
+func SendTeamSelection(b *bot.Bot, battle *game.Battle, player *game.BattlePlayer) bool {
+	log := logger.NewLogger("services.battle")
+
+	userChars, err := b.DB.GetCharactersForUser(b.Context, player.ID)
+	if err != nil {
+		log.Error("Failed to get characters for team selection",
+			logger.DiscordUserID(player.ID),
+			logger.ErrorField(err),
+		)
+		cancelTeamSelection(b, battle)
+		b.Client.Rest().CreateMessage(battle.ThreadID, discord.MessageCreate{Content: fmt.Sprintf("Failed to get characters for <@%s>", player.ID)})
+		return false
+	}
+
+	// Characters held by a market listing or auction can't battle
+	available := userChars[:0]
+	for _, char := range userChars {
+		if !char.Locked {
+			available = append(available, char)
+		}
+	}
+	userChars = available
+
+	if len(userChars) < battle.Settings.TeamSize {
+		cancelTeamSelection(b, battle)
+		b.Client.Rest().CreateMessage(battle.ThreadID, discord.MessageCreate{Content: fmt.Sprintf("<@%s> does not have enough characters to battle!", player.ID)})
+		return false
+	}
+
+	// Offer the strongest characters first, select menus are limited to 25 options
+	sort.SliceStable(userChars, func(i, j int) bool {
+		return userChars[i].Level > userChars[j].Level
+	})
+	if len(userChars) > maxTeamSelectSize {
+		userChars = userChars[:maxTeamSelectSize]
+	}
+
+	options := make([]discord.StringSelectMenuOption, 0, len(userChars))
+	for _, char := range userChars {
+		options = append(options, discord.NewStringSelectMenuOption(
+			fmt.Sprintf("#%d Lvl %d %s", char.IDX, char.Level, char.CharacterName()),
+			char.ID.String(),
+		).WithDescription(fmt.Sprintf("IV %s", char.IvPercentage())).
+			WithEmoji(discord.ComponentEmoji{Name: "⚔️"}))
+	}
+
+	selectMenu := discord.NewStringSelectMenu(
+		fmt.Sprintf("battle_team_select/%s/%s", battle.ID, player.ID),
+		"Select your team...",
+		options...,
+	).WithMaxValues(battle.Settings.TeamSize).WithMinValues(battle.Settings.TeamSize)
+
+	embed := discord.NewEmbedBuilder().
+		SetTitle("Team Selection").
+		SetDescription(fmt.Sprintf("Choose your team of %d characters for the battle.", battle.Settings.TeamSize)).
+		SetColor(constants.ColorInfo).
+		Build()
+
+	if _, err := b.Client.Rest().CreateMessage(battle.ThreadID, discord.MessageCreate{
+		Content:    fmt.Sprintf("<@%s>, it's your turn to select a team.", player.ID),
+		Embeds:     []discord.Embed{embed},
+		Components: []discord.ContainerComponent{discord.NewActionRow(selectMenu)},
+	}); err != nil {
+		log.Error("Failed to send team selection",
+			logger.DiscordUserID(player.ID),
+			logger.DiscordChannelID(battle.ThreadID),
+			logger.ErrorField(err),
+		)
+	}
+	return true
+}
+
+// SendActionPrompt posts the move and switch buttons for both players.
+func SendActionPrompt(b *bot.Bot, battle *game.Battle) {
+	log := logger.NewLogger("services.battle")
+
+	for _, player := range []*game.BattlePlayer{battle.Player1, battle.Player2} {
+		active := player.GetActiveCharacter()
+		if active == nil {
+			continue
+		}
+
+		moveButtons := make([]discord.InteractiveComponent, 0, 5)
+		for _, moveID := range active.Moves {
+			move, exists := game.GetMoveByID(int(moveID))
+			if !exists {
+				continue
+			}
+			moveButtons = append(moveButtons, discord.NewPrimaryButton(
+				move.Name,
+				fmt.Sprintf("battle_move/%s/%s/%d", battle.ID, player.ID, move.ID),
+			))
+			if len(moveButtons) == 4 {
+				break
+			}
+		}
+		moveButtons = append(moveButtons, discord.NewSecondaryButton(
+			"Pass",
+			fmt.Sprintf("battle_skip/%s/%s", battle.ID, player.ID),
+		))
+
+		rows := []discord.ContainerComponent{discord.NewActionRow(moveButtons...)}
+
+		switchButtons := make([]discord.InteractiveComponent, 0, 5)
+		for i, char := range player.Team {
+			if i == player.ActiveCharacter || char.BattleStats.IsFainted() {
+				continue
+			}
+			switchButtons = append(switchButtons, discord.NewSecondaryButton(
+				fmt.Sprintf("Switch to %s", char.CharacterName()),
+				fmt.Sprintf("battle_switch/%s/%s/%d", battle.ID, player.ID, i),
+			))
+			if len(switchButtons) == 5 {
+				break
+			}
+		}
+		if len(switchButtons) > 0 {
+			rows = append(rows, discord.NewActionRow(switchButtons...))
+		}
+
+		if _, err := b.Client.Rest().CreateMessage(battle.ThreadID, discord.MessageCreate{
+			Content:    fmt.Sprintf("<@%s>, choose an action for **%s** (turn %d).", player.ID, active.CharacterName(), battle.CurrentTurn),
+			Components: rows,
+		}); err != nil {
+			log.Error("Failed to send action prompt",
+				logger.DiscordUserID(player.ID),
+				logger.DiscordChannelID(battle.ThreadID),
+				logger.ErrorField(err),
+			)
+		}
+	}
+}
+
+// PublishTurn posts the summary of the most recent turn to the battle thread and
+// adds the fan favourite voting reactions. Votes move to the new message.
+func PublishTurn(b *bot.Bot, battle *game.Battle) {
+	log := logger.NewLogger("services.battle")
+
+	msg, err := b.Client.Rest().CreateMessage(battle.ThreadID, discord.MessageCreate{
+		Embeds: []discord.Embed{TurnSummaryEmbed(battle.TurnSummary())},
+	})
+	if err != nil {
+		log.Error("Failed to publish turn summary",
+			logger.DiscordChannelID(battle.ThreadID),
+			zap.String("battle_id", battle.ID.String()),
+			logger.ErrorField(err),
+		)
+		return
+	}
+	b.BattleManager.SetVoteMessage(battle.ID, msg.ID)
+
+	for _, emoji := range []string{FanVoteEmojiPlayer1, FanVoteEmojiPlayer2} {
+		if err := b.Client.Rest().AddReaction(msg.ChannelID, msg.ID, emoji); err != nil {
+			log.Warn("Failed to add fan vote reaction",
+				logger.DiscordMessageID(msg.ID),
+				logger.ErrorField(err),
+			)
+		}
+	}
+}
+
+// FinishBattle ends a finished battle in the battle manager and announces the result.
+func FinishBattle(b *bot.Bot, battle *game.Battle) {
+	log := logger.NewLogger("services.battle")
+
+	if err := b.BattleManager.EndBattle(battle.ID); err != nil {
+		log.Error("Failed to end battle",
+			zap.String("battle_id", battle.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+
+	if replay := battle.Replay(); replay != nil {
+		if err := b.DB.CreateBattleReplay(b.Context, replay); err != nil {
+			log.Error("Failed to save battle replay",
+				zap.String("battle_id", battle.ID.String()),
+				logger.ErrorField(err),
+			)
+		}
+	}
+
+	result := "The battle ended in a draw!"
+	if battle.Winner != nil {
+		result = fmt.Sprintf("🏆 <@%s> wins the battle!", *battle.Winner)
+	}
+
+	embed := discord.NewEmbedBuilder().
+		SetTitle("Battle Over").
+		SetDescription(result).
+		SetColor(constants.ColorSuccess).
+		AddField("Turns", fmt.Sprint(battle.CurrentTurn), true).
+		SetFooterTextf("Watch the replay with /battle replay id:%s", game.ShortBattleID(battle.ID))
+
+	ratingChanges, err := applyRankedResult(b, battle)
+	if err != nil {
+		log.Error("Failed to update ratings",
+			zap.String("battle_id", battle.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+	if len(ratingChanges) > 0 {
+		lines := make([]string, 0, len(ratingChanges))
+		for _, change := range ratingChanges {
+			lines = append(lines, change.String())
+		}
+		embed.AddField("Rating", strings.Join(lines, "\n"), false)
+	}
+
+	reward, err := payBattleReward(b.Context, b, battle)
+	if err != nil {
+		log.Error("Failed to pay battle reward",
+			zap.String("battle_id", battle.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+	if reward > 0 {
+		embed.AddField("Reward", fmt.Sprintf("<@%s> earned %d coins", *battle.Winner, reward), true)
+
+		// XP shares the daily cap on rewarded wins, so farming battles doesn't pay
+		if err := awardBattleXP(b.Context, b, battle); err != nil {
+			log.Error("Failed to award battle XP",
+				zap.String("battle_id", battle.ID.String()),
+				logger.ErrorField(err),
+			)
+		} else {
+			embed.AddField("XP", fmt.Sprintf("The winning team gained %d XP each", game.BattleWinXP), true)
+		}
+	}
+
+	if favourite, votes, ok := battle.FanFavourite(); ok {
+		embed.AddField("Fan Favourite", fmt.Sprintf("<@%s> (%d votes)", favourite, votes), true)
+	}
+
+	if _, err := b.Client.Rest().CreateMessage(battle.ThreadID, discord.MessageCreate{
+		Embeds: []discord.Embed{embed.Build()},
+	}); err != nil {
+		log.Error("Failed to announce battle result",
+			logger.DiscordChannelID(battle.ThreadID),
+			logger.ErrorField(err),
+		)
+	}
+
+	if battle.Winner != nil {
+		loser := battle.GetOpponent(*battle.Winner)
+		if err := b.DB.RecordBattleResult(b.Context, *battle.Winner, loser.ID); err != nil {
+			log.Error("Failed to record battle result",
+				zap.String("battle_id", battle.ID.String()),
+				logger.ErrorField(err),
+			)
+		}
+	}
+	refreshLeaderboards(b, battle.Player1.ID, battle.Player2.ID)
+
+	if err := recordTournamentResult(b, battle); err != nil {
+		log.Error("Failed to record tournament result",
+			zap.String("battle_id", battle.ID.String()),
+			zap.String("tournament_id", battle.TournamentID.String()),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// TurnSummaryEmbed renders a turn summary as an embed.
+func TurnSummaryEmbed(summary game.TurnSummary) discord.Embed {
+	embed := discord.NewEmbedBuilder()
+	WriteTurnSummary(embed, summary)
+	return embed.Build()
+}
+
+// WriteTurnSummary renders a turn summary into an existing embed builder.
+func WriteTurnSummary(embed *discord.EmbedBuilder, summary game.TurnSummary) {
+	title := fmt.Sprintf("Turn %d/%d", summary.Turn, summary.MaxTurns)
+	if summary.Turn == 0 {
+		title = "Battle Start"
+	}
+
+	events := "*Nothing happened yet.*"
+	if len(summary.Events) > 0 {
+		events = TruncateField(strings.Join(summary.Events, "\n"))
+	}
+
+	embed.SetTitle(title).
+		SetColor(constants.ColorInfo).
+		ClearFields().
+		AddField(sideTitle(summary.Player1, FanVoteEmojiPlayer1), sideDescription(summary.Player1), true).
+		AddField(sideTitle(summary.Player2, FanVoteEmojiPlayer2), sideDescription(summary.Player2), true).
+		AddField("Events", events, false).
+		SetFooterTextf("Battle %s • React %s or %s to vote for the fan favourite", game.ShortBattleID(summary.BattleID), FanVoteEmojiPlayer1, FanVoteEmojiPlayer2)
+
+	if summary.State == game.BattleStateFinished {
+		embed.SetColor(constants.ColorSuccess)
+	}
+}
+
+func sideTitle(side game.SideSummary, emoji string) string {
+	return fmt.Sprintf("%s Player (%d/%d left)", emoji, side.AliveCount, side.TeamSize)
+}
+
+func sideDescription(side game.SideSummary) string {
+	if side.Active == "" {
+		return fmt.Sprintf("<@%s>\n*No active character*", side.PlayerID)
+	}
+
+	statuses := make([]string, 0, len(side.Statuses))
+	for _, status := range side.Statuses {
+		if icon, ok := statusIcons[status]; ok {
+			statuses = append(statuses, icon)
+		}
+	}
+
+	return fmt.Sprintf("<@%s>\n**%s** Lvl %d %s\n%s %d/%d HP\n👏 %d",
+		side.PlayerID,
+		side.Active,
+		side.Level,
+		strings.Join(statuses, ""),
+		HPBar(side.HP, side.MaxHP),
+		side.HP,
+		side.MaxHP,
+		side.FanVotes,
+	)
+}
+
+// HPBar renders a coloured HP bar.
+func HPBar(hp, maxHP int) string {
+	if maxHP <= 0 {
+		return strings.Repeat("⬛", hpBarLength)
+	}
+
+	filled := (hp*hpBarLength + maxHP - 1) / maxHP
+	if filled > hpBarLength {
+		filled = hpBarLength
+	}
+	if filled < 0 {
+		filled = 0
+	}
+
+	segment := "🟩"
+	switch {
+	case hp*100 <= maxHP*20:
+		segment = "🟥"
+	case hp*100 <= maxHP*50:
+		segment = "🟨"
+	}
+
+	return strings.Repeat(segment, filled) + strings.Repeat("⬛", hpBarLength-filled)
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/scheduler/tasks"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const HintCooldown = 30 * time.Second // How often a user can ask for a hint
+
+var (
+	ErrNothingToClaim  = errors.New("there's no character to catch here")
+	ErrAlreadyClaimed  = errors.New("this character has already been caught or wandered away")
+	ErrHintOnCooldown  = errors.New("you asked for a hint recently, try again in a bit")
+	ErrHintsNotEnabled = errors.New("this server catches characters with the claim button, no hints needed")
+)
+
+// ClaimSpawn gives a spawned character to a user. The spawn is taken from the
+// cache atomically, so only one claim wins even across bot processes.
+func ClaimSpawn(ctx context.Context, b *bot.Bot, channelID snowflake.ID, spawnID uuid.UUID, userID snowflake.ID) (*game.Character, error) {
+	log := logger.NewLogger("services.claim")
+
+	if _, err := b.DB.EnsureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	character, err := b.Cache.TakeChannelCharacter(channelID, spawnID)
+	if err != nil {
+		return nil, err
+	}
+	if character == nil {
+		return nil, ErrAlreadyClaimed
+	}
+
+	character.OwnerID = userID.String()
+	character.ClaimedTimestamp = time.Now()
+	if _, err := b.DB.CreateCharacter(ctx, userID, character); err != nil {
+		// Put the spawn back so someone can still catch it, unless another
+		// spawn took its place
+		character.OwnerID = ""
+		if _, err := b.Cache.SetChannelCharacter(channelID, character); err != nil {
+			log.Warn("Failed to restore unclaimed character",
+				logger.DiscordChannelID(channelID),
+				logger.CharacterID(character.ID),
+				logger.ErrorField(err),
+			)
+		}
+		return nil, err
+	}
+
+	log.Info("Character claimed",
+		logger.DiscordUserID(userID),
+		logger.DiscordChannelID(channelID),
+		logger.CharacterID(character.ID),
+		logger.CharacterName(character.CharacterName()),
+		zap.Int("character_index", character.IDX),
+	)
+
+	if character.Shiny {
+		if err := b.DB.IncrementShiniesCaught(ctx, userID); err != nil {
+			log.Error("Failed to count shiny catch",
+				logger.DiscordUserID(userID),
+				logger.ErrorField(err),
+			)
+		}
+	}
+
+	rewardDexMilestones(ctx, b, userID)
+
+	if err := RefreshLeaderboards(ctx, b, userID); err != nil {
+		log.Warn("Failed to refresh leaderboards",
+			logger.DiscordUserID(userID),
+			logger.ErrorField(err),
+		)
+	}
+
+	if err := closeSpawn(b, channelID, character, userID); err != nil {
+		log.Warn("Failed to close spawn message",
+			logger.DiscordChannelID(channelID),
+			logger.CharacterID(character.ID),
+			logger.ErrorField(err),
+		)
+	}
+
+	return character, nil
+}
+
+// closeSpawn cancels a claimed spawn's expiry and shows who caught it.
+func closeSpawn(b *bot.Bot, channelID snowflake.ID, character *game.Character, userID snowflake.ID) error {
+	messageID, err := b.Cache.GetSpawnMessage(character.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Scheduler.Cancel("default", tasks.SpawnTaskID(messageID)); err != nil {
+		// The expiry finds the spawn gone and does nothing, so this is harmless
+		logger.NewLogger("services.claim").Debug("Failed to cancel spawn expiry",
+			logger.DiscordMessageID(messageID),
+			logger.ErrorField(err),
+		)
+	}
+
+	message, err := b.Client.Rest().GetMessage(channelID, messageID)
+	if err != nil {
+		return err
+	}
+
+	update := discord.NewMessageUpdateBuilder()
+	if len(message.Embeds) > 0 {
+		embed := message.Embeds[0]
+		embed.Title = fmt.Sprintf("%s was caught!", character.CharacterName())
+		embed.Description = fmt.Sprintf("Caught by <@%s>.", userID)
+		update.SetEmbeds(embed)
+	}
+	if button, exists := message.ButtonByID(tasks.SpawnButtonID(character.ID)); exists {
+		update.AddActionRow(button.AsDisabled().WithLabel("Claimed"))
+	}
+
+	_, err = b.Client.Rest().UpdateMessage(channelID, messageID, update.Build())
+	return err
+}
+
+// SpawnHint reveals more of the name of the character spawned in a channel.
+func SpawnHint(ctx context.Context, b *bot.Bot, guildID, channelID, userID snowflake.ID) (string, error) {
+	settings, err := GuildSettings(ctx, b, guildID)
+	if err != nil {
+		return "", err
+	}
+	if !settings.CatchByName {
+		return "", ErrHintsNotEnabled
+	}
+
+	character, err := b.Cache.GetChannelCharacter(channelID)
+	if err != nil || character == nil {
+		return "", ErrNothingToClaim
+	}
+
+	if b.Cache.IsHintOnCooldown(userID) {
+		return "", ErrHintOnCooldown
+	}
+	if err := b.Cache.SetHintCooldown(userID, HintCooldown); err != nil {
+		return "", err
+	}
+
+	level, err := b.Cache.IncrementHintLevel(character.ID)
+	if err != nil {
+		return "", err
+	}
+
+	return character.NameHint(level), nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/db"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ClaimDexMilestones pays a user the dex milestones they reached since they
+// were last rewarded, and returns them.
+func ClaimDexMilestones(ctx context.Context, b *bot.Bot, userID snowflake.ID) ([]game.DexMilestone, error) {
+	var reached []game.DexMilestone
+
+	err := b.DB.Tx(ctx, func(tx db.Store) error {
+		user, err := tx.GetUser(ctx, userID)
+		if err != nil || user == nil {
+			return err
+		}
+
+		dex, err := tx.GetDex(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		reached = dex.ReachedDexMilestones(user.DexMilestones)
+		if len(reached) == 0 {
+			return nil
+		}
+
+		claimed, err := tx.ClaimDexMilestones(ctx, userID, user.DexMilestones, user.DexMilestones+len(reached))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			// Another catch paid them first
+			reached = nil
+			return nil
+		}
+
+		for _, milestone := range reached {
+			reference := fmt.Sprintf("%d%% dex completion", milestone.Percent)
+			if _, err := tx.AdjustBalance(ctx, userID, milestone.Coins, game.ReasonDexMilestone, reference); err != nil {
+				return err
+			}
+			if milestone.ItemID != 0 {
+				if _, err := tx.AddItem(ctx, userID, milestone.ItemID, 1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reached) > 0 {
+		logger.NewLogger("services.dex").Info("Dex milestones reached",
+			logger.DiscordUserID(userID),
+			zap.Int("milestones", len(reached)),
+			zap.Int("percent", reached[len(reached)-1].Percent),
+		)
+		refreshLeaderboards(b, userID)
+	}
+
+	return reached, nil
+}
+
+// rewardDexMilestones pays the milestones a new species reached and tells the
+// user by DM, where a failure is not worth failing the catch over.
+func rewardDexMilestones(ctx context.Context, b *bot.Bot, userID snowflake.ID) {
+	reached, err := ClaimDexMilestones(ctx, b, userID)
+	if err != nil {
+		logger.NewLogger("services.dex").Error("Failed to reward dex milestones",
+			logger.DiscordUserID(userID),
+			logger.ErrorField(err),
+		)
+		return
+	}
+	if len(reached) == 0 {
+		return
+	}
+
+	notifyUser(b, userID, discord.MessageCreate{
+		Embeds: []discord.Embed{DexMilestoneEmbed(reached)},
+	})
+}
+
+// DexCompletionEmbed shows how much of the dex a user caught, every species'
+// counts and the completion milestones.
+func DexCompletionEmbed(user discord.User, dex game.Dex, claimed int) discord.Embed {
+	total := game.DexSize()
+	species := dex.Species()
+
+	lines := make([]string, 0, total)
+	for _, character := range game.DexCharacters() {
+		entry, ok := dex[character.ID]
+		if !ok || entry.Caught == 0 {
+			lines = append(lines, fmt.Sprintf("`#%03d` %s: not caught", character.ID, character.Name))
+			continue
+		}
+		line := fmt.Sprintf("`#%03d` **%s**: %d caught", character.ID, character.Name, entry.Caught)
+		if entry.Shinies > 0 {
+			line += fmt.Sprintf(", %d ✨", entry.Shinies)
+		}
+		lines = append(lines, line)
+	}
+
+	milestones := make([]string, len(game.DexMilestones))
+	for i, milestone := range game.DexMilestones {
+		status := fmt.Sprintf("%d/%d", min(species, milestone.Species()), milestone.Species())
+		if i < claimed {
+			status = "✅"
+		} else if species >= milestone.Species() {
+			status = "🎁 ready to claim"
+		}
+		milestones[i] = fmt.Sprintf("**%d%%** %s: %s", milestone.Percent, status, milestone.Rewards())
+	}
+
+	return discord.NewEmbedBuilder().
+		SetTitlef("%s's Dex", user.Username).
+		SetThumbnail(user.EffectiveAvatarURL()).
+		SetColor(constants.ColorInfo).
+		SetDescription(strings.Join(lines, "\n")).
+		AddField("Species Caught", fmt.Sprintf("%d/%d (%d%%)", species, total, species*100/total), true).
+		AddField("Shiny Species", fmt.Sprintf("%d/%d", dex.ShinySpecies(), total), true).
+		AddField("Milestones", strings.Join(milestones, "\n"), false).
+		SetFooterText("Evolving into a form registers it too. Use /dex <species> for details.").
+		Build()
+}
+
+// DexClaimButton lets a user claim the dex milestones they reached but weren't
+// paid for.
+func DexClaimButton(userID snowflake.ID) discord.ActionRowComponent {
+	return discord.NewActionRow(
+		discord.NewSuccessButton("Claim rewards", fmt.Sprintf("/dex_claim/%s", userID)),
+	)
+}
+
+// DexMilestoneEmbed announces the dex milestones a user reached and their
+// rewards.
+func DexMilestoneEmbed(milestones []game.DexMilestone) discord.Embed {
+	lines := make([]string, len(milestones))
+	for i, milestone := range milestones {
+		lines[i] = fmt.Sprintf("**%d%%** (%d species): %s", milestone.Percent, milestone.Species(), milestone.Rewards())
+	}
+
+	return discord.NewEmbedBuilder().
+		SetTitle("📖 Dex Milestone!").
+		SetDescription("You caught enough species to earn a completion reward.").
+		SetColor(constants.ColorSuccess).
+		AddField("Rewards", strings.Join(lines, "\n"), false).
+		Build()
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/db"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// DailyClaim is the result of claiming the daily reward.
+type DailyClaim struct {
+	Reward  int
+	Streak  int
+	Balance int
+}
+
+// ClaimDaily gives the user their daily reward, which grows with the number of
+// days in a row they have claimed it.
+func ClaimDaily(ctx context.Context, b *bot.Bot, userID snowflake.ID) (*DailyClaim, error) {
+	user, err := b.DB.EnsureUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	streak, ok := game.NextDailyStreak(user.LastDailyAt, user.DailyStreak, now)
+	if !ok {
+		return nil, fmt.Errorf("you already claimed your daily reward, come back <t:%d:R>", game.DailyDay(now).Add(24*time.Hour).Unix())
+	}
+
+	claim := &DailyClaim{Reward: game.DailyReward(streak), Streak: streak}
+	err = b.DB.Tx(ctx, func(tx db.Store) error {
+		claimed, err := tx.ClaimDaily(ctx, userID, user.LastDailyAt, streak, now)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return fmt.Errorf("you already claimed your daily reward")
+		}
+
+		claim.Balance, err = tx.AdjustBalance(ctx, userID, claim.Reward, game.ReasonDaily, fmt.Sprintf("day %d", streak))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claim, nil
+}
+
+// BuyItem buys items from the shop into the user's inventory, returning the
+// total price.
+func BuyItem(ctx context.Context, b *bot.Bot, userID snowflake.ID, item game.Item, quantity int) (int, error) {
+	if item.Price <= 0 {
+		return 0, fmt.Errorf("the shop doesn't sell %s", item.Name)
+	}
+	if quantity <= 0 || quantity > game.MaxShopQuantity {
+		return 0, fmt.Errorf("you can buy between 1 and %d at once", game.MaxShopQuantity)
+	}
+
+	if _, err := b.DB.EnsureUser(ctx, userID); err != nil {
+		return 0, err
+	}
+
+	price := item.Price * quantity
+	err := b.DB.Tx(ctx, func(tx db.Store) error {
+		if _, err := tx.AdjustBalance(ctx, userID, -price, game.ReasonShop, fmt.Sprintf("%dx %s", quantity, item.Name)); err != nil {
+			if errors.Is(err, db.ErrInsufficientBalance) {
+				return fmt.Errorf("you need %d coins to buy this", price)
+			}
+			return err
+		}
+
+		_, err := tx.AddItem(ctx, userID, item.ID, quantity)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return price, nil
+}
+
+// payBattleReward pays the winner of a battle, up to a daily number of wins so
+// battles between friends can't be farmed. It returns the coins paid.
+func payBattleReward(ctx context.Context, b *bot.Bot, battle *game.Battle) (int, error) {
+	if battle.Winner == nil {
+		return 0, nil
+	}
+	winner := *battle.Winner
+
+	// The winner's row is held while today's wins are counted, so battles
+	// ending together can't both be paid past the daily cap
+	reward := 0
+	err := b.DB.Tx(ctx, func(tx db.Store) error {
+		user, err := tx.LockUser(ctx, winner)
+		if err != nil || user == nil {
+			return err
+		}
+
+		rewarded, err := tx.CountTransactions(ctx, winner, game.ReasonBattleWin, game.DailyDay(time.Now()))
+		if err != nil {
+			return err
+		}
+		if rewarded >= game.MaxBattleRewardsPerDay {
+			return nil
+		}
+
+		reward = game.BattleReward(battle.Ranked)
+		_, err = tx.AdjustBalance(ctx, winner, reward, game.ReasonBattleWin, "battle "+game.ShortBattleID(battle.ID))
+		return err
+	})
+	if err != nil || reward == 0 {
+		return 0, err
+	}
+
+	logger.NewLogger("services.economy").Info("Battle reward paid",
+		logger.DiscordUserID(winner),
+		zap.String("battle_id", battle.ID.String()),
+		zap.Int("reward", reward),
+	)
+	return reward, nil
+}
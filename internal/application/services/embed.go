@@ -0,0 +1,23 @@
+package services
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TruncateField keeps embed field values within Discord's 1024 character limit.
+func TruncateField(value string) string {
+	if len(value) <= 1024 {
+		return value
+	}
+
+	cut := strings.LastIndex(value[:1020], "\n")
+	if cut < 0 {
+		// Back up to the start of a rune so none is cut in half
+		cut = 1020
+		for cut > 0 && !utf8.RuneStart(value[cut]) {
+			cut--
+		}
+	}
+	return value[:cut] + "\n…"
+}
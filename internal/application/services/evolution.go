@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/db"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrEvolutionNotOwned = errors.New("you don't own that character")
+	ErrEvolutionLocked   = errors.New("that character is up for sale on the market or in an auction")
+	ErrEvolutionInBattle = errors.New("that character is in a battle")
+)
+
+// PrepareEvolution finds the evolution one of the user's characters can take
+// now, so it can be confirmed. Nothing changes until it is.
+func PrepareEvolution(ctx context.Context, b *bot.Bot, userID snowflake.ID, characterID uuid.UUID) (*game.Character, game.Evolution, error) {
+	character, err := b.DB.GetCharacter(ctx, characterID)
+	if err != nil {
+		return nil, game.Evolution{}, err
+	}
+	if character == nil || character.OwnerID != userID.String() {
+		return nil, game.Evolution{}, ErrEvolutionNotOwned
+	}
+
+	evolutions := character.Data().Evolutions()
+	if len(evolutions) == 0 {
+		return nil, game.Evolution{}, fmt.Errorf("%s doesn't evolve", character.CharacterName())
+	}
+
+	inventory, err := b.DB.GetInventory(ctx, userID)
+	if err != nil {
+		return nil, game.Evolution{}, err
+	}
+
+	evolution, ok := character.AvailableEvolution(inventory)
+	if !ok {
+		requirements := make([]string, len(evolutions))
+		for i, evolution := range evolutions {
+			requirements[i] = fmt.Sprintf("into %s %s", evolution.Form().Name, evolution.Requirement())
+		}
+		return nil, game.Evolution{}, fmt.Errorf("%s evolves %s", character.CharacterName(), strings.Join(requirements, ", or "))
+	}
+
+	return character, evolution, nil
+}
+
+// ConfirmEvolution evolves one of the user's characters into the given form,
+// taking the item the evolution needs in the same transaction, and registers
+// the form in their dex. Everything is checked again, since the prompt may
+// have waited a while.
+func ConfirmEvolution(ctx context.Context, b *bot.Bot, userID snowflake.ID, characterID uuid.UUID, formID int) (*game.Character, []game.LearnedMove, error) {
+	if b.BattleManager.IsCharacterInBattle(characterID) {
+		return nil, nil, ErrEvolutionInBattle
+	}
+
+	var character *game.Character
+	var learned []game.LearnedMove
+	var fromName string
+
+	err := b.DB.Tx(ctx, func(tx db.Store) error {
+		var err error
+		character, err = tx.LockCharacter(ctx, characterID)
+		if err != nil {
+			return err
+		}
+		if character == nil || character.OwnerID != userID.String() {
+			return ErrEvolutionNotOwned
+		}
+		if character.Locked {
+			return ErrEvolutionLocked
+		}
+		// A battle may have started while the row was being locked
+		if b.BattleManager.IsCharacterInBattle(character.ID) {
+			return ErrEvolutionInBattle
+		}
+
+		var evolution *game.Evolution
+		for _, e := range character.Data().Evolutions() {
+			if e.FormID == formID {
+				evolution = &e
+				break
+			}
+		}
+		if evolution == nil {
+			return fmt.Errorf("%s can't evolve into that form", character.CharacterName())
+		}
+
+		fromName = character.CharacterName()
+		learned, err = character.Evolve(*evolution)
+		if err != nil {
+			return err
+		}
+
+		if item, ok := evolution.Item(); ok {
+			if err := tx.RemoveItem(ctx, userID, item.ID, 1); err != nil {
+				if errors.Is(err, db.ErrNotEnoughItems) {
+					return fmt.Errorf("you need %s to evolve %s", item.NameWithArticle(), fromName)
+				}
+				return err
+			}
+		}
+
+		if _, err := tx.UpdateCharacter(ctx, character.ID, character); err != nil {
+			return err
+		}
+
+		return tx.RecordDexEntry(ctx, userID, character.CharacterID, character.Shiny)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.NewLogger("services.evolution").Info("Character evolved",
+		logger.DiscordUserID(userID),
+		logger.CharacterID(character.ID),
+		zap.String("from", fromName),
+		logger.CharacterName(character.CharacterName()),
+	)
+
+	rewardDexMilestones(ctx, b, userID)
+
+	return character, learned, nil
+}
+
+// EvolutionButtons returns the buttons that confirm or cancel an evolution.
+func EvolutionButtons(characterID uuid.UUID, evolution game.Evolution) discord.ActionRowComponent {
+	return discord.NewActionRow(
+		discord.NewSuccessButton("Evolve", fmt.Sprintf("/evolve_confirm/%s/%d", characterID, evolution.FormID)),
+		discord.NewSecondaryButton("Cancel", fmt.Sprintf("/evolve_cancel/%s", characterID)),
+	)
+}
+
+// EvolutionEmbed asks to confirm an evolution, showing how the character's
+// stats change.
+func EvolutionEmbed(character *game.Character, evolution game.Evolution) discord.Embed {
+	evolved := *character
+	evolved.CharacterID = evolution.FormID
+
+	stats := []struct {
+		name          string
+		before, after int
+	}{
+		{"HP", character.MaxHP(), evolved.MaxHP()},
+		{"Attack", character.Atk(), evolved.Atk()},
+		{"Defense", character.Def(), evolved.Def()},
+		{"Sp. Atk", character.SpAtk(), evolved.SpAtk()},
+		{"Sp. Def", character.SpDef(), evolved.SpDef()},
+		{"Speed", character.Spd(), evolved.Spd()},
+	}
+	lines := make([]string, len(stats))
+	for i, stat := range stats {
+		lines[i] = fmt.Sprintf("**%s:** %d → %d", stat.name, stat.before, stat.after)
+	}
+
+	embed := discord.NewEmbedBuilder().
+		SetTitlef("Evolve %s?", character.Format("n")).
+		SetDescription(fmt.Sprintf("Your **%s** is ready to evolve into **%s**!", character.Format("ln"), evolution.Form().Name)).
+		SetColor(constants.ColorWarn).
+		AddField("Stats", strings.Join(lines, "\n"), true).
+		SetFooterText("Level, IVs, nickname and shininess carry over. This can't be undone.")
+
+	if item, ok := evolution.Item(); ok {
+		embed.AddField("Uses", fmt.Sprintf("%s %s", item.Emoji, item.Name), true)
+	}
+
+	return embed.Build()
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+)
+
+const guildSettingsCacheTTL = 10 * time.Minute
+
+// GuildSettings returns a guild's settings, from the cache when possible.
+// Guilds that never changed anything get the defaults.
+func GuildSettings(ctx context.Context, b *bot.Bot, guildID snowflake.ID) (game.GuildSettings, error) {
+	if settings, err := b.Cache.GetGuildSettings(guildID); err == nil {
+		return *settings, nil
+	}
+
+	settings, err := b.DB.GetGuildSettings(ctx, guildID)
+	if err != nil {
+		return game.DefaultGuildSettings(guildID), err
+	}
+	if settings == nil {
+		defaults := game.DefaultGuildSettings(guildID)
+		settings = &defaults
+	}
+
+	if err := b.Cache.SetGuildSettings(settings, guildSettingsCacheTTL); err != nil {
+		logger.NewLogger("services.guild_settings").Warn("Failed to cache guild settings",
+			logger.DiscordGuildID(guildID),
+			logger.ErrorField(err),
+		)
+	}
+
+	return *settings, nil
+}
+
+// UpdateGuildSettings changes a guild's settings with update and saves them if
+// they are still valid.
+func UpdateGuildSettings(ctx context.Context, b *bot.Bot, guildID snowflake.ID, update func(*game.GuildSettings)) (game.GuildSettings, error) {
+	settings, err := b.DB.GetGuildSettings(ctx, guildID)
+	if err != nil {
+		return game.GuildSettings{}, err
+	}
+	if settings == nil {
+		defaults := game.DefaultGuildSettings(guildID)
+		settings = &defaults
+	}
+
+	update(settings)
+	if err := settings.Validate(); err != nil {
+		return game.GuildSettings{}, err
+	}
+
+	if err := b.DB.SaveGuildSettings(ctx, *settings); err != nil {
+		return game.GuildSettings{}, err
+	}
+
+	if err := b.Cache.DeleteGuildSettings(guildID); err != nil {
+		logger.NewLogger("services.guild_settings").Warn("Failed to clear cached guild settings",
+			logger.DiscordGuildID(guildID),
+			logger.ErrorField(err),
+		)
+	}
+
+	return *settings, nil
+}
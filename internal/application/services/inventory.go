@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/db"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// UseItem uses one of the user's items on one of their characters. The item
+// is taken from the inventory, the character saved and any level-up rewards
+// paid in one transaction, with its row locked so nothing else changes it
+// meanwhile.
+func UseItem(ctx context.Context, b *bot.Bot, userID snowflake.ID, item game.Item, characterID uuid.UUID, personality constants.Personality) (*game.Character, game.ItemUse, error) {
+	var character *game.Character
+	var use game.ItemUse
+
+	if b.BattleManager.IsCharacterInBattle(characterID) {
+		return nil, use, fmt.Errorf("that character is in a battle")
+	}
+
+	err := b.DB.Tx(ctx, func(tx db.Store) error {
+		var err error
+		character, err = tx.LockCharacter(ctx, characterID)
+		if err != nil {
+			return err
+		}
+		if character == nil || character.OwnerID != userID.String() {
+			return fmt.Errorf("you don't own that character")
+		}
+		if character.Locked {
+			return fmt.Errorf("that character is up for sale on the market or in an auction")
+		}
+		if b.BattleManager.IsCharacterInBattle(character.ID) {
+			return fmt.Errorf("that character is in a battle")
+		}
+
+		use, err = game.UseItem(item, character, personality)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.RemoveItem(ctx, userID, item.ID, 1); err != nil {
+			if errors.Is(err, db.ErrNotEnoughItems) {
+				return fmt.Errorf("you don't have a %s", item.Name)
+			}
+			return err
+		}
+		if _, ok := game.ItemsRegistry[use.ReturnedItem]; ok {
+			if _, err := tx.AddItem(ctx, userID, use.ReturnedItem, 1); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.SaveCharacterStats(ctx, character); err != nil {
+			return err
+		}
+
+		if use.LevelUp.Coins > 0 {
+			reference := fmt.Sprintf("%s level %d", character.CharacterName(), use.LevelUp.ToLevel)
+			if _, err := tx.AdjustBalance(ctx, userID, use.LevelUp.Coins, game.ReasonLevelUp, reference); err != nil {
+				return err
+			}
+		}
+		for _, reward := range use.LevelUp.Items {
+			if _, err := tx.AddItem(ctx, userID, reward, 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, use, err
+	}
+
+	logger.NewLogger("services.inventory").Info("Item used",
+		logger.DiscordUserID(userID),
+		logger.CharacterID(character.ID),
+		zap.Int("item_id", item.ID),
+	)
+
+	if use.LevelUp.ToLevel > 0 || item.RerollIVs {
+		refreshLeaderboards(b, userID)
+	}
+
+	return character, use, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const LeaderboardSize = 100 // Number of players shown on a leaderboard
+
+// GetLeaderboard returns the top players of a category, globally or in a guild
+// when guildID is set.
+func GetLeaderboard(ctx context.Context, b *bot.Bot, category game.LeaderboardCategory, guildID snowflake.ID) ([]game.LeaderboardEntry, error) {
+	if err := ensureLeaderboards(ctx, b); err != nil {
+		return nil, err
+	}
+
+	return b.Leaderboard.Top(ctx, category, guildID, LeaderboardSize)
+}
+
+// GetLeaderboardRank returns a player's position on a leaderboard, or nil if
+// they are not ranked in it.
+func GetLeaderboardRank(ctx context.Context, b *bot.Bot, category game.LeaderboardCategory, guildID, userID snowflake.ID) (*game.LeaderboardEntry, error) {
+	if err := ensureLeaderboards(ctx, b); err != nil {
+		return nil, err
+	}
+
+	return b.Leaderboard.Rank(ctx, category, guildID, userID)
+}
+
+// RefreshLeaderboards recalculates the scores of players whose stats changed.
+func RefreshLeaderboards(ctx context.Context, b *bot.Bot, userIDs ...snowflake.ID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	stats, err := b.DB.GetLeaderboardStats(ctx, userIDs...)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range stats {
+		if err := b.Leaderboard.Update(ctx, s.UserID, s.Scores(b.RatingSystem)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// refreshLeaderboards refreshes players' scores after a write, where a stale
+// leaderboard is not worth failing the write over.
+func refreshLeaderboards(b *bot.Bot, userIDs ...snowflake.ID) {
+	if err := RefreshLeaderboards(b.Context, b, userIDs...); err != nil {
+		logger.NewLogger("services.leaderboard").Error("Failed to refresh leaderboards",
+			zap.Int("users", len(userIDs)),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// TrackGuildMember records that a user plays in a guild, adding them to the
+// guild's leaderboards the first time they are seen there.
+func TrackGuildMember(ctx context.Context, b *bot.Bot, guildID, userID snowflake.ID) error {
+	added, err := b.Leaderboard.AddGuildMember(ctx, guildID, userID)
+	if err != nil || !added {
+		return err
+	}
+
+	if err := b.DB.AddGuildMember(ctx, guildID, userID); err != nil {
+		return err
+	}
+
+	return RefreshLeaderboards(ctx, b, userID)
+}
+
+// RebuildLeaderboards fills the leaderboards from the database. This scans
+// every user's characters, so it only runs when Redis has no leaderboards and
+// after changes that affect everyone, like season soft resets.
+func RebuildLeaderboards(ctx context.Context, b *bot.Bot) error {
+	memberships, err := b.DB.GetGuildMemberships(ctx)
+	if err != nil {
+		return err
+	}
+
+	members := 0
+	for userID, guildIDs := range memberships {
+		members += len(guildIDs)
+		for _, guildID := range guildIDs {
+			if _, err := b.Leaderboard.AddGuildMember(ctx, guildID, userID); err != nil {
+				return err
+			}
+		}
+	}
+
+	stats, err := b.DB.GetLeaderboardStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range stats {
+		if err := b.Leaderboard.Update(ctx, s.UserID, s.Scores(b.RatingSystem)); err != nil {
+			return err
+		}
+	}
+
+	logger.NewLogger("services.leaderboard").Info("Leaderboards rebuilt",
+		zap.Int("users", len(stats)),
+		zap.Int("guild_members", members),
+	)
+
+	return b.Leaderboard.MarkBuilt(ctx)
+}
+
+func ensureLeaderboards(ctx context.Context, b *bot.Bot) error {
+	built, err := b.Leaderboard.Built(ctx)
+	if err != nil || built {
+		return err
+	}
+
+	return RebuildLeaderboards(ctx, b)
+}
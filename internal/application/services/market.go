@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/db"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"github.com/theoreotm/friemon/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	MarketExpiryTask     = "market_expiry"
+	MarketExpiryInterval = 10 * time.Minute
+	marketExpiryBatch    = 100
+)
+
+// SetupMarket registers the periodic task that takes expired listings off the market.
+func SetupMarket(b *bot.Bot) error {
+	b.Scheduler.On(MarketExpiryTask, func(ctx context.Context, _ types.TaskData) error {
+		return ExpireListings(ctx, b)
+	})
+
+	return b.Scheduler.Every(MarketExpiryInterval, MarketExpiryTask)
+}
+
+// ListCharacter puts one of the seller's characters up for sale. The character
+// is locked until it sells, is unlisted or the listing expires.
+func ListCharacter(ctx context.Context, b *bot.Bot, sellerID snowflake.ID, characterID uuid.UUID, price int) (*game.MarketListing, error) {
+	if price <= 0 || price > game.MaxMarketPrice {
+		return nil, fmt.Errorf("the price must be between 1 and %d coins", game.MaxMarketPrice)
+	}
+
+	if _, err := escrowableCharacter(ctx, b, sellerID, characterID); err != nil {
+		return nil, err
+	}
+
+	listings, err := b.DB.CountListings(ctx, game.MarketFilter{SellerID: sellerID})
+	if err != nil {
+		return nil, err
+	}
+	if listings >= game.MaxMarketListings {
+		return nil, fmt.Errorf("you can have at most %d listings at once", game.MaxMarketListings)
+	}
+
+	listing, err := b.DB.CreateListing(ctx, sellerID, characterID, price, time.Now().Add(game.MarketListingDuration))
+	if errors.Is(err, db.ErrCharacterLocked) {
+		return nil, fmt.Errorf("that character is already up for sale")
+	}
+	return listing, err
+}
+
+// escrowableCharacter returns one of the user's characters if it can be locked
+// for a market listing or auction.
+func escrowableCharacter(ctx context.Context, b *bot.Bot, userID snowflake.ID, characterID uuid.UUID) (*game.Character, error) {
+	user, err := b.DB.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("you haven't started playing yet")
+	}
+
+	character, err := b.DB.GetCharacter(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if character == nil || character.OwnerID != userID.String() {
+		return nil, fmt.Errorf("you don't own that character")
+	}
+	if character.Locked {
+		return nil, fmt.Errorf("that character is already up for sale")
+	}
+	if character.ID == user.SelectedID {
+		return nil, fmt.Errorf("your selected character can't be sold, select another one first")
+	}
+	if b.BattleManager.IsCharacterInBattle(character.ID) {
+		return nil, fmt.Errorf("that character is in a battle")
+	}
+
+	return character, nil
+}
+
+// UnlistCharacter takes one of the seller's listings off the market.
+func UnlistCharacter(ctx context.Context, b *bot.Bot, sellerID snowflake.ID, listingID int) (*game.MarketListing, error) {
+	listing, err := b.DB.GetListing(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing == nil || listing.SellerID != sellerID {
+		return nil, fmt.Errorf("you don't have a listing with ID %d", listingID)
+	}
+
+	listing, err = b.DB.DeleteListing(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing == nil {
+		return nil, fmt.Errorf("that listing was just sold")
+	}
+
+	return listing, nil
+}
+
+// BuyListing buys a character from the market. The buyer pays the full price,
+// the seller receives it minus the market fee and the character changes owner,
+// all in one transaction.
+func BuyListing(ctx context.Context, b *bot.Bot, buyerID snowflake.ID, listingID int) (*game.MarketListing, error) {
+	listing, err := b.DB.GetListing(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing == nil {
+		return nil, fmt.Errorf("there is no listing with ID %d", listingID)
+	}
+	if listing.SellerID == buyerID {
+		return nil, fmt.Errorf("you can't buy your own listing, unlist it instead")
+	}
+
+	if _, err := b.DB.EnsureUser(ctx, buyerID); err != nil {
+		return nil, err
+	}
+
+	err = b.DB.Tx(ctx, func(tx db.Store) error {
+		// Deleting first means concurrent buyers wait on the row, and only the
+		// first of them finds it
+		sold, err := tx.DeleteListing(ctx, listingID)
+		if err != nil {
+			return err
+		}
+		if sold == nil {
+			return fmt.Errorf("that listing is no longer available")
+		}
+		listing = sold
+
+		if _, err := tx.AdjustBalance(ctx, buyerID, -listing.Price, game.ReasonMarketPurchase, fmt.Sprintf("listing #%d", listing.ID)); err != nil {
+			if errors.Is(err, db.ErrInsufficientBalance) {
+				return fmt.Errorf("you need %d coins to buy this", listing.Price)
+			}
+			return err
+		}
+		if _, err := tx.AdjustBalance(ctx, listing.SellerID, listing.SellerProceeds(), game.ReasonMarketSale, fmt.Sprintf("listing #%d", listing.ID)); err != nil {
+			return err
+		}
+
+		return tx.TransferCharacter(ctx, listing.Character.ID, buyerID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.NewLogger("services.market").Info("Listing sold",
+		zap.Int("listing_id", listing.ID),
+		logger.DiscordUserID(buyerID),
+		zap.String("seller_id", listing.SellerID.String()),
+		zap.Int("price", listing.Price),
+	)
+
+	notifyUser(b, listing.SellerID, discord.MessageCreate{
+		Embeds: []discord.Embed{{
+			Title: "Market sale",
+			Description: fmt.Sprintf("Your **%s No. %d** sold to <@%s> for **%d coins**. You received **%d coins** after the %d%% market fee.",
+				listing.Character.Format("ln"), listing.Character.IDX, buyerID, listing.Price, listing.SellerProceeds(), game.MarketFeePercent),
+			Color: constants.ColorSuccess,
+		}},
+	})
+
+	refreshLeaderboards(b, buyerID, listing.SellerID)
+	return listing, nil
+}
+
+// ExpireListings takes listings past their expiry off the market, returning
+// the characters to their sellers.
+func ExpireListings(ctx context.Context, b *bot.Bot) error {
+	log := logger.NewLogger("services.market")
+
+	for {
+		expired, err := b.DB.GetExpiredListings(ctx, time.Now(), marketExpiryBatch)
+		if err != nil {
+			return err
+		}
+
+		for _, listing := range expired {
+			removed, err := b.DB.DeleteListing(ctx, listing.ID)
+			if err != nil {
+				return err
+			}
+			if removed == nil {
+				continue
+			}
+
+			log.Info("Listing expired", zap.Int("listing_id", listing.ID), logger.DiscordUserID(listing.SellerID))
+			notifyUser(b, listing.SellerID, discord.MessageCreate{
+				Embeds: []discord.Embed{{
+					Title:       "Market listing expired",
+					Description: fmt.Sprintf("Your **%s No. %d** didn't sell and has been returned to you.", removed.Character.Format("ln"), removed.Character.IDX),
+					Color:       constants.ColorInfo,
+				}},
+			})
+		}
+
+		if len(expired) < marketExpiryBatch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"github.com/theoreotm/friemon/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	MatchmakingTask     = "ranked_matchmaking"
+	MatchmakingInterval = 15 * time.Second
+)
+
+// SetupMatchmaking registers the periodic ranked matchmaking task.
+func SetupMatchmaking(b *bot.Bot) error {
+	if b.Cfg.Bot.RankedChannel == 0 {
+		logger.NewLogger("services.matchmaking").Info("Ranked channel not configured, matchmaking disabled")
+		return nil
+	}
+
+	b.Scheduler.On(MatchmakingTask, func(ctx context.Context, _ types.TaskData) error {
+		return RunMatchmaking(ctx, b)
+	})
+
+	return b.Scheduler.Every(MatchmakingInterval, MatchmakingTask)
+}
+
+// RunMatchmaking pairs players waiting in the ranked queue and starts their battles.
+// Players who are already in a battle stay queued but are skipped.
+func RunMatchmaking(ctx context.Context, b *bot.Bot) error {
+	log := logger.NewLogger("services.matchmaking")
+
+	entries, err := b.RankedQueue.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	available := make([]game.QueueEntry, 0, len(entries))
+	for _, entry := range entries {
+		if !b.BattleManager.IsPlayerInBattle(entry.PlayerID) {
+			available = append(available, entry)
+		}
+	}
+
+	for _, match := range game.FindMatches(available, time.Now()) {
+		if err := startRankedMatch(ctx, b, match); err != nil {
+			log.Error("Failed to start ranked match",
+				zap.String("player1_id", match.Player1.PlayerID.String()),
+				zap.String("player2_id", match.Player2.PlayerID.String()),
+				logger.ErrorField(err),
+			)
+		}
+	}
+
+	return nil
+}
+
+func startRankedMatch(ctx context.Context, b *bot.Bot, match game.Match) error {
+	// Claim both players, a player that left the queue in the meantime cancels the match
+	left1, err := b.RankedQueue.Leave(ctx, match.Player1.PlayerID)
+	if err != nil {
+		return err
+	}
+	left2, err := b.RankedQueue.Leave(ctx, match.Player2.PlayerID)
+	if err != nil {
+		return err
+	}
+	if !left1 || !left2 {
+		requeue(ctx, b, match, left1, left2)
+		return nil
+	}
+
+	battle, err := b.BattleManager.CreateMatch(match.Player1.PlayerID, match.Player2.PlayerID, b.Cfg.Bot.RankedChannel, game.DefaultGameSettings(), game.MatchOptions{Ranked: true})
+	if err != nil {
+		requeue(ctx, b, match, true, true)
+		return err
+	}
+
+	thread, err := b.Client.Rest().CreateThread(b.Cfg.Bot.RankedChannel, discord.GuildPublicThreadCreate{
+		Name:                fmt.Sprintf("Ranked: %d vs %d", match.Player1.Rating, match.Player2.Rating),
+		AutoArchiveDuration: discord.AutoArchiveDuration1h,
+	})
+	if err != nil {
+		b.BattleManager.EndBattle(battle.ID)
+		requeue(ctx, b, match, true, true)
+		return fmt.Errorf("failed to create battle thread: %w", err)
+	}
+
+	if err := b.BattleManager.SetBattleThread(battle.ID, thread.ID()); err != nil {
+		return err
+	}
+
+	b.Client.Rest().CreateMessage(b.Cfg.Bot.RankedChannel, discord.MessageCreate{
+		Content: fmt.Sprintf("<@%s> <@%s>", match.Player1.PlayerID, match.Player2.PlayerID),
+		Embeds: []discord.Embed{discord.NewEmbedBuilder().
+			SetTitle("Ranked Match Found").
+			SetDescriptionf("<@%s> (%d) vs <@%s> (%d)\nHead to %s to pick your teams!",
+				match.Player1.PlayerID, match.Player1.Rating,
+				match.Player2.PlayerID, match.Player2.Rating,
+				thread.Mention(),
+			).
+			SetColor(constants.ColorInfo).
+			Build()},
+	})
+
+	IntroduceBattle(b, battle)
+
+	return nil
+}
+
+// requeue puts claimed players back in the queue when their match falls through.
+func requeue(ctx context.Context, b *bot.Bot, match game.Match, player1, player2 bool) {
+	if player1 {
+		b.RankedQueue.Join(ctx, match.Player1)
+	}
+	if player2 {
+		b.RankedQueue.Join(ctx, match.Player2)
+	}
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+)
+
+// notifyUser sends a player a direct message. Players can close their DMs, so
+// failures are only logged.
+func notifyUser(b *bot.Bot, userID snowflake.ID, message discord.MessageCreate) {
+	log := logger.NewLogger("services.notify")
+
+	channel, err := b.Client.Rest().CreateDMChannel(userID)
+	if err != nil {
+		log.Warn("Failed to open DM channel", logger.DiscordUserID(userID), logger.ErrorField(err))
+		return
+	}
+
+	if _, err := b.Client.Rest().CreateMessage(channel.ID(), message); err != nil {
+		log.Warn("Failed to send DM", logger.DiscordUserID(userID), logger.ErrorField(err))
+	}
+}
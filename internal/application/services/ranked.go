@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"github.com/theoreotm/friemon/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	RatingDecayTask = "rating_decay"
+	RatingPeriod    = 24 * time.Hour // Inactivity period after which rating deviation grows
+)
+
+// RatingChange is a player's rating before and after a ranked battle.
+type RatingChange struct {
+	PlayerID snowflake.ID
+	Old      int
+	New      int
+}
+
+func (rc RatingChange) String() string {
+	return fmt.Sprintf("<@%s>: %d → %d (%+d)", rc.PlayerID, rc.Old, rc.New, rc.New-rc.Old)
+}
+
+// applyRankedResult updates both players' ratings after a ranked battle.
+func applyRankedResult(b *bot.Bot, battle *game.Battle) ([]RatingChange, error) {
+	if !battle.Ranked || !battle.Settings.ELOEnabled {
+		return nil, nil
+	}
+
+	user1, err := b.DB.EnsureUser(b.Context, battle.Player1.ID)
+	if err != nil {
+		return nil, err
+	}
+	user2, err := b.DB.EnsureUser(b.Context, battle.Player2.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := 0.5
+	if battle.Winner != nil {
+		switch *battle.Winner {
+		case battle.Player1.ID:
+			result = 1
+		case battle.Player2.ID:
+			result = 0
+		}
+	}
+
+	rating1, rating2 := user1.Rating(), user2.Rating()
+	new1 := b.RatingSystem.Update(rating1, rating2, result)
+	new2 := b.RatingSystem.Update(rating2, rating1, 1-result)
+
+	changes := []RatingChange{
+		{PlayerID: user1.ID, Old: rating1.Value(), New: new1.Value()},
+		{PlayerID: user2.ID, Old: rating2.Value(), New: new2.Value()},
+	}
+
+	now := time.Now()
+	if err := b.DB.UpdateRating(b.Context, user1.ID, new1, &now); err != nil {
+		return nil, err
+	}
+	if err := b.DB.UpdateRating(b.Context, user2.ID, new2, &now); err != nil {
+		return nil, err
+	}
+
+	if err := recordSeasonResult(b, battle, changes); err != nil {
+		return changes, err
+	}
+
+	return changes, nil
+}
+
+// SetupRatingDecay registers the periodic task that grows the rating deviation
+// of inactive players.
+func SetupRatingDecay(b *bot.Bot) error {
+	b.Scheduler.On(RatingDecayTask, func(ctx context.Context, _ types.TaskData) error {
+		return DecayRatings(ctx, b)
+	})
+
+	return b.Scheduler.Every(RatingPeriod, RatingDecayTask)
+}
+
+// DecayRatings applies one rating period of inactivity to players who have not
+// played ranked within the last period.
+func DecayRatings(ctx context.Context, b *bot.Bot) error {
+	users, err := b.DB.GetInactiveRatedUsers(ctx, time.Now().Add(-RatingPeriod))
+	if err != nil {
+		return err
+	}
+
+	decayed := make([]snowflake.ID, 0, len(users))
+	for _, user := range users {
+		rating := user.Rating()
+		newRating := b.RatingSystem.Decay(rating, 1)
+		if newRating == rating {
+			continue
+		}
+
+		if err := b.DB.UpdateRating(ctx, user.ID, newRating, nil); err != nil {
+			return err
+		}
+		decayed = append(decayed, user.ID)
+	}
+
+	if err := RefreshLeaderboards(ctx, b, decayed...); err != nil {
+		return err
+	}
+
+	logger.NewLogger("services.ranked").Debug("Rating decay applied",
+		zap.String("rating_system", b.RatingSystem.Name()),
+		zap.Int("users", len(decayed)),
+	)
+
+	return nil
+}
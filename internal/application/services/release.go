@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/db"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	ReleaseConfirmTimeout = 2 * time.Minute // How long a release waits for confirmation
+	MaxBulkRelease        = 500             // Most characters released at once
+)
+
+var (
+	ErrReleaseNotOwned   = errors.New("you don't own that character")
+	ErrReleaseFavourite  = errors.New("favourite characters can't be released, unfavourite it first")
+	ErrReleaseSelected   = errors.New("your selected character can't be released, select another one first")
+	ErrReleaseListed     = errors.New("that character is up for sale on the market or in an auction")
+	ErrReleaseInBattle   = errors.New("that character is in a battle")
+	ErrReleaseNotFound   = errors.New("this release has expired, run the command again")
+	ErrNothingToRelease  = errors.New("none of your characters can be released with those filters")
+	ErrReleaseNotStarted = errors.New("you haven't started playing yet")
+)
+
+// PrepareRelease checks that a single character can be released and holds it
+// until the release is confirmed.
+func PrepareRelease(ctx context.Context, b *bot.Bot, userID snowflake.ID, characterID uuid.UUID) (*game.PendingRelease, []game.Character, error) {
+	user, err := b.DB.GetUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, ErrReleaseNotStarted
+	}
+
+	character, err := b.DB.GetCharacter(ctx, characterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if character == nil || character.OwnerID != userID.String() {
+		return nil, nil, ErrReleaseNotOwned
+	}
+	if character.Favourite {
+		return nil, nil, ErrReleaseFavourite
+	}
+	if character.ID == user.SelectedID {
+		return nil, nil, ErrReleaseSelected
+	}
+	if character.Locked {
+		return nil, nil, ErrReleaseListed
+	}
+	if b.BattleManager.IsCharacterInBattle(character.ID) {
+		return nil, nil, ErrReleaseInBattle
+	}
+
+	characters := []game.Character{*character}
+	release, err := holdRelease(b, userID, characters)
+	if err != nil {
+		return nil, nil, err
+	}
+	return release, characters, nil
+}
+
+// PrepareBulkRelease holds every releasable character matching the filter until
+// the release is confirmed. Favourites, listed characters, characters in a
+// battle and the selected character are skipped.
+func PrepareBulkRelease(ctx context.Context, b *bot.Bot, userID snowflake.ID, filter game.CharacterFilter) (*game.PendingRelease, []game.Character, error) {
+	user, err := b.DB.GetUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, ErrReleaseNotStarted
+	}
+
+	notFavourite := false
+	filter.Favourite = &notFavourite
+
+	matching, err := b.DB.ListCharacters(ctx, userID, filter, game.OrderOptions{OrderBy: game.OrderByIDX}, 0, MaxBulkRelease+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	characters := make([]game.Character, 0, len(matching))
+	for _, character := range matching {
+		if character.ID != user.SelectedID && !character.Locked && !b.BattleManager.IsCharacterInBattle(character.ID) {
+			characters = append(characters, character)
+		}
+	}
+	if len(characters) > MaxBulkRelease {
+		characters = characters[:MaxBulkRelease]
+	}
+	if len(characters) == 0 {
+		return nil, nil, ErrNothingToRelease
+	}
+
+	release, err := holdRelease(b, userID, characters)
+	if err != nil {
+		return nil, nil, err
+	}
+	return release, characters, nil
+}
+
+// ConfirmRelease releases the characters of a pending release in one
+// transaction and pays their value to the owner. Characters that were traded,
+// favourited, listed, selected or sent into battle since the release was
+// prepared are left alone.
+func ConfirmRelease(ctx context.Context, b *bot.Bot, releaseID uuid.UUID, userID snowflake.ID) (released int, coins int, err error) {
+	release, err := b.Cache.TakePendingRelease(releaseID, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if release == nil {
+		return 0, 0, ErrReleaseNotFound
+	}
+
+	err = b.DB.Tx(ctx, func(tx db.Store) error {
+		released, coins = 0, 0
+
+		user, err := tx.GetUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrReleaseNotStarted
+		}
+
+		for _, characterID := range release.CharacterIDs {
+			character, err := tx.LockCharacter(ctx, characterID)
+			if err != nil {
+				return err
+			}
+			if character == nil || character.OwnerID != userID.String() || character.Favourite || character.Locked || character.ID == user.SelectedID {
+				continue
+			}
+			if b.BattleManager.IsCharacterInBattle(character.ID) {
+				continue
+			}
+
+			if _, err := tx.DeleteCharacter(ctx, character.ID); err != nil {
+				return err
+			}
+			released++
+			coins += game.ReleaseValue(*character)
+		}
+
+		if coins == 0 {
+			return nil
+		}
+
+		_, err = tx.AdjustBalance(ctx, userID, coins, game.ReasonRelease, fmt.Sprintf("%d characters", released))
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if released > 0 {
+		logger.NewLogger("services.release").Info("Characters released",
+			logger.DiscordUserID(userID),
+			zap.Int("released", released),
+			zap.Int("coins", coins),
+		)
+		refreshLeaderboards(b, userID)
+	}
+
+	return released, coins, nil
+}
+
+// CancelRelease drops a pending release, reporting whether there was one.
+func CancelRelease(b *bot.Bot, releaseID uuid.UUID, userID snowflake.ID) (bool, error) {
+	release, err := b.Cache.TakePendingRelease(releaseID, userID)
+	return release != nil, err
+}
+
+// holdRelease keeps a release in the cache until it is confirmed, cancelled or
+// ReleaseConfirmTimeout runs out.
+func holdRelease(b *bot.Bot, userID snowflake.ID, characters []game.Character) (*game.PendingRelease, error) {
+	release := &game.PendingRelease{
+		ID:           uuid.New(),
+		UserID:       userID,
+		CharacterIDs: make([]uuid.UUID, len(characters)),
+	}
+	for i, character := range characters {
+		release.CharacterIDs[i] = character.ID
+		release.Value += game.ReleaseValue(character)
+	}
+
+	if err := b.Cache.SetPendingRelease(release, ReleaseConfirmTimeout); err != nil {
+		return nil, err
+	}
+	return release, nil
+}
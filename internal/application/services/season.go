@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/db"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"github.com/theoreotm/friemon/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	SeasonRolloverTask     = "season_rollover"
+	SeasonRolloverInterval = 10 * time.Minute
+)
+
+// SetupSeasons registers the periodic task that ends seasons and hands out rewards.
+func SetupSeasons(b *bot.Bot) error {
+	b.Scheduler.On(SeasonRolloverTask, func(ctx context.Context, _ types.TaskData) error {
+		return RolloverSeason(ctx, b)
+	})
+
+	return b.Scheduler.Every(SeasonRolloverInterval, SeasonRolloverTask)
+}
+
+// CurrentSeason returns the active season, starting the first one if needed.
+func CurrentSeason(ctx context.Context, b *bot.Bot) (*game.Season, error) {
+	season, err := b.DB.GetActiveSeason(ctx)
+	if err != nil || season != nil {
+		return season, err
+	}
+
+	now := time.Now()
+	return b.DB.CreateSeason(ctx, game.Season{
+		Number:   1,
+		StartsAt: now,
+		EndsAt:   now.Add(game.SeasonLength),
+		Active:   true,
+	})
+}
+
+// RolloverSeason ends the active season once it is over: standings are archived,
+// ratings are soft reset and the next season starts. Rewards for archived
+// standings are then granted.
+func RolloverSeason(ctx context.Context, b *bot.Bot) error {
+	season, err := CurrentSeason(ctx, b)
+	if err != nil {
+		return err
+	}
+
+	if season.Remaining(time.Now()) == 0 {
+		if err := endSeason(ctx, b, season); err != nil {
+			return err
+		}
+	}
+
+	return grantSeasonRewards(ctx, b)
+}
+
+func endSeason(ctx context.Context, b *bot.Bot, season *game.Season) error {
+	log := logger.NewLogger("services.season")
+
+	standings, err := b.DB.GetSeasonStandings(ctx, season.ID)
+	if err != nil {
+		return err
+	}
+	game.RankStandings(standings)
+
+	var next *game.Season
+	err = b.DB.Tx(ctx, func(tx db.Store) error {
+		if err := tx.ArchiveSeason(ctx, season.ID, standings); err != nil {
+			return err
+		}
+		if err := tx.SoftResetRatings(ctx); err != nil {
+			return err
+		}
+
+		next, err = tx.CreateSeason(ctx, season.Next(time.Now()))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to end season %d: %w", season.Number, err)
+	}
+
+	log.Info("Season ended",
+		zap.Int("season", season.Number),
+		zap.Int("standings", len(standings)),
+		zap.Int("next_season", next.Number),
+	)
+
+	announceSeasonEnd(b, season, next, standings)
+
+	// Every rating changed with the soft reset
+	if err := RebuildLeaderboards(ctx, b); err != nil {
+		log.Error("Failed to rebuild leaderboards", logger.ErrorField(err))
+	}
+
+	return nil
+}
+
+func grantSeasonRewards(ctx context.Context, b *bot.Bot) error {
+	log := logger.NewLogger("services.season")
+
+	standings, err := b.DB.GetUnrewardedStandings(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, standing := range standings {
+		if err := grantSeasonReward(ctx, b, standing); err != nil {
+			log.Error("Failed to grant season reward",
+				logger.DiscordUserID(standing.UserID),
+				zap.Int("season_id", standing.SeasonID),
+				logger.ErrorField(err),
+			)
+		}
+	}
+
+	return nil
+}
+
+// grantSeasonReward pays a standing's reward in one transaction. The standing
+// is marked rewarded first, so a reward another run already paid is skipped.
+func grantSeasonReward(ctx context.Context, b *bot.Bot, standing game.SeasonStanding) error {
+	reward := standing.Reward()
+
+	paid := false
+	err := b.DB.Tx(ctx, func(tx db.Store) error {
+		marked, err := tx.MarkStandingRewarded(ctx, standing.SeasonID, standing.UserID)
+		if err != nil || !marked {
+			return err
+		}
+
+		if reward.Balance > 0 {
+			if _, err := tx.AdjustBalance(ctx, standing.UserID, reward.Balance, game.ReasonSeasonReward, fmt.Sprintf("season %d", standing.SeasonID)); err != nil {
+				return err
+			}
+		}
+
+		if reward.Shiny {
+			char := game.NewCharacter(standing.UserID.String())
+			char.Shiny = true
+			if _, err := tx.CreateCharacter(ctx, standing.UserID, char); err != nil {
+				return err
+			}
+		}
+
+		paid = true
+		return nil
+	})
+	if err != nil || !paid {
+		return err
+	}
+
+	refreshLeaderboards(b, standing.UserID)
+	return nil
+}
+
+// recordSeasonResult counts a ranked battle toward both players' season records.
+func recordSeasonResult(b *bot.Bot, battle *game.Battle, changes []RatingChange) error {
+	season, err := CurrentSeason(b.Context, b)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		result := game.SeasonResultDraw
+		if battle.Winner != nil {
+			result = game.SeasonResultLoss
+			if *battle.Winner == change.PlayerID {
+				result = game.SeasonResultWin
+			}
+		}
+
+		if err := b.DB.RecordSeasonResult(b.Context, season.ID, change.PlayerID, change.New, result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func announceSeasonEnd(b *bot.Bot, season, next *game.Season, standings []game.SeasonStanding) {
+	if b.Cfg.Bot.RankedChannel == 0 {
+		return
+	}
+
+	description := "Nobody played ranked this season."
+	if len(standings) > 0 {
+		description = ""
+		for _, standing := range standings[:min(len(standings), 10)] {
+			description += fmt.Sprintf("**#%d** %s <@%s> • %d\n", standing.Rank, standing.Bracket.Emoji(), standing.UserID, standing.Rating)
+		}
+	}
+
+	b.Client.Rest().CreateMessage(b.Cfg.Bot.RankedChannel, discord.MessageCreate{
+		Embeds: []discord.Embed{discord.NewEmbedBuilder().
+			SetTitlef("Season %d has ended!", season.Number).
+			SetDescription(description).
+			SetColor(constants.ColorSuccess).
+			SetFooterTextf("Ratings have been soft reset. Season %d ends %s", next.Number, next.EndsAt.Format("2006-01-02")).
+			Build()},
+	})
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	messageRateWindow  = time.Minute
+	duplicateWindow    = 5 * time.Minute  // How long a message counts as a repeat of the next one
+	spamReportCooldown = 10 * time.Minute // How often the same violation is reported
+)
+
+// CheckMessage decides whether a message counts toward spawns and XP. It
+// returns the reason it doesn't, and false when it does.
+func CheckMessage(b *bot.Bot, settings game.GuildSettings, userID snowflake.ID, content string) (game.SpamReason, bool, error) {
+	if game.MessageLength(content) < settings.MinMessageLength {
+		return game.SpamTooShort, true, nil
+	}
+
+	fingerprint := game.MessageFingerprint(content)
+	previous, err := b.Cache.SwapLastMessage(userID, fingerprint, duplicateWindow)
+	if err != nil {
+		return 0, false, err
+	}
+	if settings.BlockDuplicates && previous != "" && previous == fingerprint {
+		return game.SpamDuplicate, true, nil
+	}
+
+	if settings.MessageRateLimit > 0 {
+		count, err := b.Cache.IncrementMessageRate(settings.GuildID, userID, messageRateWindow)
+		if err != nil {
+			return 0, false, err
+		}
+		if count > settings.MessageRateLimit {
+			return game.SpamRateLimited, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// CountSpawnContribution counts a user's message toward a channel's next spawn,
+// and reports false once they sent more than their share of it. The share only
+// applies once someone else is talking too, so a quiet channel with one player
+// still spawns.
+func CountSpawnContribution(b *bot.Bot, settings game.GuildSettings, channelID, userID snowflake.ID) (bool, error) {
+	limit := settings.MaxUserSpawnMessages()
+	if limit == 0 {
+		return true, nil
+	}
+
+	count, contributors, err := b.Cache.IncrementSpawnContribution(channelID, userID)
+	if err != nil {
+		return false, err
+	}
+	return contributors < 2 || count <= limit, nil
+}
+
+// ReportSpam logs a message the filter ignored, and posts it to the guild's
+// moderation log unless the same violation was posted recently.
+func ReportSpam(b *bot.Bot, settings game.GuildSettings, channelID, userID snowflake.ID, reason game.SpamReason) {
+	log := logger.NewLogger("services.spam")
+
+	log.Debug("Message filtered",
+		logger.DiscordGuildID(settings.GuildID),
+		logger.DiscordChannelID(channelID),
+		logger.DiscordUserID(userID),
+		zap.String("reason", reason.String()),
+	)
+
+	// Short messages are ordinary chat, only abuse is worth a moderator's time
+	if settings.ModLogChannelID == 0 || reason == game.SpamTooShort {
+		return
+	}
+
+	first, err := b.Cache.MarkSpamReported(settings.GuildID, userID, reason, spamReportCooldown)
+	if err != nil || !first {
+		return
+	}
+
+	embed := discord.NewEmbedBuilder().
+		SetTitle("Spam filtered").
+		SetDescription(fmt.Sprintf("Messages from <@%s> in <#%s> stopped counting toward spawns and XP.", userID, channelID)).
+		SetColor(constants.ColorWarn).
+		AddField("Reason", reason.String(), true).
+		AddField("User ID", userID.String(), true).
+		SetFooterText(fmt.Sprintf("Repeats are not reported for %s", spamReportCooldown)).
+		SetTimestamp(time.Now()).
+		Build()
+
+	if _, err := b.Client.Rest().CreateMessage(settings.ModLogChannelID, discord.MessageCreate{
+		Embeds:          []discord.Embed{embed},
+		AllowedMentions: &discord.AllowedMentions{},
+	}); err != nil {
+		log.Warn("Failed to post to moderation log",
+			logger.DiscordGuildID(settings.GuildID),
+			logger.DiscordChannelID(settings.ModLogChannelID),
+			logger.ErrorField(err),
+		)
+	}
+}
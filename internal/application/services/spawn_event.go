@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/db"
+	"github.com/theoreotm/friemon/internal/infrastructure/scheduler"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"github.com/theoreotm/friemon/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	SpawnEventRecurTask = "spawn_event_recur"
+	LureRefundTask      = "lure_refund"
+
+	spawnEventsCacheTTL = 5 * time.Minute
+)
+
+var (
+	ErrLureAlreadyPlaced = errors.New("there's already a lure waiting in this channel")
+	ErrLureNoSpawns      = errors.New("characters don't spawn in this channel")
+)
+
+// SetupSpawnEvents registers the tasks that move recurring events forward and
+// refund unused lures, and schedules the first for every recurring event.
+func SetupSpawnEvents(b *bot.Bot) error {
+	b.Scheduler.On(SpawnEventRecurTask, func(ctx context.Context, data types.TaskData) error {
+		id, ok := data.Int("event_id")
+		if !ok {
+			return fmt.Errorf("missing event_id")
+		}
+		return recurSpawnEvent(ctx, b, id)
+	})
+	b.Scheduler.On(LureRefundTask, func(ctx context.Context, data types.TaskData) error {
+		channelID, err := snowflake.Parse(data.MustString("channel_id"))
+		if err != nil {
+			return fmt.Errorf("invalid channel_id: %w", err)
+		}
+		lureID, err := uuid.Parse(data.MustString("lure_id"))
+		if err != nil {
+			return fmt.Errorf("invalid lure_id: %w", err)
+		}
+		return refundLure(ctx, b, channelID, lureID)
+	})
+
+	events, err := b.DB.GetSpawnEvents(b.Context, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		if err := scheduleSpawnEventRecurrence(b, &events[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateSpawnEvent saves a new spawn event and schedules its recurrence.
+func CreateSpawnEvent(ctx context.Context, b *bot.Bot, event game.SpawnEvent) (*game.SpawnEvent, error) {
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+
+	created, err := b.DB.CreateSpawnEvent(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	forgetSpawnEvents(b)
+
+	if err := scheduleSpawnEventRecurrence(b, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// DeleteSpawnEvent removes a spawn event, reporting whether there was one.
+func DeleteSpawnEvent(ctx context.Context, b *bot.Bot, id int) (bool, error) {
+	deleted, err := b.DB.DeleteSpawnEvent(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	forgetSpawnEvents(b)
+	return deleted, nil
+}
+
+// SpawnEvents returns the events that have not ended, running or upcoming,
+// from the cache when possible.
+func SpawnEvents(ctx context.Context, b *bot.Bot) ([]game.SpawnEvent, error) {
+	if events, err := b.Cache.GetSpawnEvents(); err == nil {
+		return events, nil
+	}
+
+	events, err := b.DB.GetSpawnEvents(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.Cache.SetSpawnEvents(events, spawnEventsCacheTTL); err != nil {
+		logger.NewLogger("services.spawn_event").Warn("Failed to cache spawn events",
+			logger.ErrorField(err),
+		)
+	}
+
+	return events, nil
+}
+
+// ActiveSpawnModifiers combines the effects of every event running now.
+func ActiveSpawnModifiers(ctx context.Context, b *bot.Bot) (game.SpawnModifiers, []game.SpawnEvent, error) {
+	modifiers := game.DefaultSpawnModifiers()
+
+	events, err := SpawnEvents(ctx, b)
+	if err != nil {
+		return modifiers, nil, err
+	}
+
+	now := time.Now()
+	var active []game.SpawnEvent
+	for _, event := range events {
+		if event.Active(now) {
+			modifiers.Apply(event)
+			active = append(active, event)
+		}
+	}
+
+	return modifiers, active, nil
+}
+
+// UseLure places one of the user's lures in the channel characters from
+// channelID spawn in.
+func UseLure(ctx context.Context, b *bot.Bot, userID, guildID, channelID snowflake.ID, item game.Item) (snowflake.ID, error) {
+	if item.Category != game.ItemCategoryLure {
+		return 0, fmt.Errorf("%s isn't a lure", item.Name)
+	}
+
+	settings, err := GuildSettings(ctx, b, guildID)
+	if err != nil {
+		return 0, err
+	}
+	if !settings.CountsMessages(channelID) {
+		return 0, ErrLureNoSpawns
+	}
+	channelID = settings.SpawnChannel(channelID)
+
+	// The lure stays cached a little past its duration, so the refund can
+	// still take it if it never forced a spawn
+	lure := &game.ChannelLure{ID: uuid.New(), ItemID: item.ID, UserID: userID}
+	placed, err := b.Cache.SetChannelLure(channelID, lure, game.LureDuration+time.Minute)
+	if err != nil {
+		return 0, err
+	}
+	if !placed {
+		return 0, ErrLureAlreadyPlaced
+	}
+
+	if err := b.DB.RemoveItem(ctx, userID, item.ID, 1); err != nil {
+		if err := b.Cache.DeleteChannelLure(channelID); err != nil {
+			logger.NewLogger("services.spawn_event").Warn("Failed to remove unpaid lure",
+				logger.DiscordChannelID(channelID),
+				logger.ErrorField(err),
+			)
+		}
+		if errors.Is(err, db.ErrNotEnoughItems) {
+			return 0, fmt.Errorf("you don't have a %s", item.Name)
+		}
+		return 0, err
+	}
+
+	if _, err := b.Scheduler.After(game.LureDuration).
+		ID(fmt.Sprintf("%s:%s", LureRefundTask, lure.ID)).
+		With("channel_id", channelID.String()).
+		With("lure_id", lure.ID.String()).
+		Emit(LureRefundTask); err != nil {
+		logger.NewLogger("services.spawn_event").Error("Failed to schedule lure refund",
+			logger.DiscordChannelID(channelID),
+			logger.ErrorField(err),
+		)
+	}
+
+	// Count the lure's messages from now on
+	if err := b.Cache.ResetInteractionCount(channelID); err != nil {
+		return 0, err
+	}
+
+	return channelID, nil
+}
+
+// refundLure gives a lure back to its owner if it is still waiting in the
+// channel once its time is up. A lure that forced a spawn is gone already.
+func refundLure(ctx context.Context, b *bot.Bot, channelID snowflake.ID, lureID uuid.UUID) error {
+	lure, err := b.Cache.TakeChannelLure(channelID, lureID)
+	if err != nil || lure == nil {
+		return err
+	}
+
+	item := lure.Item()
+	if _, err := b.DB.AddItem(ctx, lure.UserID, item.ID, 1); err != nil {
+		return err
+	}
+
+	logger.NewLogger("services.spawn_event").Info("Unused lure refunded",
+		logger.DiscordUserID(lure.UserID),
+		logger.DiscordChannelID(channelID),
+		zap.Int("item_id", item.ID),
+	)
+
+	notifyUser(b, lure.UserID, discord.MessageCreate{
+		Content: fmt.Sprintf("Nothing spawned in <#%s> while your %s %s was out, so you got it back.", channelID, item.Emoji, item.Name),
+	})
+	return nil
+}
+
+// recurSpawnEvent moves a recurring event to its next occurrence once it ended.
+func recurSpawnEvent(ctx context.Context, b *bot.Bot, id int) error {
+	event, err := b.DB.GetSpawnEvent(ctx, id)
+	if err != nil || event == nil {
+		return err
+	}
+
+	// The task fired early or the event was rescheduled meanwhile
+	if event.EndsAt.After(time.Now()) {
+		return scheduleSpawnEventRecurrence(b, event)
+	}
+
+	next, ok := event.NextOccurrence(time.Now())
+	if !ok {
+		return nil
+	}
+	if err := b.DB.RescheduleSpawnEvent(ctx, &next); err != nil {
+		return err
+	}
+	forgetSpawnEvents(b)
+
+	logger.NewLogger("services.spawn_event").Info("Spawn event recurred",
+		zap.Int("event_id", next.ID),
+		zap.String("event_name", next.Name),
+		zap.Time("starts_at", next.StartsAt),
+	)
+
+	return scheduleSpawnEventRecurrence(b, &next)
+}
+
+// forgetSpawnEvents drops the cached events after they changed.
+func forgetSpawnEvents(b *bot.Bot) {
+	if err := b.Cache.DeleteSpawnEvents(); err != nil {
+		logger.NewLogger("services.spawn_event").Warn("Failed to clear cached spawn events",
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// scheduleSpawnEventRecurrence schedules a recurring event to move forward
+// once it ends. One-off events need no task.
+func scheduleSpawnEventRecurrence(b *bot.Bot, event *game.SpawnEvent) error {
+	if event.RecurEvery <= 0 {
+		return nil
+	}
+
+	_, err := b.Scheduler.At(event.EndsAt).
+		With("event_id", event.ID).
+		ID(fmt.Sprintf("%s:%d:%d", SpawnEventRecurTask, event.ID, event.EndsAt.Unix())).
+		Emit(SpawnEventRecurTask)
+	if errors.Is(err, scheduler.ErrDuplicateTask) {
+		return nil
+	}
+	return err
+}
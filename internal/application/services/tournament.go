@@ -0,0 +1,586 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/db"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"github.com/theoreotm/friemon/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	TournamentStartTask   = "tournament_start"
+	TournamentNoShowTask  = "tournament_no_show"
+	TournamentNoShowAfter = 10 * time.Minute // Time players have to pick their teams before forfeiting
+	TournamentMatchLimit  = time.Hour        // Time a match has to finish before it is decided without it
+)
+
+// SetupTournaments registers the tasks that start tournaments at their sign-up
+// deadline and resolve matches whose players do not show up.
+func SetupTournaments(b *bot.Bot) error {
+	b.Scheduler.On(TournamentStartTask, func(ctx context.Context, data types.TaskData) error {
+		id, err := taskTournamentID(data)
+		if err != nil {
+			return err
+		}
+		return StartTournament(ctx, b, id)
+	})
+
+	b.Scheduler.On(TournamentNoShowTask, func(ctx context.Context, data types.TaskData) error {
+		id, err := taskTournamentID(data)
+		if err != nil {
+			return err
+		}
+		matchID, ok := data.Int("match_id")
+		if !ok {
+			return fmt.Errorf("missing match_id")
+		}
+		return resolveNoShow(ctx, b, id, matchID)
+	})
+
+	return nil
+}
+
+func taskTournamentID(data types.TaskData) (uuid.UUID, error) {
+	id, ok := data.String("tournament_id")
+	if !ok {
+		return uuid.Nil, fmt.Errorf("missing tournament_id")
+	}
+	return uuid.Parse(id)
+}
+
+// updateTournament loads a tournament, applies fn and saves the result. The
+// tournament's row stays locked throughout, as matches of the same tournament
+// can finish at the same time on different bot processes. fn must only change
+// the tournament, anything else is done once the change is saved.
+func updateTournament(ctx context.Context, b *bot.Bot, id uuid.UUID, fn func(*game.Tournament) error) (*game.Tournament, error) {
+	var tournament *game.Tournament
+	err := b.DB.Tx(ctx, func(tx db.Store) error {
+		var err error
+		tournament, err = tx.LockTournament(ctx, id)
+		if err != nil {
+			return err
+		}
+		if tournament == nil {
+			return fmt.Errorf("tournament not found")
+		}
+
+		if err := fn(tournament); err != nil {
+			return err
+		}
+
+		return tx.SaveTournament(ctx, tournament)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tournament, nil
+}
+
+// tournamentChange is what a change to a tournament leaves to do once it is
+// saved: battles to end, messages to post and newly paired matches to start.
+type tournamentChange struct {
+	Matches   []*game.TournamentMatch
+	Walkovers []tournamentWalkover
+	Messages  []discord.MessageCreate // Posted to the tournament thread
+	Recheck   *game.TournamentMatch   // Match whose no-show check runs again later
+}
+
+// tournamentWalkover is a match decided while its battle was still running.
+type tournamentWalkover struct {
+	Battle  game.BattleProgress
+	Message string // Posted to the battle thread once the battle is ended
+}
+
+func (c *tournamentChange) post(format string, args ...any) {
+	c.Messages = append(c.Messages, discord.MessageCreate{Content: fmt.Sprintf(format, args...)})
+}
+
+// applyTournamentChange carries out a saved change to a tournament.
+func applyTournamentChange(ctx context.Context, b *bot.Bot, t *game.Tournament, change tournamentChange) error {
+	for _, walkover := range change.Walkovers {
+		b.BattleManager.EndBattle(walkover.Battle.ID)
+		b.Client.Rest().CreateMessage(walkover.Battle.ThreadID, discord.MessageCreate{Content: walkover.Message})
+	}
+
+	for _, msg := range change.Messages {
+		b.Client.Rest().CreateMessage(t.ThreadID, msg)
+	}
+
+	advanceTournament(ctx, b, t, change.Matches)
+
+	if change.Recheck != nil {
+		return scheduleNoShowCheck(b, t, change.Recheck)
+	}
+	return nil
+}
+
+// CreateTournament opens sign-ups for a new tournament, creates the thread its
+// bracket is posted to and schedules its start at the sign-up deadline.
+func CreateTournament(ctx context.Context, b *bot.Bot, tournament *game.Tournament) error {
+	existing, err := b.DB.GetChannelTournament(ctx, tournament.ChannelID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && (existing.State == game.TournamentStateSignUp || existing.State == game.TournamentStateInProgress) {
+		return fmt.Errorf("**%s** is already running in this channel", existing.Name)
+	}
+
+	thread, err := b.Client.Rest().CreateThread(tournament.ChannelID, discord.GuildPublicThreadCreate{
+		Name:                fmt.Sprintf("🏆 %s", tournament.Name),
+		AutoArchiveDuration: discord.AutoArchiveDuration1w,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tournament thread: %w", err)
+	}
+	tournament.ThreadID = thread.ID()
+
+	if err := b.DB.SaveTournament(ctx, tournament); err != nil {
+		return err
+	}
+
+	_, err = b.Scheduler.At(tournament.SignupDeadline).
+		With("tournament_id", tournament.ID.String()).
+		ID(fmt.Sprintf("%s:%s", TournamentStartTask, tournament.ID)).
+		Emit(TournamentStartTask)
+	if err != nil {
+		return fmt.Errorf("failed to schedule tournament start: %w", err)
+	}
+
+	postBracket(b, tournament)
+	return nil
+}
+
+// JoinTournament signs a player up, recording their current rating for seeding.
+func JoinTournament(ctx context.Context, b *bot.Bot, id uuid.UUID, userID snowflake.ID) (*game.Tournament, error) {
+	user, err := b.DB.EnsureUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateTournament(ctx, b, id, func(t *game.Tournament) error {
+		return t.Join(userID, b.RatingSystem.Conservative(user.Rating()))
+	})
+}
+
+func LeaveTournament(ctx context.Context, b *bot.Bot, id uuid.UUID, userID snowflake.ID) (*game.Tournament, error) {
+	return updateTournament(ctx, b, id, func(t *game.Tournament) error {
+		return t.Leave(userID)
+	})
+}
+
+// StartTournament closes sign-ups, seeds players by their current rating and
+// starts the first round. Tournaments without enough players are cancelled.
+func StartTournament(ctx context.Context, b *bot.Bot, id uuid.UUID) error {
+	tournament, err := b.DB.GetTournament(ctx, id)
+	if err != nil {
+		return err
+	}
+	if tournament == nil || tournament.State != game.TournamentStateSignUp {
+		return nil
+	}
+
+	// Read ratings before the tournament is locked, players who join meanwhile
+	// keep the rating they signed up with
+	ratings := make(map[snowflake.ID]int, len(tournament.Participants))
+	for _, p := range tournament.Participants {
+		user, err := b.DB.EnsureUser(ctx, p.UserID)
+		if err != nil {
+			return err
+		}
+		ratings[p.UserID] = b.RatingSystem.Conservative(user.Rating())
+	}
+
+	var change tournamentChange
+	tournament, err = updateTournament(ctx, b, id, func(t *game.Tournament) error {
+		if t.State != game.TournamentStateSignUp {
+			return nil
+		}
+
+		if len(t.Participants) < game.MinTournamentPlayers {
+			t.Cancel()
+			change.Messages = append(change.Messages, discord.MessageCreate{
+				Embeds: []discord.Embed{discord.NewEmbedBuilder().
+					SetTitlef("%s has been cancelled", t.Name).
+					SetDescriptionf("At least %d players are needed to run a tournament.", game.MinTournamentPlayers).
+					SetColor(constants.ColorFail).
+					Build()},
+			})
+			return nil
+		}
+
+		for _, p := range t.Participants {
+			if rating, ok := ratings[p.UserID]; ok {
+				p.Rating = rating
+			}
+		}
+
+		var err error
+		change.Matches, err = t.Start()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return applyTournamentChange(ctx, b, tournament, change)
+}
+
+// recordTournamentResult advances the tournament a finished battle was a match of.
+func recordTournamentResult(b *bot.Bot, battle *game.Battle) error {
+	if battle.TournamentID == uuid.Nil {
+		return nil
+	}
+
+	var change tournamentChange
+	tournament, err := updateTournament(b.Context, b, battle.TournamentID, func(t *game.Tournament) error {
+		match := t.MatchForBattle(battle.ID)
+		if match == nil || match.Done {
+			return nil
+		}
+
+		var err error
+		change.Matches, err = t.RecordResult(match.ID, battle.Winner, false)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return applyTournamentChange(b.Context, b, tournament, change)
+}
+
+// resolveNoShow awards a walkover for a match whose players did not pick their
+// teams in time. Matches that are still being played are checked again later,
+// until they run past TournamentMatchLimit and are decided as they stand.
+func resolveNoShow(ctx context.Context, b *bot.Bot, id uuid.UUID, matchID int) error {
+	var change tournamentChange
+	tournament, err := updateTournament(ctx, b, id, func(t *game.Tournament) error {
+		match := t.Match(matchID)
+		if t.State != game.TournamentStateInProgress || match == nil || match.Done {
+			return nil
+		}
+
+		battle, exists := b.BattleManager.GetBattleProgress(match.BattleID)
+		if exists {
+			switch battle.State {
+			case game.BattleStateInProgress:
+				if time.Since(battle.StartedAt) < TournamentMatchLimit {
+					change.Recheck = match
+					return nil
+				}
+				return timeOutMatch(t, match, battle, &change)
+			case game.BattleStateFinished:
+				return nil // The result is recorded when the battle is announced
+			}
+		}
+
+		present1 := exists && battle.TeamSize[0] > 0
+		present2 := exists && battle.TeamSize[1] > 0
+		winner := walkoverWinner(t, match, present1, present2)
+
+		var err error
+		change.Matches, err = t.RecordResult(match.ID, &winner, true)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			change.Walkovers = append(change.Walkovers, tournamentWalkover{
+				Battle:  battle,
+				Message: fmt.Sprintf("Team selection timed out. <@%s> advances by walkover.", winner),
+			})
+		}
+		change.post("Match %d: <@%s> advances by walkover.", match.ID, winner)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return applyTournamentChange(ctx, b, tournament, change)
+}
+
+// timeOutMatch decides a match that ran too long in favour of the player with
+// more characters standing, or the higher seed when they are level.
+func timeOutMatch(t *game.Tournament, match *game.TournamentMatch, battle game.BattleProgress, change *tournamentChange) error {
+	winner := walkoverWinner(t, match, true, true)
+	switch alive1, alive2 := battle.Alive[0], battle.Alive[1]; {
+	case alive1 > alive2:
+		winner = match.Player1
+	case alive2 > alive1:
+		winner = match.Player2
+	}
+
+	var err error
+	change.Matches, err = t.RecordResult(match.ID, &winner, true)
+	if err != nil {
+		return err
+	}
+
+	change.Walkovers = append(change.Walkovers, tournamentWalkover{
+		Battle:  battle,
+		Message: fmt.Sprintf("The match ran out of time. <@%s> advances.", winner),
+	})
+	change.post("Match %d ran out of time: <@%s> advances.", match.ID, winner)
+	return nil
+}
+
+// walkoverWinner picks who advances when a match cannot be played: the only
+// player who showed up, otherwise the higher seed.
+func walkoverWinner(t *game.Tournament, match *game.TournamentMatch, present1, present2 bool) snowflake.ID {
+	if present1 != present2 {
+		if present1 {
+			return match.Player1
+		}
+		return match.Player2
+	}
+
+	if t.Participant(match.Player2).Seed < t.Participant(match.Player1).Seed {
+		return match.Player2
+	}
+	return match.Player1
+}
+
+// tournamentBattle is the battle created for a tournament match.
+type tournamentBattle struct {
+	MatchID int
+	Battle  *game.Battle
+	Thread  *discord.GuildThread
+}
+
+// matchWalkover is a match that could not be started.
+type matchWalkover struct {
+	MatchID int
+	Winner  snowflake.ID
+}
+
+// advanceTournament starts the battles of a newly paired round and posts the
+// updated bracket. Matches that cannot be started are decided by walkover,
+// which may in turn pair further rounds.
+func advanceTournament(ctx context.Context, b *bot.Bot, t *game.Tournament, matches []*game.TournamentMatch) {
+	log := logger.NewLogger("services.tournament")
+
+	if len(matches) == 0 && t.State != game.TournamentStateFinished {
+		return
+	}
+
+	for len(matches) > 0 {
+		started := make([]tournamentBattle, 0, len(matches))
+		walkovers := make([]matchWalkover, 0)
+		for _, match := range matches {
+			battle, err := createTournamentBattle(b, t, match)
+			if err == nil {
+				started = append(started, battle)
+				continue
+			}
+
+			log.Warn("Failed to start tournament match",
+				zap.String("tournament_id", t.ID.String()),
+				zap.Int("match_id", match.ID),
+				logger.ErrorField(err),
+			)
+			walkovers = append(walkovers, matchWalkover{
+				MatchID: match.ID,
+				Winner: walkoverWinner(t, match,
+					!b.BattleManager.IsPlayerInBattle(match.Player1),
+					!b.BattleManager.IsPlayerInBattle(match.Player2),
+				),
+			})
+		}
+
+		var next []*game.TournamentMatch
+		updated, err := updateTournament(ctx, b, t.ID, func(tournament *game.Tournament) error {
+			next = nil
+			for _, battle := range started {
+				if match := tournament.Match(battle.MatchID); match != nil {
+					match.BattleID = battle.Battle.ID
+				}
+			}
+
+			for _, walkover := range walkovers {
+				if match := tournament.Match(walkover.MatchID); match == nil || match.Done {
+					continue
+				}
+				paired, err := tournament.RecordResult(walkover.MatchID, &walkover.Winner, true)
+				if err != nil {
+					return err
+				}
+				next = append(next, paired...)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Error("Failed to save tournament matches",
+				zap.String("tournament_id", t.ID.String()),
+				logger.ErrorField(err),
+			)
+			for _, battle := range started {
+				b.BattleManager.EndBattle(battle.Battle.ID)
+			}
+			return
+		}
+		t = updated
+
+		for _, battle := range started {
+			announceTournamentBattle(b, t, battle)
+		}
+		for _, walkover := range walkovers {
+			b.Client.Rest().CreateMessage(t.ThreadID, discord.MessageCreate{
+				Content: fmt.Sprintf("Match %d could not be started. <@%s> advances by walkover.", walkover.MatchID, walkover.Winner),
+			})
+		}
+		matches = next
+	}
+
+	postBracket(b, t)
+}
+
+func createTournamentBattle(b *bot.Bot, t *game.Tournament, match *game.TournamentMatch) (tournamentBattle, error) {
+	battle, err := b.BattleManager.CreateMatch(match.Player1, match.Player2, t.ChannelID, t.Settings, game.MatchOptions{TournamentID: t.ID})
+	if err != nil {
+		return tournamentBattle{}, err
+	}
+
+	thread, err := b.Client.Rest().CreateThread(t.ChannelID, discord.GuildPublicThreadCreate{
+		Name:                fmt.Sprintf("%s: Round %d, Match %d", t.Name, match.Round, match.ID),
+		AutoArchiveDuration: discord.AutoArchiveDuration1h,
+	})
+	if err != nil {
+		b.BattleManager.EndBattle(battle.ID)
+		return tournamentBattle{}, fmt.Errorf("failed to create battle thread: %w", err)
+	}
+
+	if err := b.BattleManager.SetBattleThread(battle.ID, thread.ID()); err != nil {
+		b.BattleManager.EndBattle(battle.ID)
+		return tournamentBattle{}, err
+	}
+
+	return tournamentBattle{MatchID: match.ID, Battle: battle, Thread: thread}, nil
+}
+
+// announceTournamentBattle sends the players of a saved match to its battle
+// thread and schedules the check that they show up.
+func announceTournamentBattle(b *bot.Bot, t *game.Tournament, started tournamentBattle) {
+	match := t.Match(started.MatchID)
+	if match == nil {
+		return
+	}
+
+	b.Client.Rest().CreateMessage(t.ThreadID, discord.MessageCreate{
+		Content: fmt.Sprintf("<@%s> vs <@%s>: head to %s and pick your teams within %d minutes or forfeit the match!",
+			match.Player1, match.Player2, started.Thread.Mention(), int(TournamentNoShowAfter.Minutes())),
+	})
+
+	IntroduceBattle(b, started.Battle)
+
+	if err := scheduleNoShowCheck(b, t, match); err != nil {
+		logger.NewLogger("services.tournament").Error("Failed to schedule no-show check",
+			zap.String("tournament_id", t.ID.String()),
+			zap.Int("match_id", match.ID),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+func scheduleNoShowCheck(b *bot.Bot, t *game.Tournament, match *game.TournamentMatch) error {
+	_, err := b.Scheduler.After(TournamentNoShowAfter).
+		With("tournament_id", t.ID.String()).
+		With("match_id", match.ID).
+		Emit(TournamentNoShowTask)
+	return err
+}
+
+// TournamentEmbed renders the tournament's bracket and standings.
+func TournamentEmbed(t *game.Tournament) discord.Embed {
+	embed := discord.NewEmbedBuilder().
+		SetTitlef("🏆 %s", t.Name).
+		SetColor(constants.ColorInfo).
+		AddField("Format", t.Format.String(), true).
+		AddField("Settings", t.Preset, true).
+		AddField("Status", t.State.String(), true).
+		SetFooterTextf("Tournament %s", t.ShortID())
+
+	switch t.State {
+	case game.TournamentStateSignUp:
+		players := make([]string, 0, len(t.Participants))
+		for _, p := range t.Participants {
+			players = append(players, fmt.Sprintf("<@%s>", p.UserID))
+		}
+		if len(players) == 0 {
+			players = append(players, "Nobody yet, use `/tournament join`!")
+		}
+
+		embed.SetDescriptionf("Sign-ups close <t:%d:R>.", t.SignupDeadline.Unix()).
+			AddField(fmt.Sprintf("Players (%d/%d)", len(t.Participants), game.MaxTournamentPlayers), TruncateField(strings.Join(players, ", ")), false)
+		return embed.Build()
+	case game.TournamentStateFinished:
+		embed.SetColor(constants.ColorSuccess)
+		if t.Winner != nil {
+			embed.SetDescriptionf("<@%s> wins the tournament!", *t.Winner)
+		}
+	case game.TournamentStateCancelled:
+		embed.SetColor(constants.ColorFail)
+	}
+
+	if t.Round > 0 {
+		lines := make([]string, 0)
+		for _, match := range t.RoundMatches(t.Round) {
+			lines = append(lines, tournamentMatchLine(match))
+		}
+		embed.AddField(fmt.Sprintf("Round %d", t.Round), TruncateField(strings.Join(lines, "\n")), false)
+	}
+
+	standings := t.Standings()
+	lines := make([]string, 0, len(standings))
+	for i, p := range standings[:min(len(standings), 16)] {
+		line := fmt.Sprintf("**%d.** <@%s> (seed %d) %dW / %dL", i+1, p.UserID, p.Seed, p.Wins, p.Losses)
+		if t.Format == game.TournamentSwiss {
+			line = fmt.Sprintf("**%d.** <@%s> %.1f pts (Buchholz %.1f)", i+1, p.UserID, p.Points(), t.Buchholz(p))
+		}
+		lines = append(lines, line)
+	}
+	embed.AddField("Standings", TruncateField(strings.Join(lines, "\n")), false)
+
+	return embed.Build()
+}
+
+func tournamentMatchLine(match *game.TournamentMatch) string {
+	if match.IsBye() {
+		return fmt.Sprintf("`#%d` <@%s> has a bye", match.ID, match.Player1)
+	}
+
+	line := fmt.Sprintf("`#%d` <@%s> vs <@%s>", match.ID, match.Player1, match.Player2)
+	switch {
+	case !match.Done:
+		return line + " • in progress"
+	case match.Winner == nil:
+		return line + " • draw"
+	case match.Walkover:
+		return line + fmt.Sprintf(" • <@%s> by walkover", *match.Winner)
+	default:
+		return line + fmt.Sprintf(" • <@%s> wins", *match.Winner)
+	}
+}
+
+func postBracket(b *bot.Bot, t *game.Tournament) {
+	if _, err := b.Client.Rest().CreateMessage(t.ThreadID, discord.MessageCreate{
+		Embeds: []discord.Embed{TournamentEmbed(t)},
+	}); err != nil {
+		logger.NewLogger("services.tournament").Error("Failed to post tournament bracket",
+			logger.DiscordChannelID(t.ThreadID),
+			logger.ErrorField(err),
+		)
+	}
+}
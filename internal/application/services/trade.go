@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/db"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// OpenTrade starts a trade between two players in a channel.
+func OpenTrade(ctx context.Context, b *bot.Bot, initiator, partner, channelID snowflake.ID) (*game.Trade, error) {
+	for _, userID := range []snowflake.ID{initiator, partner} {
+		if _, err := b.DB.EnsureUser(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.TradeManager.Open(initiator, partner, channelID)
+}
+
+// AddTradeCharacter offers one of the player's characters in their trade.
+func AddTradeCharacter(ctx context.Context, b *bot.Bot, userID snowflake.ID, characterID uuid.UUID) (*game.Trade, error) {
+	character, err := b.DB.GetCharacter(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if character == nil || character.OwnerID != userID.String() {
+		return nil, fmt.Errorf("you don't own that character")
+	}
+	if character.Locked {
+		return nil, fmt.Errorf("that character is up for sale on the market or in an auction")
+	}
+	if b.BattleManager.IsCharacterInBattle(character.ID) {
+		return nil, fmt.Errorf("that character is in a battle")
+	}
+
+	trade, err := b.TradeManager.AddCharacter(userID, *character)
+	if err != nil {
+		return nil, err
+	}
+
+	updateTradeMessage(b, trade)
+	return trade, nil
+}
+
+// RemoveTradeCharacter takes a character back out of the player's trade.
+func RemoveTradeCharacter(b *bot.Bot, userID snowflake.ID, characterID uuid.UUID) (*game.Trade, error) {
+	trade, err := b.TradeManager.RemoveCharacter(userID, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	updateTradeMessage(b, trade)
+	return trade, nil
+}
+
+// SetTradeCoins changes how many coins the player offers in their trade.
+func SetTradeCoins(ctx context.Context, b *bot.Bot, userID snowflake.ID, coins int) (*game.Trade, error) {
+	user, err := b.DB.EnsureUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Balance < coins {
+		return nil, fmt.Errorf("you only have %d coins", user.Balance)
+	}
+
+	trade, err := b.TradeManager.SetCoins(userID, coins)
+	if err != nil {
+		return nil, err
+	}
+
+	updateTradeMessage(b, trade)
+	return trade, nil
+}
+
+// ConfirmTrade accepts the current offer for the player. Once both players
+// confirmed, the exchange is carried out and completed is true.
+func ConfirmTrade(ctx context.Context, b *bot.Bot, userID snowflake.ID) (trade *game.Trade, completed bool, err error) {
+	trade, err = b.TradeManager.Confirm(userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !trade.Executing {
+		updateTradeMessage(b, trade)
+		return trade, false, nil
+	}
+
+	if err := executeTrade(ctx, b, trade); err != nil {
+		b.TradeManager.Abort(trade.ID)
+		if reopened, ok := b.TradeManager.GetPlayerTrade(userID); ok {
+			trade = reopened
+			updateTradeMessage(b, trade)
+		}
+		return trade, false, err
+	}
+
+	b.TradeManager.Finish(trade.ID)
+	closeTradeMessage(b, trade, "Trade completed! Everything has been exchanged.", constants.ColorSuccess)
+	return trade, true, nil
+}
+
+// CancelTrade closes the player's trade without exchanging anything.
+func CancelTrade(b *bot.Bot, userID snowflake.ID) (*game.Trade, error) {
+	trade, err := b.TradeManager.Cancel(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	closeTradeMessage(b, trade, fmt.Sprintf("Trade cancelled by <@%s>.", userID), constants.ColorFail)
+	return trade, nil
+}
+
+// executeTrade swaps everything offered in one transaction, so either the
+// whole trade goes through or nothing changes.
+func executeTrade(ctx context.Context, b *bot.Bot, trade *game.Trade) error {
+	for _, side := range trade.Sides {
+		for _, offered := range side.Characters {
+			if b.BattleManager.IsCharacterInBattle(offered.ID) {
+				return fmt.Errorf("**%s** is in a battle", offered.CharacterName())
+			}
+		}
+	}
+
+	err := b.DB.Tx(ctx, func(tx db.Store) error {
+		for _, side := range trade.Sides {
+			receiver := trade.Partner(side.UserID).UserID
+
+			for _, offered := range side.Characters {
+				character, err := tx.LockCharacter(ctx, offered.ID)
+				if err != nil {
+					return err
+				}
+				if character == nil || character.OwnerID != side.UserID.String() || character.Locked {
+					return fmt.Errorf("**%s No. %d** is no longer available", offered.CharacterName(), offered.IDX)
+				}
+				// Checked again now that the row is held, as a battle may have
+				// started since the first check
+				if b.BattleManager.IsCharacterInBattle(character.ID) {
+					return fmt.Errorf("**%s** is in a battle", offered.CharacterName())
+				}
+
+				if err := tx.TransferCharacter(ctx, character.ID, receiver); err != nil {
+					return err
+				}
+			}
+
+			if side.Coins == 0 {
+				continue
+			}
+			if _, err := tx.AdjustBalance(ctx, side.UserID, -side.Coins, game.ReasonTrade, "trade "+trade.ID.String()); err != nil {
+				if errors.Is(err, db.ErrInsufficientBalance) {
+					return fmt.Errorf("<@%s> no longer has %d coins", side.UserID, side.Coins)
+				}
+				return err
+			}
+			if _, err := tx.AdjustBalance(ctx, receiver, side.Coins, game.ReasonTrade, "trade "+trade.ID.String()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.NewLogger("services.trade").Info("Trade completed",
+		zap.String("trade_id", trade.ID.String()),
+		logger.DiscordUserID(trade.Sides[0].UserID),
+		zap.String("partner_id", trade.Sides[1].UserID.String()),
+	)
+
+	refreshLeaderboards(b, trade.Sides[0].UserID, trade.Sides[1].UserID)
+	return nil
+}
+
+// TradeMessage renders an open trade with its confirm and cancel buttons.
+func TradeMessage(trade *game.Trade) discord.MessageUpdate {
+	return discord.NewMessageUpdateBuilder().
+		SetEmbeds(TradeEmbed(trade, constants.ColorInfo)).
+		SetContainerComponents(TradeComponents(trade)...).
+		Build()
+}
+
+// TradeComponents are the buttons of an open trade.
+func TradeComponents(trade *game.Trade) []discord.ContainerComponent {
+	return []discord.ContainerComponent{
+		discord.NewActionRow(
+			discord.NewSuccessButton("Confirm", fmt.Sprintf("/trade_confirm/%s", trade.ID)),
+			discord.NewDangerButton("Cancel", fmt.Sprintf("/trade_cancel/%s", trade.ID)),
+		),
+	}
+}
+
+// TradeEmbed lists what both players offer and who has confirmed.
+func TradeEmbed(trade *game.Trade, color int) discord.Embed {
+	embed := discord.NewEmbedBuilder().
+		SetTitle("Trade").
+		SetDescription(fmt.Sprintf("<@%s> ⇄ <@%s>\nAdd characters with `/trade add` and coins with `/trade coins`. Any change resets both confirmations.",
+			trade.Sides[0].UserID, trade.Sides[1].UserID)).
+		SetColor(color).
+		SetFooterTextf("Closes after %s without changes", game.TradeTimeout)
+
+	for _, side := range trade.Sides {
+		lines := make([]string, 0, len(side.Characters)+1)
+		if side.Coins > 0 {
+			lines = append(lines, fmt.Sprintf("💰 %d coins", side.Coins))
+		}
+		for _, character := range side.Characters {
+			lines = append(lines, fmt.Sprintf("`%d` %s • Lvl. %d • %s", character.IDX, character.Format("in"), character.Level, character.IvPercentage()))
+		}
+		if len(lines) == 0 {
+			lines = append(lines, "Nothing yet")
+		}
+
+		status := "⏳"
+		if side.Confirmed {
+			status = "✅"
+		}
+
+		embed.AddField(fmt.Sprintf("%s Offer", status), TruncateField(fmt.Sprintf("<@%s>\n%s", side.UserID, strings.Join(lines, "\n"))), true)
+	}
+
+	return embed.Build()
+}
+
+// closeTradeMessage shows the final offer of a closed trade without its buttons.
+func closeTradeMessage(b *bot.Bot, trade *game.Trade, note string, color int) {
+	if trade.MessageID == 0 {
+		return
+	}
+
+	_, err := b.Client.Rest().UpdateMessage(trade.ChannelID, trade.MessageID, discord.NewMessageUpdateBuilder().
+		SetContent(note).
+		SetEmbeds(TradeEmbed(trade, color)).
+		ClearContainerComponents().
+		Build())
+	if err != nil {
+		logger.NewLogger("services.trade").Error("Failed to close trade message",
+			zap.String("trade_id", trade.ID.String()),
+			logger.DiscordChannelID(trade.ChannelID),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// updateTradeMessage refreshes the shared trade message after an offer changed.
+func updateTradeMessage(b *bot.Bot, trade *game.Trade) {
+	if trade.MessageID == 0 {
+		return
+	}
+
+	if _, err := b.Client.Rest().UpdateMessage(trade.ChannelID, trade.MessageID, TradeMessage(trade)); err != nil {
+		logger.NewLogger("services.trade").Error("Failed to update trade message",
+			zap.String("trade_id", trade.ID.String()),
+			logger.DiscordChannelID(trade.ChannelID),
+			logger.ErrorField(err),
+		)
+	}
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/theoreotm/friemon/constants"
+	"github.com/theoreotm/friemon/internal/application/bot"
+	"github.com/theoreotm/friemon/internal/core/game"
+	"github.com/theoreotm/friemon/internal/infrastructure/db"
+	"github.com/theoreotm/friemon/internal/pkg/logger"
+	"github.com/theoreotm/friemon/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	XPFlushTask     = "flush_xp"
+	XPFlushInterval = time.Minute
+)
+
+// SetupXP registers the task that saves buffered XP and schedules it to run
+// every interval.
+func SetupXP(b *bot.Bot) error {
+	b.Scheduler.On(XPFlushTask, func(ctx context.Context, _ types.TaskData) error {
+		return FlushXP(ctx, b)
+	})
+
+	return b.Scheduler.Every(XPFlushInterval, XPFlushTask)
+}
+
+// AwardXP buffers XP for a character, multiplied by its held item and the
+// running events. It is saved by the next flush.
+func AwardXP(ctx context.Context, b *bot.Bot, character *game.Character, xp int, guildID, channelID snowflake.ID) error {
+	multiplier, err := ActiveXPMultiplier(ctx, b)
+	if err != nil {
+		return err
+	}
+
+	userID, err := snowflake.Parse(character.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	return b.Cache.AddPendingXP(game.XPGain{
+		CharacterID: character.ID,
+		UserID:      userID,
+		GuildID:     guildID,
+		ChannelID:   channelID,
+		XP:          game.ApplyXPMultiplier(xp, multiplier*character.XPMultiplier()),
+	})
+}
+
+// ActiveXPMultiplier combines the XP multipliers of every event running now,
+// from the cached events when possible.
+func ActiveXPMultiplier(ctx context.Context, b *bot.Bot) (float64, error) {
+	events, err := SpawnEvents(ctx, b)
+	if err != nil {
+		return 1, err
+	}
+
+	now := time.Now()
+	multiplier := 1.0
+	for _, event := range events {
+		if event.Active(now) {
+			multiplier *= event.XPMultiplier
+		}
+	}
+	return multiplier, nil
+}
+
+// FlushXP saves all buffered XP. XP that fails to save goes back to the
+// buffer for the next flush.
+func FlushXP(ctx context.Context, b *bot.Bot) error {
+	log := logger.NewLogger("services.xp")
+
+	gains, err := b.Cache.TakePendingXP()
+	if err != nil {
+		return err
+	}
+
+	for _, gain := range gains {
+		if err := applyXP(ctx, b, gain); err != nil {
+			log.Error("Failed to save XP",
+				logger.CharacterID(gain.CharacterID),
+				zap.Int("xp", gain.XP),
+				logger.ErrorField(err),
+			)
+
+			if err := b.Cache.AddPendingXP(gain); err != nil {
+				log.Error("Lost XP that failed to save",
+					logger.CharacterID(gain.CharacterID),
+					zap.Int("xp", gain.XP),
+					logger.ErrorField(err),
+				)
+			}
+		}
+	}
+
+	if len(gains) > 0 {
+		log.Debug("XP flushed", zap.Int("characters", len(gains)))
+	}
+	return nil
+}
+
+// awardBattleXP gives every character on the winning team XP for the win.
+func awardBattleXP(ctx context.Context, b *bot.Bot, battle *game.Battle) error {
+	if battle.Winner == nil {
+		return nil
+	}
+
+	for _, character := range battle.GetPlayer(*battle.Winner).Team {
+		if err := AwardXP(ctx, b, character, game.BattleWinXP, 0, battle.ChannelID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyXP saves one character's buffered XP, running its level-up hooks and
+// paying the owner its rewards in the same transaction.
+func applyXP(ctx context.Context, b *bot.Bot, gain game.XPGain) error {
+	var character *game.Character
+	var levelUp game.LevelUp
+	var ownerID snowflake.ID
+
+	err := b.DB.Tx(ctx, func(tx db.Store) error {
+		var err error
+		character, err = tx.LockCharacter(ctx, gain.CharacterID)
+		if err != nil || character == nil {
+			// Released since, the XP has nowhere to go
+			return err
+		}
+		if character.OwnerID != gain.UserID.String() {
+			// Traded since, the XP was earned by the previous owner
+			character = nil
+			return nil
+		}
+		ownerID, err = snowflake.Parse(character.OwnerID)
+		if err != nil {
+			return err
+		}
+
+		levelUp = character.GainXP(gain.XP)
+		if err := tx.SaveCharacterProgress(ctx, character); err != nil {
+			return err
+		}
+
+		if levelUp.Coins > 0 {
+			reference := fmt.Sprintf("%s level %d", character.CharacterName(), levelUp.ToLevel)
+			if _, err := tx.AdjustBalance(ctx, ownerID, levelUp.Coins, game.ReasonLevelUp, reference); err != nil {
+				return err
+			}
+		}
+		for _, item := range levelUp.Items {
+			if _, err := tx.AddItem(ctx, ownerID, item, 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil || character == nil || levelUp.ToLevel == 0 {
+		return err
+	}
+
+	logger.NewLogger("services.xp").Info("Character leveled up",
+		logger.DiscordUserID(ownerID),
+		logger.CharacterID(character.ID),
+		logger.CharacterName(character.CharacterName()),
+		zap.Int("old_level", levelUp.FromLevel),
+		zap.Int("new_level", levelUp.ToLevel),
+		zap.Int("moves_learned", len(levelUp.Moves)),
+	)
+
+	refreshLeaderboards(b, ownerID)
+	announceLevelUp(ctx, b, gain, ownerID, character, levelUp)
+	return nil
+}
+
+// announceLevelUp tells the owner about a level-up the way the guild it was
+// earned in wants, in the channel it was earned in outside guilds. A level
+// that unlocked an evolution comes with the prompt to take it.
+func announceLevelUp(ctx context.Context, b *bot.Bot, gain game.XPGain, ownerID snowflake.ID, character *game.Character, levelUp game.LevelUp) {
+	log := logger.NewLogger("services.xp")
+
+	settings := game.DefaultGuildSettings(gain.GuildID)
+	if gain.GuildID != 0 {
+		var err error
+		settings, err = GuildSettings(ctx, b, gain.GuildID)
+		if err != nil {
+			log.Warn("Failed to load guild settings, using defaults",
+				logger.DiscordGuildID(gain.GuildID),
+				logger.ErrorField(err),
+			)
+		}
+	}
+
+	message := discord.MessageCreate{
+		Embeds:          []discord.Embed{levelUpEmbed(ownerID, character, levelUp)},
+		AllowedMentions: &discord.AllowedMentions{},
+	}
+	if levelUp.Evolution != nil {
+		message.Components = []discord.ContainerComponent{EvolutionButtons(character.ID, *levelUp.Evolution)}
+	}
+
+	switch settings.LevelUpNotify {
+	case game.LevelUpOff:
+	case game.LevelUpInDM:
+		notifyUser(b, ownerID, message)
+	default:
+		channelID := settings.LevelUpChannel(gain.ChannelID)
+		if _, err := b.Client.Rest().CreateMessage(channelID, message); err != nil {
+			log.Warn("Failed to announce level-up",
+				logger.DiscordChannelID(channelID),
+				logger.CharacterID(character.ID),
+				logger.ErrorField(err),
+			)
+		}
+	}
+}
+
+func levelUpEmbed(ownerID snowflake.ID, character *game.Character, levelUp game.LevelUp) discord.Embed {
+	embed := discord.NewEmbedBuilder().
+		SetTitle("🎉 Level Up!").
+		SetDescription(fmt.Sprintf("<@%s>'s **%s** reached level **%d**!", ownerID, character.Format("n"), levelUp.ToLevel)).
+		SetColor(constants.ColorSuccess)
+
+	if len(levelUp.Moves) > 0 {
+		lines := make([]string, len(levelUp.Moves))
+		for i, move := range levelUp.Moves {
+			lines[i] = fmt.Sprintf("It %s.", move)
+		}
+		embed.AddField("Moves", strings.Join(lines, "\n"), false)
+	}
+
+	rewards := []string{fmt.Sprintf("%d coins", levelUp.Coins)}
+	for _, id := range levelUp.Items {
+		item := game.ItemsRegistry[id]
+		rewards = append(rewards, fmt.Sprintf("%s %s", item.Emoji, item.Name))
+	}
+	embed.AddField("Rewards", strings.Join(rewards, "\n"), false)
+
+	if levelUp.Evolution != nil {
+		embed.AddField("Evolution", fmt.Sprintf("It's ready to evolve into **%s**!", levelUp.Evolution.Form().Name), false)
+	}
+
+	return embed.Build()
+}
@@ -0,0 +1,99 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	MinAuctionDuration     = 10 * time.Minute
+	MaxAuctionDuration     = 48 * time.Hour
+	AuctionSnipeWindow     = 2 * time.Minute // Bids this close to the end push it back to this far away
+	MinBidIncrementPercent = 5               // How much a bid must beat the current one by
+)
+
+type AuctionState int
+
+const (
+	AuctionActive AuctionState = iota
+	AuctionSold
+	AuctionUnsold
+	AuctionCancelled
+)
+
+func (s AuctionState) String() string {
+	switch s {
+	case AuctionActive:
+		return "Active"
+	case AuctionSold:
+		return "Sold"
+	case AuctionUnsold:
+		return "Unsold"
+	case AuctionCancelled:
+		return "Cancelled"
+	default:
+		return "Unknown"
+	}
+}
+
+// Auction sells a character to the highest bidder. The character is locked
+// while the auction runs and the top bid is held from the bidder's balance
+// until they are outbid or the auction settles.
+type Auction struct {
+	ID          int
+	SellerID    snowflake.ID
+	Character   Character
+	ChannelID   snowflake.ID
+	MessageID   snowflake.ID
+	StartingBid int
+	CurrentBid  int
+	BidderID    snowflake.ID // Zero until the first bid
+	Bids        int
+	State       AuctionState
+	EndsAt      time.Time
+	CreatedAt   time.Time
+}
+
+// HasBids reports whether anyone has bid yet.
+func (a *Auction) HasBids() bool {
+	return a.BidderID != 0
+}
+
+// MinimumBid is the lowest amount the next bid can be.
+func (a *Auction) MinimumBid() int {
+	if !a.HasBids() {
+		return a.StartingBid
+	}
+	return a.CurrentBid + max(1, a.CurrentBid*MinBidIncrementPercent/100)
+}
+
+// PlaceBid checks a bid and records it on the auction, extending the end when
+// the bid comes in the last moments. The previous top bidder and their bid are
+// left for the caller to refund.
+func (a *Auction) PlaceBid(bidderID snowflake.ID, amount int, now time.Time) (extended bool, err error) {
+	if a.State != AuctionActive || !now.Before(a.EndsAt) {
+		return false, fmt.Errorf("this auction has ended")
+	}
+	if bidderID == a.SellerID {
+		return false, fmt.Errorf("you can't bid on your own auction")
+	}
+	if bidderID == a.BidderID {
+		return false, fmt.Errorf("you already have the highest bid")
+	}
+	if amount < a.MinimumBid() {
+		return false, fmt.Errorf("the minimum bid is %d coins", a.MinimumBid())
+	}
+
+	a.BidderID = bidderID
+	a.CurrentBid = amount
+	a.Bids++
+
+	if a.EndsAt.Sub(now) < AuctionSnipeWindow {
+		a.EndsAt = now.Add(AuctionSnipeWindow)
+		extended = true
+	}
+
+	return extended, nil
+}
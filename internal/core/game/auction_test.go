@@ -0,0 +1,132 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func TestAuctionPlaceBid(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		auction    Auction
+		bidder     snowflake.ID
+		amount     int
+		wantErr    bool
+		wantEndsAt time.Time
+	}{
+		{
+			name:       "first bid at the starting bid",
+			auction:    Auction{SellerID: 1, StartingBid: 100, EndsAt: now.Add(time.Hour)},
+			bidder:     2,
+			amount:     100,
+			wantEndsAt: now.Add(time.Hour),
+		},
+		{
+			name:    "first bid below the starting bid",
+			auction: Auction{SellerID: 1, StartingBid: 100, EndsAt: now.Add(time.Hour)},
+			bidder:  2,
+			amount:  99,
+			wantErr: true,
+		},
+		{
+			name:       "outbid by the minimum increment",
+			auction:    Auction{SellerID: 1, StartingBid: 100, CurrentBid: 200, BidderID: 2, Bids: 1, EndsAt: now.Add(time.Hour)},
+			bidder:     3,
+			amount:     210,
+			wantEndsAt: now.Add(time.Hour),
+		},
+		{
+			name:    "outbid by less than the minimum increment",
+			auction: Auction{SellerID: 1, StartingBid: 100, CurrentBid: 200, BidderID: 2, Bids: 1, EndsAt: now.Add(time.Hour)},
+			bidder:  3,
+			amount:  209,
+			wantErr: true,
+		},
+		{
+			name:       "small bids still go up by a coin",
+			auction:    Auction{SellerID: 1, StartingBid: 1, CurrentBid: 10, BidderID: 2, Bids: 1, EndsAt: now.Add(time.Hour)},
+			bidder:     3,
+			amount:     11,
+			wantEndsAt: now.Add(time.Hour),
+		},
+		{
+			name:    "seller bids",
+			auction: Auction{SellerID: 1, StartingBid: 100, EndsAt: now.Add(time.Hour)},
+			bidder:  1,
+			amount:  500,
+			wantErr: true,
+		},
+		{
+			name:    "top bidder bids again",
+			auction: Auction{SellerID: 1, StartingBid: 100, CurrentBid: 200, BidderID: 2, Bids: 1, EndsAt: now.Add(time.Hour)},
+			bidder:  2,
+			amount:  500,
+			wantErr: true,
+		},
+		{
+			name:    "auction ended",
+			auction: Auction{SellerID: 1, StartingBid: 100, EndsAt: now},
+			bidder:  2,
+			amount:  500,
+			wantErr: true,
+		},
+		{
+			name:    "auction cancelled",
+			auction: Auction{SellerID: 1, StartingBid: 100, State: AuctionCancelled, EndsAt: now.Add(time.Hour)},
+			bidder:  2,
+			amount:  500,
+			wantErr: true,
+		},
+		{
+			name:       "bid in the snipe window extends the auction",
+			auction:    Auction{SellerID: 1, StartingBid: 100, EndsAt: now.Add(30 * time.Second)},
+			bidder:     2,
+			amount:     100,
+			wantEndsAt: now.Add(AuctionSnipeWindow),
+		},
+		{
+			name:       "bid just outside the snipe window",
+			auction:    Auction{SellerID: 1, StartingBid: 100, EndsAt: now.Add(AuctionSnipeWindow)},
+			bidder:     2,
+			amount:     100,
+			wantEndsAt: now.Add(AuctionSnipeWindow),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auction := tt.auction
+			extended, err := auction.PlaceBid(tt.bidder, tt.amount, now)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("PlaceBid succeeded, want an error")
+				}
+				if auction.BidderID != tt.auction.BidderID || auction.CurrentBid != tt.auction.CurrentBid || !auction.EndsAt.Equal(tt.auction.EndsAt) {
+					t.Errorf("a rejected bid changed the auction: %d by %d, ending %s", auction.CurrentBid, auction.BidderID, auction.EndsAt)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PlaceBid: %v", err)
+			}
+
+			if auction.BidderID != tt.bidder || auction.CurrentBid != tt.amount {
+				t.Errorf("top bid = %d by %d, want %d by %d", auction.CurrentBid, auction.BidderID, tt.amount, tt.bidder)
+			}
+			if auction.Bids != tt.auction.Bids+1 {
+				t.Errorf("bids = %d, want %d", auction.Bids, tt.auction.Bids+1)
+			}
+			if !auction.EndsAt.Equal(tt.wantEndsAt) {
+				t.Errorf("ends at %s, want %s", auction.EndsAt, tt.wantEndsAt)
+			}
+			if want := !tt.wantEndsAt.Equal(tt.auction.EndsAt); extended != want {
+				t.Errorf("extended = %t, want %t", extended, want)
+			}
+		})
+	}
+}
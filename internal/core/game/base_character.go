@@ -21,6 +21,8 @@ type BaseCharacter struct {
 	Type0 Type `json:"type0"`
 	Type1 Type `json:"type1"`
 
+	Rarity Rarity `json:"rarity"`
+
 	Emoji string `json:"emoji"` // The id of the emoji in the application
 }
 
@@ -38,7 +40,7 @@ func (bc BaseCharacter) GetEmoji() string {
 }
 
 // NewBaseCharacter is a constructor for creating a new BaseCharacter.
-func NewBaseCharacter(id int, name string, types []Type, hp, atk, def, spAtk, spDef, spd int) BaseCharacter {
+func NewBaseCharacter(id int, name string, types []Type, rarity Rarity, hp, atk, def, spAtk, spDef, spd int) BaseCharacter {
 	for _, ch := range Characters {
 		if ch.ID == id {
 			slog.Error("Character already exists, character wasnt added", slog.Int("existing_id", ch.ID), slog.Int("new_id", id))
@@ -64,6 +66,8 @@ func NewBaseCharacter(id int, name string, types []Type, hp, atk, def, spAtk, sp
 		Type0: types[0],
 		Type1: types[1],
 
+		Rarity: rarity,
+
 		Emoji: "",
 	}
 
@@ -103,6 +103,24 @@ type Battle struct {
 	BattleLog   []string               `json:"battle_log"`
 	Weather     string                 `json:"weather,omitempty"`
 	Field       map[string]interface{} `json:"field,omitempty"`
+	Ranked      bool                   `json:"ranked"`
+
+	// Tournament the battle is a match of, uuid.Nil for regular battles
+	TournamentID uuid.UUID `json:"tournament_id"`
+
+	// Spectator votes for the fan favourite, keyed by spectator ID
+	FanVotes map[snowflake.ID]snowflake.ID `json:"fan_votes,omitempty"`
+	// Latest turn summary, the only message whose reactions count as votes
+	VoteMessageID snowflake.ID `json:"vote_message_id,omitempty"`
+
+	// Replay data, every random roll is drawn from a generator seeded with Seed
+	Seed  int64            `json:"seed"`
+	Turns [][]PlayerAction `json:"turns"`
+
+	rng          *rand.Rand
+	startTeams   [2][]CharacterSnapshot // Teams as they were when the battle started
+	lastTurn     int                    // Turn number of the most recently processed turn
+	turnLogStart int                    // Index in BattleLog where the most recent turn starts
 }
 
 func NewBattle(channelID snowflake.ID, player1ID, player2ID snowflake.ID) *Battle {
@@ -128,9 +146,20 @@ func NewBattle(channelID snowflake.ID, player1ID, player2ID snowflake.ID) *Battl
 		StartedAt:   time.Now(),
 		BattleLog:   make([]string, 0),
 		Field:       make(map[string]interface{}),
+		FanVotes:    make(map[snowflake.ID]snowflake.ID),
+		Seed:        rand.Int63(),
+		Turns:       make([][]PlayerAction, 0),
 	}
 }
 
+// random returns the battle's random number generator, seeded from Seed.
+func (b *Battle) random() *rand.Rand {
+	if b.rng == nil {
+		b.rng = rand.New(rand.NewSource(b.Seed))
+	}
+	return b.rng
+}
+
 func (b *Battle) GetPlayer(playerID snowflake.ID) *BattlePlayer {
 	if b.Player1.ID == playerID {
 		return b.Player1
@@ -170,6 +199,11 @@ func (b *Battle) Start() error {
 		return fmt.Errorf("battle cannot start: invalid state or incomplete teams")
 	}
 
+	b.startTeams = [2][]CharacterSnapshot{
+		snapshotTeam(b.Player1.Team),
+		snapshotTeam(b.Player2.Team),
+	}
+
 	// Initialize battle stats for all characters
 	for _, char := range b.Player1.Team {
 		char.InitializeBattleStats()
@@ -213,7 +247,7 @@ func (b *Battle) CalculateTurnOrder() {
 		b.TurnOrder = []snowflake.ID{b.Player2.ID, b.Player1.ID}
 	} else {
 		// Speed tie - random
-		if rand.Intn(2) == 0 {
+		if b.random().Intn(2) == 0 {
 			b.TurnOrder = []snowflake.ID{b.Player1.ID, b.Player2.ID}
 		} else {
 			b.TurnOrder = []snowflake.ID{b.Player2.ID, b.Player1.ID}
@@ -227,9 +261,12 @@ func (b *Battle) ProcessTurn() error {
 	}
 
 	b.AddToLog(fmt.Sprintf("--- Turn %d ---", b.CurrentTurn))
+	b.lastTurn = b.CurrentTurn
+	b.turnLogStart = len(b.BattleLog)
 
 	// Process priority moves first, then regular moves
 	actions := b.collectAllActions()
+	b.Turns = append(b.Turns, actions)
 	sortedActions := b.sortActionsByPriority(actions)
 
 	for _, action := range sortedActions {
@@ -269,6 +306,8 @@ func (b *Battle) ProcessTurn() error {
 		return nil
 	}
 
+	b.replaceFaintedCharacters()
+
 	b.CurrentTurn++
 
 	// Clear actions for next turn
@@ -353,7 +392,7 @@ func (b *Battle) executeAttack(player *BattlePlayer, action PlayerAction) error
 	}
 
 	// Check if character can move
-	if !attacker.BattleStats.CanMove() {
+	if !attacker.BattleStats.CanMove() || attacker.BattleStats.IsFullyParalyzed(b.random()) {
 		if attacker.BattleStats.MustRecharge {
 			b.AddToLog(fmt.Sprintf("%s must recharge!", attacker.CharacterName()))
 		} else if attacker.BattleStats.HasStatusEffect(constants.StatusSleep) {
@@ -368,7 +407,7 @@ func (b *Battle) executeAttack(player *BattlePlayer, action PlayerAction) error
 
 	// Check for confusion
 	if attacker.BattleStats.HasStatusEffect(constants.StatusConfuse) {
-		if rand.Intn(100) < 33 { // 33% chance to hurt self in confusion
+		if b.random().Intn(100) < 33 { // 33% chance to hurt self in confusion
 			damage := b.calculateConfusionDamage(attacker)
 			attacker.BattleStats.TakeDamage(damage)
 			b.AddToLog(fmt.Sprintf("%s hurt itself in confusion for %d damage!", attacker.CharacterName(), damage))
@@ -460,7 +499,7 @@ func (b *Battle) executeSingleTargetMove(attacker, target *Character, move Move)
 	}
 
 	// Calculate damage
-	damageResult := CalculateDamage(attacker, target, move, b.Settings)
+	damageResult := CalculateDamage(attacker, target, move, b.Settings, b.random())
 
 	if !damageResult.Hit {
 		b.AddToLog(fmt.Sprintf("%s's attack missed!", attacker.CharacterName()))
@@ -572,7 +611,7 @@ func (b *Battle) applyMoveEffects(attacker, target *Character, move Move, damage
 			chance = move.SecondaryEffect.FlinchChance
 		}
 
-		if rand.Intn(100) < chance {
+		if b.random().Intn(100) < chance {
 			b.applySingleEffect(attacker, target, move.SecondaryEffect, chance, damage)
 		}
 	}
@@ -581,13 +620,13 @@ func (b *Battle) applyMoveEffects(attacker, target *Character, move Move, damage
 func (b *Battle) applySingleEffect(attacker, target *Character, effect *EffectType, chance int, damage int) {
 	// Status conditions
 	if effect.StatusCondition != constants.StatusNone && effect.StatusChance > 0 {
-		if rand.Intn(100) < effect.StatusChance {
+		if b.random().Intn(100) < effect.StatusChance {
 			duration := 0
 			switch effect.StatusCondition {
 			case constants.StatusSleep:
-				duration = rand.Intn(3) + 1 // 1-3 turns
+				duration = b.random().Intn(3) + 1 // 1-3 turns
 			case constants.StatusConfuse:
-				duration = rand.Intn(4) + 1 // 1-4 turns
+				duration = b.random().Intn(4) + 1 // 1-4 turns
 			}
 
 			if target.BattleStats.AddStatusEffect(effect.StatusCondition, duration) {
@@ -600,8 +639,9 @@ func (b *Battle) applySingleEffect(attacker, target *Character, effect *EffectTy
 
 	// Stat modifications (target)
 	if len(effect.StatModifiers) > 0 && effect.StatChance > 0 {
-		if rand.Intn(100) < effect.StatChance {
-			for stat, stages := range effect.StatModifiers {
+		if b.random().Intn(100) < effect.StatChance {
+			for _, stat := range effect.StatModifiers.Stats() {
+				stages := effect.StatModifiers[stat]
 				oldStage := b.getStatStage(target, stat)
 				target.BattleStats.ModifyStat(stat, stages)
 				newStage := b.getStatStage(target, stat)
@@ -625,8 +665,9 @@ func (b *Battle) applySingleEffect(attacker, target *Character, effect *EffectTy
 
 	// Self stat modifications
 	if len(effect.SelfStatModifiers) > 0 && effect.SelfStatChance > 0 {
-		if rand.Intn(100) < effect.SelfStatChance {
-			for stat, stages := range effect.SelfStatModifiers {
+		if b.random().Intn(100) < effect.SelfStatChance {
+			for _, stat := range effect.SelfStatModifiers.Stats() {
+				stages := effect.SelfStatModifiers[stat]
 				oldStage := b.getStatStage(attacker, stat)
 				attacker.BattleStats.ModifyStat(stat, stages)
 				newStage := b.getStatStage(attacker, stat)
@@ -644,7 +685,7 @@ func (b *Battle) applySingleEffect(attacker, target *Character, effect *EffectTy
 
 	// Flinch
 	if effect.Flinch && effect.FlinchChance > 0 {
-		if rand.Intn(100) < effect.FlinchChance {
+		if b.random().Intn(100) < effect.FlinchChance {
 			target.BattleStats.FlinchThisTurn = true
 			b.AddToLog(fmt.Sprintf("%s flinched!", target.CharacterName()))
 		}
@@ -770,6 +811,25 @@ func (b *Battle) processEndOfTurnEffects() {
 	}
 }
 
+// replaceFaintedCharacters sends out the next healthy team member for any
+// player whose active character fainted this turn.
+func (b *Battle) replaceFaintedCharacters() {
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		active := player.GetActiveCharacter()
+		if active != nil && !active.BattleStats.IsFainted() {
+			continue
+		}
+
+		for i, char := range player.Team {
+			if !char.BattleStats.IsFainted() {
+				player.ActiveCharacter = i
+				b.AddToLog(fmt.Sprintf("%s sent out %s!", player.ID, char.CharacterName()))
+				break
+			}
+		}
+	}
+}
+
 func (b *Battle) processStatusEffects(char *Character, playerID snowflake.ID) {
 	stats := char.BattleStats
 
@@ -805,7 +865,7 @@ func (b *Battle) processStatusEffects(char *Character, playerID snowflake.ID) {
 
 	// Freeze chance to thaw
 	if stats.HasStatusEffect(constants.StatusFreeze) {
-		if rand.Intn(100) < 20 { // 20% chance to thaw each turn
+		if b.random().Intn(100) < 20 { // 20% chance to thaw each turn
 			stats.RemoveStatusEffect(constants.StatusFreeze)
 			b.AddToLog(fmt.Sprintf("%s thawed out!", char.CharacterName()))
 		}
@@ -970,7 +1030,7 @@ func (b *Battle) BothPlayersHaveActions() bool {
 }
 
 func (b *Battle) GetBattleSummary() string {
-	summary := fmt.Sprintf("Battle ID: %s\n", b.ID.String()[:8])
+	summary := fmt.Sprintf("Battle ID: %s\n", ShortBattleID(b.ID))
 	summary += fmt.Sprintf("Turn: %d/%d\n", b.CurrentTurn, b.Settings.MaxTurns)
 	summary += fmt.Sprintf("State: %s\n", b.State.String())
 
@@ -996,3 +1056,131 @@ func (b *Battle) GetBattleSummary() string {
 
 	return summary
 }
+
+// LastTurnLog returns the log entries produced by the most recently processed turn.
+func (b *Battle) LastTurnLog() []string {
+	if b.turnLogStart <= 0 || b.turnLogStart > len(b.BattleLog) {
+		return nil
+	}
+	return b.BattleLog[b.turnLogStart:]
+}
+
+// VoteFanFavourite records a spectator's vote for one of the players.
+// Voting again replaces the previous vote.
+func (b *Battle) VoteFanFavourite(voterID, playerID snowflake.ID) error {
+	if b.IsPlayerInBattle(voterID) {
+		return fmt.Errorf("players cannot vote in their own battle")
+	}
+	if !b.IsPlayerInBattle(playerID) {
+		return fmt.Errorf("player is not in this battle")
+	}
+
+	if b.FanVotes == nil {
+		b.FanVotes = make(map[snowflake.ID]snowflake.ID)
+	}
+	b.FanVotes[voterID] = playerID
+	return nil
+}
+
+// RemoveFanVote removes a spectator's vote if it was cast for the given player.
+func (b *Battle) RemoveFanVote(voterID, playerID snowflake.ID) {
+	if b.FanVotes[voterID] == playerID {
+		delete(b.FanVotes, voterID)
+	}
+}
+
+// FanVoteCounts returns the number of fan favourite votes for each player.
+func (b *Battle) FanVoteCounts() (player1Votes, player2Votes int) {
+	for _, playerID := range b.FanVotes {
+		switch playerID {
+		case b.Player1.ID:
+			player1Votes++
+		case b.Player2.ID:
+			player2Votes++
+		}
+	}
+	return
+}
+
+// FanFavourite returns the player with the most spectator votes.
+// ok is false when nobody voted or the vote is tied.
+func (b *Battle) FanFavourite() (playerID snowflake.ID, votes int, ok bool) {
+	p1, p2 := b.FanVoteCounts()
+	switch {
+	case p1 > p2:
+		return b.Player1.ID, p1, true
+	case p2 > p1:
+		return b.Player2.ID, p2, true
+	default:
+		return 0, p1, false
+	}
+}
+
+// SideSummary is a snapshot of one player's side of the field.
+type SideSummary struct {
+	PlayerID    snowflake.ID             `json:"player_id"`
+	Active      string                   `json:"active"`
+	Level       int                      `json:"level"`
+	HP          int                      `json:"hp"`
+	MaxHP       int                      `json:"max_hp"`
+	Statuses    []constants.StatusEffect `json:"statuses,omitempty"`
+	AliveCount  int                      `json:"alive_count"`
+	TeamSize    int                      `json:"team_size"`
+	FanVotes    int                      `json:"fan_votes"`
+	ActiveIndex int                      `json:"active_index"`
+}
+
+// TurnSummary is a snapshot of the battle after a turn, used for the
+// battle thread feed.
+type TurnSummary struct {
+	BattleID uuid.UUID     `json:"battle_id"`
+	Turn     int           `json:"turn"`
+	MaxTurns int           `json:"max_turns"`
+	State    BattleState   `json:"state"`
+	Player1  SideSummary   `json:"player1"`
+	Player2  SideSummary   `json:"player2"`
+	Events   []string      `json:"events"`
+	Winner   *snowflake.ID `json:"winner,omitempty"`
+}
+
+// TurnSummary builds a snapshot of the current state of the battle along with
+// the events of the most recently processed turn.
+func (b *Battle) TurnSummary() TurnSummary {
+	p1Votes, p2Votes := b.FanVoteCounts()
+
+	summary := TurnSummary{
+		BattleID: b.ID,
+		Turn:     b.lastTurn,
+		MaxTurns: b.Settings.MaxTurns,
+		State:    b.State,
+		Player1:  b.Player1.sideSummary(p1Votes),
+		Player2:  b.Player2.sideSummary(p2Votes),
+		Events:   append([]string(nil), b.LastTurnLog()...),
+		Winner:   b.Winner,
+	}
+
+	return summary
+}
+
+func (bp *BattlePlayer) sideSummary(fanVotes int) SideSummary {
+	side := SideSummary{
+		PlayerID:    bp.ID,
+		TeamSize:    len(bp.Team),
+		FanVotes:    fanVotes,
+		ActiveIndex: bp.ActiveCharacter,
+	}
+
+	char := bp.GetActiveCharacter()
+	if char == nil || char.BattleStats == nil {
+		return side
+	}
+
+	side.Active = char.CharacterName()
+	side.Level = char.Level
+	side.HP = char.BattleStats.CurrentHP
+	side.MaxHP = char.BattleStats.MaxHP
+	side.Statuses = append([]constants.StatusEffect(nil), char.BattleStats.StatusEffects...)
+	side.AliveCount = bp.GetAlivePokemonCount()
+
+	return side
+}
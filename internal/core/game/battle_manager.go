@@ -93,15 +93,7 @@ func (bm *BattleManager) AcceptChallenge(challenged snowflake.ID) (*Battle, erro
 	}
 
 	// Create battle
-	battle := NewBattle(challenge.ChannelID, challenge.Challenger, challenge.Challenged)
-	battle.Settings = challenge.Settings
-	battle.State = BattleStateTeamSelection
-
-	// Register battle
-	bm.battles[battle.ID] = battle
-	bm.playerBattles[challenge.Challenger] = battle.ID
-	bm.playerBattles[challenged] = battle.ID
-	bm.channelBattles[challenge.ChannelID] = battle.ID
+	battle := bm.registerBattle(challenge.ChannelID, challenge.Challenger, challenge.Challenged, challenge.Settings)
 
 	// Remove challenge
 	delete(bm.challenges, challenged)
@@ -109,6 +101,84 @@ func (bm *BattleManager) AcceptChallenge(challenged snowflake.ID) (*Battle, erro
 	return battle, nil
 }
 
+// MatchOptions describe what a battle created by CreateMatch counts toward.
+type MatchOptions struct {
+	Ranked       bool
+	TournamentID uuid.UUID
+}
+
+// CreateMatch starts a battle between two matched players, as if one had
+// challenged the other and the challenge was accepted.
+func (bm *BattleManager) CreateMatch(player1, player2, channelID snowflake.ID, settings GameSettings, opts MatchOptions) (*Battle, error) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	if player1 == player2 {
+		return nil, fmt.Errorf("a player cannot be matched against themselves")
+	}
+	if _, exists := bm.playerBattles[player1]; exists {
+		return nil, fmt.Errorf("player 1 is already in a battle")
+	}
+	if _, exists := bm.playerBattles[player2]; exists {
+		return nil, fmt.Errorf("player 2 is already in a battle")
+	}
+
+	battle := bm.registerBattle(channelID, player1, player2, settings)
+	battle.Ranked = opts.Ranked
+	battle.TournamentID = opts.TournamentID
+
+	return battle, nil
+}
+
+func (bm *BattleManager) registerBattle(channelID, player1, player2 snowflake.ID, settings GameSettings) *Battle {
+	battle := NewBattle(channelID, player1, player2)
+	battle.Settings = settings
+	battle.State = BattleStateTeamSelection
+
+	bm.battles[battle.ID] = battle
+	bm.playerBattles[player1] = battle.ID
+	bm.playerBattles[player2] = battle.ID
+	bm.channelBattles[channelID] = battle.ID
+
+	return battle
+}
+
+// IsPlayerInBattle reports whether the player is currently in a battle.
+func (bm *BattleManager) IsPlayerInBattle(playerID snowflake.ID) bool {
+	bm.mutex.RLock()
+	defer bm.mutex.RUnlock()
+
+	_, exists := bm.playerBattles[playerID]
+	return exists
+}
+
+// IsCharacterInBattle reports whether the character is on a team in an
+// unfinished battle.
+func (bm *BattleManager) IsCharacterInBattle(characterID uuid.UUID) bool {
+	bm.mutex.RLock()
+	defer bm.mutex.RUnlock()
+
+	for _, battleID := range bm.playerBattles {
+		battle, exists := bm.battles[battleID]
+		if !exists {
+			continue
+		}
+
+		for _, player := range []*BattlePlayer{battle.Player1, battle.Player2} {
+			if player == nil {
+				continue
+			}
+			for _, character := range player.Team {
+				if character.ID == characterID {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
 func (bm *BattleManager) DeclineChallenge(challenged snowflake.ID) error {
 	bm.mutex.Lock()
 	defer bm.mutex.Unlock()
@@ -147,6 +217,67 @@ func (bm *BattleManager) GetChannelBattle(channelID snowflake.ID) (*Battle, bool
 	return battle, exists
 }
 
+// SetBattleThread records the thread the battle is played in, so the battle
+// can also be looked up from inside the thread with GetChannelBattle.
+func (bm *BattleManager) SetBattleThread(battleID uuid.UUID, threadID snowflake.ID) error {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	battle, exists := bm.battles[battleID]
+	if !exists {
+		return fmt.Errorf("battle not found")
+	}
+
+	battle.ThreadID = threadID
+	bm.channelBattles[threadID] = battleID
+	return nil
+}
+
+// SetVoteMessage makes a turn summary the message spectators vote on.
+func (bm *BattleManager) SetVoteMessage(battleID uuid.UUID, messageID snowflake.ID) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	if battle, exists := bm.battles[battleID]; exists {
+		battle.VoteMessageID = messageID
+	}
+}
+
+// VoteFanFavourite records a spectator's fan favourite vote in the battle
+// running in the given channel or thread. Only reactions on the battle's vote
+// message count, so a spectator can't vote on several messages at once.
+func (bm *BattleManager) VoteFanFavourite(channelID, messageID, voterID, playerID snowflake.ID) error {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	battleID, exists := bm.channelBattles[channelID]
+	if !exists {
+		return fmt.Errorf("no battle in this channel")
+	}
+
+	battle, exists := bm.battles[battleID]
+	if !exists {
+		return fmt.Errorf("battle not found")
+	}
+	if battle.VoteMessageID != messageID {
+		return fmt.Errorf("votes only count on the latest turn")
+	}
+
+	return battle.VoteFanFavourite(voterID, playerID)
+}
+
+// RemoveFanVote removes a spectator's fan favourite vote in the battle
+// running in the given channel or thread, when it was taken off the vote
+// message.
+func (bm *BattleManager) RemoveFanVote(channelID, messageID, voterID, playerID snowflake.ID) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	if battle, exists := bm.battles[bm.channelBattles[channelID]]; exists && battle.VoteMessageID == messageID {
+		battle.RemoveFanVote(voterID, playerID)
+	}
+}
+
 func (bm *BattleManager) GetBattle(battleID uuid.UUID) (*Battle, bool) {
 	bm.mutex.RLock()
 	defer bm.mutex.RUnlock()
@@ -155,6 +286,38 @@ func (bm *BattleManager) GetBattle(battleID uuid.UUID) (*Battle, bool) {
 	return battle, exists
 }
 
+// BattleProgress is a copy of how far a battle has got, safe to read while
+// the battle carries on.
+type BattleProgress struct {
+	ID        uuid.UUID
+	ThreadID  snowflake.ID
+	State     BattleState
+	StartedAt time.Time
+	TeamSize  [2]int // Characters each player picked
+	Alive     [2]int // Characters each player has left standing
+}
+
+// GetBattleProgress returns the progress of a battle, taken under the
+// manager's lock.
+func (bm *BattleManager) GetBattleProgress(battleID uuid.UUID) (BattleProgress, bool) {
+	bm.mutex.RLock()
+	defer bm.mutex.RUnlock()
+
+	battle, exists := bm.battles[battleID]
+	if !exists {
+		return BattleProgress{}, false
+	}
+
+	return BattleProgress{
+		ID:        battle.ID,
+		ThreadID:  battle.ThreadID,
+		State:     battle.State,
+		StartedAt: battle.StartedAt,
+		TeamSize:  [2]int{len(battle.Player1.Team), len(battle.Player2.Team)},
+		Alive:     [2]int{battle.Player1.GetAlivePokemonCount(), battle.Player2.GetAlivePokemonCount()},
+	}, true
+}
+
 func (bm *BattleManager) GetChallenge(challenged snowflake.ID) (*Challenge, bool) {
 	bm.mutex.RLock()
 	defer bm.mutex.RUnlock()
@@ -167,33 +330,85 @@ func (bm *BattleManager) AddCharacterToTeam(playerID snowflake.ID, character *Ch
 	bm.mutex.Lock()
 	defer bm.mutex.Unlock()
 
+	battle, player, err := bm.teamSelectionPlayer(playerID)
+	if err != nil {
+		return err
+	}
+
+	if err := validateTeamMember(battle, player.Team, character); err != nil {
+		return err
+	}
+
+	// Add character to team
+	player.Team = append(player.Team, prepareTeamMember(character))
+
+	return nil
+}
+
+// SetTeam replaces the player's team with the given characters. Either all
+// characters are added or, if any of them is invalid, none are.
+func (bm *BattleManager) SetTeam(playerID snowflake.ID, characters []*Character) error {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	battle, player, err := bm.teamSelectionPlayer(playerID)
+	if err != nil {
+		return err
+	}
+
+	if len(characters) != battle.Settings.TeamSize {
+		return fmt.Errorf("team must have exactly %d characters", battle.Settings.TeamSize)
+	}
+
+	team := make([]*Character, 0, len(characters))
+	for _, character := range characters {
+		if err := validateTeamMember(battle, team, character); err != nil {
+			return err
+		}
+		team = append(team, prepareTeamMember(character))
+	}
+
+	player.Team = team
+	return nil
+}
+
+func (bm *BattleManager) teamSelectionPlayer(playerID snowflake.ID) (*Battle, *BattlePlayer, error) {
 	battleID, exists := bm.playerBattles[playerID]
 	if !exists {
-		return fmt.Errorf("player is not in a battle")
+		return nil, nil, fmt.Errorf("player is not in a battle")
 	}
 
 	battle, exists := bm.battles[battleID]
 	if !exists {
-		return fmt.Errorf("battle not found")
+		return nil, nil, fmt.Errorf("battle not found")
 	}
 
 	if battle.State != BattleStateTeamSelection {
-		return fmt.Errorf("battle is not in team selection phase")
+		return nil, nil, fmt.Errorf("battle is not in team selection phase")
 	}
 
 	player := battle.GetPlayer(playerID)
 	if player == nil {
-		return fmt.Errorf("player not found in battle")
+		return nil, nil, fmt.Errorf("player not found in battle")
 	}
 
+	return battle, player, nil
+}
+
+func validateTeamMember(battle *Battle, team []*Character, character *Character) error {
 	// Check team size limit
-	if len(player.Team) >= battle.Settings.TeamSize {
+	if len(team) >= battle.Settings.TeamSize {
 		return fmt.Errorf("team is already full")
 	}
 
+	// Characters held in escrow by the market or an auction can't battle
+	if character.Locked {
+		return fmt.Errorf("%s is up for sale on the market or in an auction", character.CharacterName())
+	}
+
 	// Check for duplicates if not allowed
 	if !battle.Settings.AllowDuplicates {
-		for _, teamChar := range player.Team {
+		for _, teamChar := range team {
 			if teamChar.CharacterID == character.CharacterID {
 				return fmt.Errorf("duplicate characters not allowed")
 			}
@@ -205,13 +420,18 @@ func (bm *BattleManager) AddCharacterToTeam(playerID snowflake.ID, character *Ch
 		return fmt.Errorf("character level exceeds cap of %d", battle.Settings.LevelCap)
 	}
 
-	// Add character to team
-	charCopy := *character // Create a copy to avoid modifying original
-	player.Team = append(player.Team, &charCopy)
-
 	return nil
 }
 
+func prepareTeamMember(character *Character) *Character {
+	charCopy := *character // Create a copy to avoid modifying original
+	if len(charCopy.Moves) == 0 {
+		charCopy.Moves = DefaultMoveset(charCopy.Data(), charCopy.Level)
+	}
+	charCopy.IsInBattle = true
+	return &charCopy
+}
+
 func (bm *BattleManager) StartBattle(battleID uuid.UUID) error {
 	bm.mutex.Lock()
 	defer bm.mutex.Unlock()
@@ -253,6 +473,25 @@ func (bm *BattleManager) ProcessBattleTurn(battleID uuid.UUID) error {
 	return battle.ProcessTurn()
 }
 
+// ProcessTurnIfReady processes the current turn once both players have
+// submitted their actions. Only one caller processes a given turn; processed
+// reports whether this call did.
+func (bm *BattleManager) ProcessTurnIfReady(battleID uuid.UUID) (processed bool, err error) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	battle, exists := bm.battles[battleID]
+	if !exists {
+		return false, fmt.Errorf("battle not found")
+	}
+
+	if !battle.BothPlayersHaveActions() {
+		return false, nil
+	}
+
+	return true, battle.ProcessTurn()
+}
+
 func (bm *BattleManager) EndBattle(battleID uuid.UUID) error {
 	bm.mutex.Lock()
 	defer bm.mutex.Unlock()
@@ -262,10 +501,35 @@ func (bm *BattleManager) EndBattle(battleID uuid.UUID) error {
 		return fmt.Errorf("battle not found")
 	}
 
+	bm.endBattle(battle)
+	return nil
+}
+
+// EndTeamSelection ends a battle whose players are still picking their teams.
+// It reports whether the battle was ended.
+func (bm *BattleManager) EndTeamSelection(battleID uuid.UUID) bool {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	battle, exists := bm.battles[battleID]
+	if !exists || battle.State != BattleStateTeamSelection {
+		return false
+	}
+
+	bm.endBattle(battle)
+	return true
+}
+
+func (bm *BattleManager) endBattle(battle *Battle) {
 	// Clean up battle references
 	delete(bm.playerBattles, battle.Player1.ID)
 	delete(bm.playerBattles, battle.Player2.ID)
-	delete(bm.channelBattles, battle.ChannelID)
+	if bm.channelBattles[battle.ChannelID] == battle.ID {
+		delete(bm.channelBattles, battle.ChannelID)
+	}
+	if battle.ThreadID != 0 {
+		delete(bm.channelBattles, battle.ThreadID)
+	}
 
 	// Mark battle as finished
 	battle.State = BattleStateFinished
@@ -274,8 +538,6 @@ func (bm *BattleManager) EndBattle(battleID uuid.UUID) error {
 
 	// Keep battle in memory for a while for viewing results
 	// In production, you might want to save to database here
-
-	return nil
 }
 
 func (bm *BattleManager) CleanupExpiredChallenges() {
@@ -244,14 +244,14 @@ func (b *BattleStats) CanMove() bool {
 		return false
 	}
 
-	if b.HasStatusEffect(constants.StatusParalyze) {
-		// 25% chance to be paralyzed
-		return rand.Intn(100) >= 25
-	}
-
 	return true
 }
 
+// IsFullyParalyzed rolls the 25% chance of a paralyzed character being unable to move.
+func (b *BattleStats) IsFullyParalyzed(rng *rand.Rand) bool {
+	return b.HasStatusEffect(constants.StatusParalyze) && rng.Intn(100) < 25
+}
+
 func (b *BattleStats) TakeDamage(damage int) {
 	b.CurrentHP -= damage
 	if b.CurrentHP < 0 {
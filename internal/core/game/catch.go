@@ -0,0 +1,77 @@
+package game
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const hintBlank = '_'
+
+// NormalizeName folds a name for comparison, ignoring case, accents and
+// surrounding or repeated whitespace, so "  ubel " and "Übel" compare equal.
+func NormalizeName(name string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		folded = name
+	}
+	return strings.ToLower(strings.Join(strings.Fields(folded), " "))
+}
+
+// NameMatches reports whether a guess names the character.
+func NameMatches(guess, name string) bool {
+	normalized := NormalizeName(guess)
+	return normalized != "" && normalized == NormalizeName(name)
+}
+
+// NameHint reveals part of the character's name, with more letters shown at
+// higher levels. The same character always reveals its letters in the same
+// order and at least one letter stays hidden.
+func (c *Character) NameHint(level int) string {
+	name := []rune(c.CharacterName())
+
+	var letters []int
+	for i, r := range name {
+		if unicode.IsLetter(r) {
+			letters = append(letters, i)
+		}
+	}
+
+	// Reveal roughly a quarter of the letters per level
+	revealed := level * max(1, len(letters)/4)
+	revealed = min(revealed, len(letters)-1)
+
+	rng := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(c.ID[:8]))))
+	rng.Shuffle(len(letters), func(i, j int) {
+		letters[i], letters[j] = letters[j], letters[i]
+	})
+
+	hint := make([]rune, len(name))
+	for i, r := range name {
+		hint[i] = r
+		if unicode.IsLetter(r) {
+			hint[i] = hintBlank
+		}
+	}
+	for _, i := range letters[:max(revealed, 0)] {
+		hint[i] = name[i]
+	}
+
+	return string(hint)
+}
+
+// FindBaseCharacter returns the character with the given name, ignoring case
+// and accents.
+func FindBaseCharacter(name string) (BaseCharacter, bool) {
+	for _, character := range Characters {
+		if NameMatches(name, character.Name) {
+			return character, true
+		}
+	}
+	return BaseCharacter{}, false
+}
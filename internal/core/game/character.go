@@ -14,6 +14,12 @@ import (
 	"github.com/theoreotm/friemon/constants"
 )
 
+const (
+	MaxIvTotal = 6 * 31 // IV total of a character with perfect IVs
+	MaxLevel   = 100
+	ShinyOdds  = 1027 // One in this many characters is shiny
+)
+
 type Character struct {
 	ID               uuid.UUID // Database ID
 	OwnerID          string    // Snowflake ID of the owner
@@ -37,6 +43,7 @@ type Character struct {
 
 	Nickname  string  // The nickname of the character
 	Favourite bool    // Whether the character is a favourite or not
+	Locked    bool    // Whether the character is held in escrow by a market listing or auction
 	HeldItem  int     // The held item of the character
 	Moves     []int32 // The moves of the character TODO: Type this field
 	Color     int32
@@ -51,6 +58,12 @@ func RandomPersonality() constants.Personality {
 	return constants.Personalities[rand.Intn(len(constants.Personalities))]
 }
 
+// RollShiny reports whether a character is shiny, with the odds multiplied by
+// multiplier.
+func RollShiny(multiplier float64) bool {
+	return rand.Float64() < multiplier/ShinyOdds
+}
+
 func NewCharacter(ownerID string) *Character {
 	c := &Character{}
 	c.OwnerID = ownerID
@@ -62,16 +75,30 @@ func NewCharacter(ownerID string) *Character {
 	return c
 }
 
-// RandomCharacterSpawn returns an unclaimed character
-func RandomCharacterSpawn() *Character {
-	c := &Character{}
+// RandomCharacterSpawn returns an unclaimed character rolled with the spawn
+// modifiers. It gets its ID up front so each spawn can be told apart before it
+// is saved.
+func RandomCharacterSpawn(modifiers SpawnModifiers) *Character {
+	c := &Character{ID: uuid.New()}
 	c.Randomize()
+	c.CharacterID = RandomBaseCharacterWith(modifiers).ID
+	c.Shiny = RollShiny(modifiers.ShinyMultiplier)
+	c.RaiseIVs(modifiers.MinIV)
 
 	return c
 }
 
 // Returns the embed image for the character
 func (c *Character) Image() (*discord.File, error) {
+	return characterImage(c.CharacterID)
+}
+
+// Image returns the species' artwork, the same image its characters use.
+func (bc BaseCharacter) Image() (*discord.File, error) {
+	return characterImage(bc.ID)
+}
+
+func characterImage(characterID int) (*discord.File, error) {
 	loadImage := func(filePath string) (io.Reader, error) {
 		file, err := os.Open(filePath)
 		if err != nil {
@@ -85,7 +112,7 @@ func (c *Character) Image() (*discord.File, error) {
 		assetsDir = "./assets"
 	}
 	slog.Info("Assets dir", "path", assetsDir)
-	filePath := fmt.Sprintf("%s/characters/%v.png", assetsDir, c.CharacterID)
+	filePath := fmt.Sprintf("%s/characters/%v.png", assetsDir, characterID)
 	loa, err := loadImage(filePath)
 	if err != nil {
 		return nil, err
@@ -95,9 +122,28 @@ func (c *Character) Image() (*discord.File, error) {
 }
 
 func (c *Character) Randomize() {
-	randomId := randomInt(1, len(Characters))
+	c.CharacterID = RandomBaseCharacter().ID
+	c.RerollIVs()
+
+	c.Personality = RandomPersonality()
+	c.Level = int(math.Min(math.Max(float64(int(normalRandom(20, 10))), 1), 100))
+	c.Shiny = RollShiny(1)
+}
+
+// RaiseIVs lifts every IV below minimum up to it.
+func (c *Character) RaiseIVs(minimum int) {
+	ivs := []*int{&c.IvHP, &c.IvAtk, &c.IvDef, &c.IvSpAtk, &c.IvSpDef, &c.IvSpd}
 
-	c.CharacterID = Characters[randomId].ID
+	total := 0
+	for _, iv := range ivs {
+		*iv = max(*iv, minimum)
+		total += *iv
+	}
+	c.IvTotal = float64(total)
+}
+
+// RerollIVs gives the character new random IVs.
+func (c *Character) RerollIVs() {
 	ivs := make([]int, 6)
 	for i := range ivs {
 		ivs[i] = randomInt(1, 31)
@@ -110,10 +156,6 @@ func (c *Character) Randomize() {
 	c.IvSpDef = ivs[4]
 	c.IvSpd = ivs[5]
 	c.IvTotal = float64(ivs[0] + ivs[1] + ivs[2] + ivs[3] + ivs[4] + ivs[5])
-
-	c.Personality = RandomPersonality()
-	c.Level = int(math.Min(math.Max(float64(int(normalRandom(20, 10))), 1), 100))
-	c.Shiny = rand.Intn(1028-1) == 1
 }
 
 func (c *Character) CharacterName() string {
@@ -131,11 +173,44 @@ func (c *Character) Data() BaseCharacter {
 }
 
 func (c *Character) MaxXP() int {
-	return 250 + 25*c.Level
+	return levelXP(c.Level)
+}
+
+// levelXP is the XP it takes to get from a level to the next.
+func levelXP(level int) int {
+	return 250 + 25*level
+}
+
+// XPToLevel returns the XP the character needs to reach the given level,
+// capped at MaxLevel.
+func (c *Character) XPToLevel(level int) int {
+	xp := -c.XP
+	for l := c.Level; l < min(level, MaxLevel); l++ {
+		xp += levelXP(l)
+	}
+	return max(xp, 0)
+}
+
+// AddXP gives the character XP, levelling it up each time its XP fills. It
+// returns the number of levels gained.
+func (c *Character) AddXP(xp int) int {
+	if c.Level >= MaxLevel {
+		return 0
+	}
+
+	c.XP += xp
+	levels := 0
+	for c.XP >= c.MaxXP() && c.Level < MaxLevel {
+		c.XP -= c.MaxXP()
+		c.Level++
+		levels++
+	}
+
+	return levels
 }
 
 func (c *Character) MaxHP() int {
-	return (2*c.Data().HP+c.IvHP+5)*int(c.Level/100) + c.Level + 10
+	return (2*c.Data().HP+c.IvHP+5)*c.Level/100 + c.Level + 10
 }
 
 func (c *Character) HP() int {
@@ -218,7 +293,7 @@ func (c Character) Format(spec string) string {
 
 // returns the percentage of the character's IVs. Eg: 0.75 for a character with 75% IV
 func (c *Character) IvPercentage() string {
-	percentage := float64((c.IvTotal / 186) * 100)
+	percentage := float64((c.IvTotal / MaxIvTotal) * 100)
 	return fmt.Sprintf("%.2f", percentage) + "%"
 }
 
@@ -256,7 +331,7 @@ func (c *Character) CalculateTurnPriority() {
 }
 
 func (c *Character) InitializeBattleStats() {
-	c.BattleStats = &BattleStats{}
+	c.BattleStats = NewBattleStats(c.MaxHP())
 }
 
 func (c *Character) ResetAfterBattle() {
@@ -304,8 +379,8 @@ func calcStat(character *Character, stat string) int {
 		baseStat = 0
 	}
 
-	// Calculate the stat with the formula and apply personality multiplier
-	calculated := math.Floor((float64(((2*baseStat+iv+5)*character.Level)/100 + 5)) * getPersonalityMultiplier(character.Personality, stat))
+	// Calculate the stat with the formula and apply personality and held item multipliers
+	calculated := math.Floor((float64(((2*baseStat+iv+5)*character.Level)/100 + 5)) * getPersonalityMultiplier(character.Personality, stat) * heldItemMultiplier(character.HeldItem, stat))
 
 	return int(calculated)
 }
@@ -1,5 +1,7 @@
 package game
 
+import "strings"
+
 type Type int
 
 const (
@@ -24,18 +26,51 @@ const (
 	TypeFairy                // 18
 )
 
-var Himmel = NewBaseCharacter(1, "Himmel", types(TypeFlying, TypeFairy), 70, 155, 80, 90, 70, 135)
-var Frieren = NewBaseCharacter(2, "Frieren", types(TypeIce, TypeElectric), 70, 90, 55, 155, 135, 95)
-var Eisen = NewBaseCharacter(3, "Eisen", types(TypeSteel, TypeFighting), 110, 125, 130, 80, 95, 60)
-var Heiter = NewBaseCharacter(4, "Heiter", types(TypeNormal, TypePoison), 135, 95, 100, 125, 110, 35)
-var Fern = NewBaseCharacter(5, "Fern", types(TypeWater, TypeElectric), 70, 80, 55, 135, 60, 130)
-var Stark = NewBaseCharacter(6, "Stark", types(TypeFire, TypeSteel), 110, 125, 70, 80, 70, 75)
-var Sein = NewBaseCharacter(7, "Sein", types(TypeGrass, TypePoison), 130, 85, 90, 95, 90, 40)
-var Ubel = NewBaseCharacter(8, "Übel", types(TypeDark, TypeNone), 50, 65, 50, 135, 65, 115)
-var Land = NewBaseCharacter(9, "Land", types(TypeGround, TypeGhost), 55, 50, 80, 110, 105, 90)
-var Denken = NewBaseCharacter(10, "Denken", types(TypePsychic, TypeNone), 110, 85, 80, 120, 85, 30)
-var Flamme = NewBaseCharacter(11, "Flamme", types(TypeFire, TypeFairy), 100, 100, 90, 150, 140, 90)
-var Serie = NewBaseCharacter(12, "Serie", types(TypeNormal, TypeNone), 70, 100, 60, 170, 170, 100)
+var typeNames = [...]string{
+	"None", "Normal", "Fighting", "Flying", "Poison", "Ground", "Rock", "Bug", "Ghost", "Steel",
+	"Fire", "Water", "Grass", "Electric", "Psychic", "Ice", "Dragon", "Dark", "Fairy",
+}
+
+func (t Type) String() string {
+	if t < 0 || int(t) >= len(typeNames) {
+		return "Unknown"
+	}
+	return typeNames[t]
+}
+
+// ParseType returns the type with the given name, ignoring case.
+func ParseType(name string) (Type, bool) {
+	for i, typeName := range typeNames {
+		if strings.EqualFold(typeName, name) {
+			return Type(i), true
+		}
+	}
+	return TypeNone, false
+}
+
+// HasType reports whether the character is of the given type.
+func (bc BaseCharacter) HasType(t Type) bool {
+	return bc.Type0 == t || bc.Type1 == t
+}
+
+var Himmel = NewBaseCharacter(1, "Himmel", types(TypeFlying, TypeFairy), RarityRare, 70, 155, 80, 90, 70, 135)
+var Frieren = NewBaseCharacter(2, "Frieren", types(TypeIce, TypeElectric), RarityRare, 70, 90, 55, 155, 135, 95)
+var Eisen = NewBaseCharacter(3, "Eisen", types(TypeSteel, TypeFighting), RarityUncommon, 110, 125, 130, 80, 95, 60)
+var Heiter = NewBaseCharacter(4, "Heiter", types(TypeNormal, TypePoison), RarityUncommon, 135, 95, 100, 125, 110, 35)
+var Fern = NewBaseCharacter(5, "Fern", types(TypeWater, TypeElectric), RarityUncommon, 70, 80, 55, 135, 60, 130)
+var Stark = NewBaseCharacter(6, "Stark", types(TypeFire, TypeSteel), RarityCommon, 110, 125, 70, 80, 70, 75)
+var Sein = NewBaseCharacter(7, "Sein", types(TypeGrass, TypePoison), RarityCommon, 130, 85, 90, 95, 90, 40)
+var Ubel = NewBaseCharacter(8, "Übel", types(TypeDark, TypeNone), RarityUncommon, 50, 65, 50, 135, 65, 115)
+var Land = NewBaseCharacter(9, "Land", types(TypeGround, TypeGhost), RarityCommon, 55, 50, 80, 110, 105, 90)
+var Denken = NewBaseCharacter(10, "Denken", types(TypePsychic, TypeNone), RarityCommon, 110, 85, 80, 120, 85, 30)
+var Flamme = NewBaseCharacter(11, "Flamme", types(TypeFire, TypeFairy), RarityLegendary, 100, 100, 90, 150, 140, 90)
+var Serie = NewBaseCharacter(12, "Serie", types(TypeNormal, TypeNone), RarityMythical, 70, 100, 60, 170, 170, 100)
+
+// Forms, reached by evolving
+var SeasonedFern = NewBaseCharacter(13, "Seasoned Fern", types(TypeWater, TypeElectric), RarityUncommon, 85, 90, 70, 155, 75, 140)
+var SeasonedStark = NewBaseCharacter(14, "Seasoned Stark", types(TypeFire, TypeSteel), RarityCommon, 125, 145, 90, 85, 80, 85)
+var AwakenedFrieren = NewBaseCharacter(15, "Awakened Frieren", types(TypeIce, TypeElectric), RarityRare, 80, 95, 70, 180, 150, 110)
+var AwakenedUbel = NewBaseCharacter(16, "Awakened Übel", types(TypeDark, TypeNone), RarityUncommon, 60, 75, 60, 155, 75, 135)
 
 func types(type0, type1 Type) []Type {
 	if type1 == 0 {
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+)
+
+// CharacterFilter narrows down a player's collection. Zero values match everything.
+type CharacterFilter struct {
+	Name        string // Character name prefix, ignoring case
+	Nickname    string // Part of the nickname, ignoring case
+	Type        Type
+	Personality string
+	Shiny       *bool
+	Favourite   *bool
+	MinLevel    int
+	MaxLevel    int
+	MinIV       float64 // Percentage
+	MaxIV       float64 // Percentage
+}
+
+// CharacterIDs returns the base characters matching the name and type filters,
+// or nil if neither is set.
+func (f CharacterFilter) CharacterIDs() []int {
+	if f.Name == "" && f.Type == TypeNone {
+		return nil
+	}
+
+	ids := make([]int, 0)
+	for id, character := range Characters {
+		if f.Name != "" && !strings.HasPrefix(strings.ToLower(character.Name), strings.ToLower(f.Name)) {
+			continue
+		}
+		if f.Type != TypeNone && !character.HasType(f.Type) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+	return ids
+}
+
+// IsZero reports whether the filter matches every character.
+func (f CharacterFilter) IsZero() bool {
+	return f == CharacterFilter{}
+}
+
+// ReleaseValue is the number of coins paid for releasing a character, growing
+// with its level and IVs. Shinies are worth five times as much.
+func ReleaseValue(c Character) int {
+	value := 10 + c.Level*2 + int(c.IvTotal/MaxIvTotal*100)
+	if c.Shiny {
+		value *= 5
+	}
+	return value
+}
+
+// PendingRelease is a release waiting for its owner to confirm it.
+type PendingRelease struct {
+	ID           uuid.UUID
+	UserID       snowflake.ID
+	CharacterIDs []uuid.UUID
+	Value        int
+}
@@ -19,13 +19,14 @@ type DamageResult struct {
 	CalculationDetails  string  `json:"calculation_details,omitempty"`
 }
 
-func CalculateDamage(attacker, defender *Character, move Move, settings GameSettings) DamageResult {
+// CalculateDamage rolls accuracy, critical hits and the damage spread with rng.
+func CalculateDamage(attacker, defender *Character, move Move, settings GameSettings, rng *rand.Rand) DamageResult {
 	result := DamageResult{
 		Hit: true,
 	}
 
 	// Check accuracy first
-	if !checkAccuracy(attacker, defender, move, settings) {
+	if !checkAccuracy(attacker, defender, move, settings, rng) {
 		result.Hit = false
 		return result
 	}
@@ -77,7 +78,7 @@ func CalculateDamage(attacker, defender *Character, move Move, settings GameSett
 
 	// Check for critical hit
 	if settings.CriticalHitsEnabled {
-		result.IsCritical = checkCriticalHit(attacker, move)
+		result.IsCritical = checkCriticalHit(attacker, move, rng)
 		if result.IsCritical {
 			// Critical hits ignore negative stat changes for attacker and positive for defender
 			if move.Category == MoveCatPhysical {
@@ -124,7 +125,7 @@ func CalculateDamage(attacker, defender *Character, move Move, settings GameSett
 	}
 
 	// Random factor (85-100%)
-	randomFactor := (float64(rand.Intn(16)) + 85) / 100
+	randomFactor := (float64(rng.Intn(16)) + 85) / 100
 
 	// Final damage calculation
 	finalDamage := baseDamage * stab * typeEffectiveness * critMultiplier * randomFactor
@@ -147,7 +148,7 @@ func CalculateDamage(attacker, defender *Character, move Move, settings GameSett
 	return result
 }
 
-func checkAccuracy(attacker, defender *Character, move Move, settings GameSettings) bool {
+func checkAccuracy(attacker, defender *Character, move Move, settings GameSettings, rng *rand.Rand) bool {
 	accuracy := float64(move.Accuracy)
 
 	// Perfect accuracy moves
@@ -167,11 +168,11 @@ func checkAccuracy(attacker, defender *Character, move Move, settings GameSettin
 		accuracy = 100
 	}
 
-	roll := rand.Intn(100) + 1
+	roll := rng.Intn(100) + 1
 	return roll <= int(accuracy)
 }
 
-func checkCriticalHit(attacker *Character, move Move) bool {
+func checkCriticalHit(attacker *Character, move Move, rng *rand.Rand) bool {
 	// Base critical hit rate is 1/24 (about 4.17%)
 	critRate := 1.0 / 24.0
 
@@ -198,7 +199,7 @@ func checkCriticalHit(attacker *Character, move Move) bool {
 	// Apply any temporary crit boosts
 	critRate += float64(attacker.BattleStats.CritBoost) * (1.0 / 24.0)
 
-	return rand.Float64() < critRate
+	return rng.Float64() < critRate
 }
 
 func buildCalculationDetails(level, power, attack, defense, stab, typeEff, crit, random, final float64) string {
@@ -0,0 +1,171 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	StatBarWidth = 10
+	StatBarScale = 200 // Base stat that fills a whole bar
+)
+
+// DexEntry is how many of one species a player has caught. Evolving into a
+// form counts as catching it.
+type DexEntry struct {
+	CharacterID   int
+	Caught        int
+	Shinies       int
+	FirstCaughtAt time.Time
+}
+
+// Dex is a player's caught species, keyed by character ID.
+type Dex map[int]DexEntry
+
+// NewDex builds a player's dex from their entries.
+func NewDex(entries []DexEntry) Dex {
+	dex := make(Dex, len(entries))
+	for _, entry := range entries {
+		dex[entry.CharacterID] = entry
+	}
+	return dex
+}
+
+// Species counts the species caught at least once.
+func (d Dex) Species() int {
+	count := 0
+	for _, entry := range d {
+		if entry.Caught > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// ShinySpecies counts the species caught shiny at least once.
+func (d Dex) ShinySpecies() int {
+	count := 0
+	for _, entry := range d {
+		if entry.Shinies > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// DexSize returns the number of species there are to catch, forms included.
+func DexSize() int {
+	return len(Characters)
+}
+
+// DexCharacters returns every species in dex order.
+func DexCharacters() []BaseCharacter {
+	characters := make([]BaseCharacter, 0, len(Characters))
+	for _, character := range Characters {
+		characters = append(characters, character)
+	}
+	sort.Slice(characters, func(i, j int) bool {
+		return characters[i].ID < characters[j].ID
+	})
+	return characters
+}
+
+// DexMilestone rewards catching a share of every species.
+type DexMilestone struct {
+	Percent int
+	Coins   int
+	ItemID  int // Zero for no item
+}
+
+// DexMilestones are the completion rewards, in the order they are reached.
+var DexMilestones = []DexMilestone{
+	{Percent: 25, Coins: 1000, ItemID: XPCandyL.ID},
+	{Percent: 50, Coins: 2500, ItemID: RareCandy.ID},
+	{Percent: 75, Coins: 5000, ItemID: IVRerollCapsule.ID},
+	{Percent: 100, Coins: 10000, ItemID: AwakeningCrystal.ID},
+}
+
+// Species returns how many species reach the milestone.
+func (m DexMilestone) Species() int {
+	return (DexSize()*m.Percent + 99) / 100
+}
+
+// Rewards describes what the milestone gives.
+func (m DexMilestone) Rewards() string {
+	rewards := fmt.Sprintf("%d coins", m.Coins)
+	if item, ok := ItemsRegistry[m.ItemID]; ok {
+		rewards += fmt.Sprintf(", %s %s", item.Emoji, item.Name)
+	}
+	return rewards
+}
+
+// ReachedDexMilestones returns the milestones the dex reaches beyond the ones
+// already rewarded, which are always the first claimed of DexMilestones.
+func (d Dex) ReachedDexMilestones(claimed int) []DexMilestone {
+	species := d.Species()
+	reached := claimed
+	for reached < len(DexMilestones) && species >= DexMilestones[reached].Species() {
+		reached++
+	}
+	if reached <= claimed {
+		return nil
+	}
+	return DexMilestones[claimed:reached]
+}
+
+// StatBar draws a base stat as a bar, full at StatBarScale.
+func StatBar(value int) string {
+	filled := min(StatBarWidth, max(0, (value*StatBarWidth+StatBarScale/2)/StatBarScale))
+	return strings.Repeat("█", filled) + strings.Repeat("░", StatBarWidth-filled)
+}
+
+// BaseStatTotal sums the character's base stats.
+func (bc BaseCharacter) BaseStatTotal() int {
+	return bc.HP + bc.Atk + bc.Def + bc.SpAtk + bc.SpDef + bc.Spd
+}
+
+// TypeMatchups groups the attacking types by how effective they are against
+// the character. Types that hit it normally are left out.
+func (bc BaseCharacter) TypeMatchups() map[float64][]Type {
+	matchups := map[float64][]Type{}
+	for attacking := TypeNormal; attacking <= TypeFairy; attacking++ {
+		effectiveness := GetTypeEffectiveness(attacking, bc.Type0, bc.Type1)
+		if effectiveness != NormalEffective {
+			matchups[effectiveness] = append(matchups[effectiveness], attacking)
+		}
+	}
+	return matchups
+}
+
+// LearnsetMove is a move a character learns by levelling and the level it
+// learns it at.
+type LearnsetMove struct {
+	Move  Move
+	Level int
+}
+
+// Learnset returns the moves of the character's starting moveset at level 1,
+// then the moves it learns by levelling in the order it learns them. Once its
+// slots are full, a new move only replaces a weaker one.
+func (bc BaseCharacter) Learnset() []LearnsetMove {
+	var learnset []LearnsetMove
+	starting := DefaultMoveset(bc, 0)
+	for _, id := range starting {
+		learnset = append(learnset, LearnsetMove{Move: MovesRegistry[int(id)], Level: 1})
+	}
+
+	for id := 1; id <= len(MovesRegistry); id++ {
+		move, exists := MovesRegistry[id]
+		if !exists || slices.Contains(starting, int32(id)) || !bc.LearnsMove(move) {
+			continue
+		}
+		learnset = append(learnset, LearnsetMove{Move: move, Level: MoveLearnLevel(move)})
+	}
+	sort.SliceStable(learnset, func(i, j int) bool {
+		return learnset[i].Level < learnset[j].Level
+	})
+	return learnset
+}
@@ -0,0 +1,63 @@
+package game
+
+import "testing"
+
+// dexWithSpecies returns a dex with the first species caught, in dex order.
+func dexWithSpecies(species int) Dex {
+	entries := make([]DexEntry, 0, species)
+	for _, character := range DexCharacters()[:species] {
+		entries = append(entries, DexEntry{CharacterID: character.ID, Caught: 1})
+	}
+	return NewDex(entries)
+}
+
+func TestReachedDexMilestones(t *testing.T) {
+	quarter := DexMilestones[0].Species()
+	half := DexMilestones[1].Species()
+
+	tests := []struct {
+		name    string
+		species int
+		claimed int
+		want    []int // Percent of each milestone reached
+	}{
+		{"empty dex", 0, 0, nil},
+		{"one short of the first", quarter - 1, 0, nil},
+		{"first reached", quarter, 0, []int{25}},
+		{"first already claimed", quarter, 1, nil},
+		{"two reached at once", half, 0, []int{25, 50}},
+		{"second reached after the first", half, 1, []int{50}},
+		{"complete dex", DexSize(), 0, []int{25, 50, 75, 100}},
+		{"complete dex with the first two claimed", DexSize(), 2, []int{75, 100}},
+		{"everything claimed", DexSize(), len(DexMilestones), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dexWithSpecies(tt.species).ReachedDexMilestones(tt.claimed)
+			if len(got) != len(tt.want) {
+				t.Fatalf("reached %v, want %v", got, tt.want)
+			}
+			for i, milestone := range got {
+				if milestone.Percent != tt.want[i] {
+					t.Fatalf("reached %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDexSpecies(t *testing.T) {
+	dex := NewDex([]DexEntry{
+		{CharacterID: 1, Caught: 3, Shinies: 1},
+		{CharacterID: 2, Caught: 1},
+		{CharacterID: 3}, // Never caught
+	})
+
+	if got := dex.Species(); got != 2 {
+		t.Errorf("species = %d, want 2", got)
+	}
+	if got := dex.ShinySpecies(); got != 1 {
+		t.Errorf("shiny species = %d, want 1", got)
+	}
+}
@@ -0,0 +1,114 @@
+package game
+
+import (
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	DailyBaseReward        = 100
+	DailyStreakBonus       = 25  // Extra coins per day of streak
+	MaxDailyStreakBonus    = 6   // Days of streak that add to the reward
+	DailyWeeklyBonus       = 500 // Extra coins every seventh day in a row
+	BattleWinReward        = 25
+	RankedBattleWinReward  = 50
+	MaxBattleRewardsPerDay = 10
+	MaxShopQuantity        = 100
+)
+
+type TransactionReason string
+
+const (
+	ReasonDaily          TransactionReason = "daily"
+	ReasonBattleWin      TransactionReason = "battle_win"
+	ReasonShop           TransactionReason = "shop"
+	ReasonRelease        TransactionReason = "release"
+	ReasonTrade          TransactionReason = "trade"
+	ReasonMarketPurchase TransactionReason = "market_purchase"
+	ReasonMarketSale     TransactionReason = "market_sale"
+	ReasonAuctionBid     TransactionReason = "auction_bid"
+	ReasonAuctionRefund  TransactionReason = "auction_refund"
+	ReasonAuctionSale    TransactionReason = "auction_sale"
+	ReasonSeasonReward   TransactionReason = "season_reward"
+	ReasonLevelUp        TransactionReason = "level_up"
+	ReasonDexMilestone   TransactionReason = "dex_milestone"
+)
+
+var transactionReasonNames = map[TransactionReason]string{
+	ReasonDaily:          "Daily reward",
+	ReasonBattleWin:      "Battle win",
+	ReasonShop:           "Shop purchase",
+	ReasonRelease:        "Release",
+	ReasonTrade:          "Trade",
+	ReasonMarketPurchase: "Market purchase",
+	ReasonMarketSale:     "Market sale",
+	ReasonAuctionBid:     "Auction bid",
+	ReasonAuctionRefund:  "Auction refund",
+	ReasonAuctionSale:    "Auction sale",
+	ReasonSeasonReward:   "Season reward",
+	ReasonLevelUp:        "Level-up reward",
+	ReasonDexMilestone:   "Dex milestone",
+}
+
+// Name returns a readable name for the reason.
+func (r TransactionReason) Name() string {
+	if name, ok := transactionReasonNames[r]; ok {
+		return name
+	}
+	return string(r)
+}
+
+// Transaction is one change to a user's balance, kept in the ledger.
+type Transaction struct {
+	ID        int
+	UserID    snowflake.ID
+	Amount    int // Negative when coins are spent
+	Balance   int // Balance after the change
+	Reason    TransactionReason
+	Reference string // What the change was for, such as a listing or item
+	CreatedAt time.Time
+}
+
+// DailyDay returns the start of the UTC day t falls in. Daily rewards reset
+// at that boundary.
+func DailyDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// NextDailyStreak returns the streak a daily claim at now would reach, and
+// false if the reward was already claimed today. Missing a day resets the
+// streak.
+func NextDailyStreak(lastClaim time.Time, streak int, now time.Time) (int, bool) {
+	if lastClaim.IsZero() {
+		return 1, true
+	}
+
+	today := DailyDay(now)
+	last := DailyDay(lastClaim)
+	switch {
+	case !last.Before(today):
+		return streak, false
+	case last.Equal(today.Add(-24 * time.Hour)):
+		return streak + 1, true
+	default:
+		return 1, true
+	}
+}
+
+// DailyReward returns the coins a daily claim on the given day of a streak gives.
+func DailyReward(streak int) int {
+	reward := DailyBaseReward + DailyStreakBonus*min(max(streak-1, 0), MaxDailyStreakBonus)
+	if streak > 0 && streak%7 == 0 {
+		reward += DailyWeeklyBonus
+	}
+	return reward
+}
+
+// BattleReward returns the coins for winning a battle.
+func BattleReward(ranked bool) int {
+	if ranked {
+		return RankedBattleWinReward
+	}
+	return BattleWinReward
+}
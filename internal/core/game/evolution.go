@@ -0,0 +1,123 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Evolution is a way a character changes into another form of itself.
+type Evolution struct {
+	FormID int // The character it becomes
+	Level  int // Lowest level it evolves at, zero for any level
+	ItemID int // Item used up to evolve, zero when levelling is enough
+}
+
+// Form returns the character the evolution leads to.
+func (e Evolution) Form() BaseCharacter {
+	return Characters[e.FormID]
+}
+
+// Item returns the item the evolution uses up, if it needs one.
+func (e Evolution) Item() (Item, bool) {
+	if e.ItemID == 0 {
+		return Item{}, false
+	}
+	item, ok := ItemsRegistry[e.ItemID]
+	return item, ok
+}
+
+// Requirement describes what the evolution needs, such as "at level 36".
+func (e Evolution) Requirement() string {
+	item, needsItem := e.Item()
+	switch {
+	case needsItem && e.Level > 0:
+		return fmt.Sprintf("with %s from level %d", item.NameWithArticle(), e.Level)
+	case needsItem:
+		return "with " + item.NameWithArticle()
+	default:
+		return fmt.Sprintf("at level %d", e.Level)
+	}
+}
+
+var (
+	evolutions  = map[int][]Evolution{} // Keyed by the ID of the character that evolves
+	evolvesFrom = map[int]int{}         // Keyed by form ID
+)
+
+func init() {
+	addEvolution(Fern, Evolution{FormID: SeasonedFern.ID, Level: 36})
+	addEvolution(Stark, Evolution{FormID: SeasonedStark.ID, Level: 36})
+	addEvolution(Frieren, Evolution{FormID: AwakenedFrieren.ID, ItemID: AwakeningCrystal.ID})
+	addEvolution(Ubel, Evolution{FormID: AwakenedUbel.ID, ItemID: AwakeningCrystal.ID})
+}
+
+func addEvolution(from BaseCharacter, evolution Evolution) {
+	evolutions[from.ID] = append(evolutions[from.ID], evolution)
+	evolvesFrom[evolution.FormID] = from.ID
+}
+
+// Evolutions returns the ways the character evolves.
+func (bc BaseCharacter) Evolutions() []Evolution {
+	return evolutions[bc.ID]
+}
+
+// EvolvesFrom returns the character this form evolves from. Forms only come
+// from evolving and never spawn wild.
+func (bc BaseCharacter) EvolvesFrom() (BaseCharacter, bool) {
+	id, ok := evolvesFrom[bc.ID]
+	if !ok {
+		return BaseCharacter{}, false
+	}
+	return Characters[id], true
+}
+
+// IsForm reports whether the character is an evolved form.
+func (bc BaseCharacter) IsForm() bool {
+	_, ok := evolvesFrom[bc.ID]
+	return ok
+}
+
+// ReachedEvolution returns the evolution that levelling from one level to
+// another unlocked, if any. Evolutions that need an item aren't reached by
+// levelling.
+func (c *Character) ReachedEvolution(fromLevel, toLevel int) (Evolution, bool) {
+	for _, evolution := range c.Data().Evolutions() {
+		if evolution.ItemID == 0 && evolution.Level > fromLevel && evolution.Level <= toLevel {
+			return evolution, true
+		}
+	}
+	return Evolution{}, false
+}
+
+// AvailableEvolution returns the first evolution the character can take now,
+// given the items its owner has.
+func (c *Character) AvailableEvolution(inventory []InventoryItem) (Evolution, bool) {
+	for _, evolution := range c.Data().Evolutions() {
+		if c.Level < evolution.Level {
+			continue
+		}
+		if evolution.ItemID != 0 && !slices.ContainsFunc(inventory, func(i InventoryItem) bool {
+			return i.ItemID == evolution.ItemID && i.Quantity > 0
+		}) {
+			continue
+		}
+		return evolution, true
+	}
+	return Evolution{}, false
+}
+
+// Evolve changes the character into the evolution's form. Its level, IVs,
+// nickname and shininess carry over, its stats follow the new form's base
+// stats, and it learns the moves of the form's types up to its level. The
+// caller takes the item the evolution uses.
+func (c *Character) Evolve(evolution Evolution) ([]LearnedMove, error) {
+	if !slices.Contains(c.Data().Evolutions(), evolution) {
+		return nil, fmt.Errorf("%s can't evolve into %s", c.CharacterName(), evolution.Form().Name)
+	}
+	if c.Level < evolution.Level {
+		return nil, fmt.Errorf("%s evolves into %s %s", c.CharacterName(), evolution.Form().Name, evolution.Requirement())
+	}
+
+	c.CharacterID = evolution.FormID
+	return c.LearnMoves(0, c.Level), nil
+}
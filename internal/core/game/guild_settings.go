@@ -0,0 +1,124 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	DefaultSpawnThreshold  = 4 // Messages before a character spawns
+	MaxSpawnThreshold      = 100
+	MaxSpawnCooldown       = time.Hour
+	MaxShinyMultiplier     = 10.0
+	MaxSpawnChannelEntries = 25              // Most channels in the allow or block list
+	SpawnLifetime          = 3 * time.Minute // How long a spawn can be caught before it wanders away
+)
+
+// GuildSettings configure how characters spawn and level up in a guild.
+type GuildSettings struct {
+	GuildID snowflake.ID
+
+	AllowedChannels   []snowflake.ID // When not empty, only these channels spawn
+	BlockedChannels   []snowflake.ID // These channels never spawn
+	RedirectChannelID snowflake.ID   // Spawns from every channel go here, zero to spawn in place
+
+	SpawnThresholdMin int // Fewest messages before a spawn
+	SpawnThresholdMax int // Most messages before a spawn
+	SpawnCooldown     time.Duration
+	ShinyMultiplier   float64
+	CatchByName       bool // Spawns hide their name and are caught by typing it
+
+	MinMessageLength  int          // Fewest letters for a message to count toward spawns and XP
+	BlockDuplicates   bool         // Whether repeating the last message counts
+	MessageRateLimit  int          // Most counted messages per user per minute, zero for no limit
+	MaxUserSpawnShare float64      // Share of a spawn's messages one user can send, 1 for no limit
+	ModLogChannelID   snowflake.ID // Spam is reported here, zero to not report it
+
+	LevelUpNotify    LevelUpNotify
+	LevelUpChannelID snowflake.ID // In-channel level-ups are announced here, zero to announce them in place
+}
+
+// DefaultGuildSettings returns the settings of a guild that has not configured anything.
+func DefaultGuildSettings(guildID snowflake.ID) GuildSettings {
+	return GuildSettings{
+		GuildID:           guildID,
+		SpawnThresholdMin: DefaultSpawnThreshold,
+		SpawnThresholdMax: DefaultSpawnThreshold,
+		ShinyMultiplier:   1,
+		MinMessageLength:  DefaultMinMessageLength,
+		BlockDuplicates:   true,
+		MessageRateLimit:  DefaultMessageRateLimit,
+		MaxUserSpawnShare: DefaultMaxUserSpawnShare,
+	}
+}
+
+// Validate checks the settings are within the allowed limits.
+func (s GuildSettings) Validate() error {
+	if s.SpawnThresholdMin < 1 || s.SpawnThresholdMax > MaxSpawnThreshold || s.SpawnThresholdMin > s.SpawnThresholdMax {
+		return fmt.Errorf("the message threshold must be between 1 and %d, with the minimum no higher than the maximum", MaxSpawnThreshold)
+	}
+	if s.SpawnCooldown < 0 || s.SpawnCooldown > MaxSpawnCooldown {
+		return fmt.Errorf("the spawn cooldown must be between 0 and %s", MaxSpawnCooldown)
+	}
+	if s.ShinyMultiplier < 1 || s.ShinyMultiplier > MaxShinyMultiplier {
+		return fmt.Errorf("the shiny multiplier must be between 1 and %g", MaxShinyMultiplier)
+	}
+	if s.MinMessageLength < 0 || s.MinMessageLength > MaxMinMessageLength {
+		return fmt.Errorf("the minimum message length must be between 0 and %d", MaxMinMessageLength)
+	}
+	if s.MessageRateLimit < 0 || s.MessageRateLimit > MaxMessageRateLimit {
+		return fmt.Errorf("the message rate limit must be between 0 and %d a minute", MaxMessageRateLimit)
+	}
+	if s.MaxUserSpawnShare <= 0 || s.MaxUserSpawnShare > 1 {
+		return fmt.Errorf("a user's share of spawn messages must be above 0%% and at most 100%%")
+	}
+	if s.LevelUpNotify < LevelUpInChannel || s.LevelUpNotify > LevelUpOff {
+		return fmt.Errorf("unknown level-up notification setting")
+	}
+	if len(s.AllowedChannels) > MaxSpawnChannelEntries || len(s.BlockedChannels) > MaxSpawnChannelEntries {
+		return fmt.Errorf("at most %d channels can be allowed or blocked", MaxSpawnChannelEntries)
+	}
+	return nil
+}
+
+// CountsMessages reports whether messages in a channel count toward spawns.
+func (s GuildSettings) CountsMessages(channelID snowflake.ID) bool {
+	if slices.Contains(s.BlockedChannels, channelID) {
+		return false
+	}
+	return len(s.AllowedChannels) == 0 || slices.Contains(s.AllowedChannels, channelID)
+}
+
+// SpawnChannel returns the channel a message in channelID spawns characters in.
+func (s GuildSettings) SpawnChannel(channelID snowflake.ID) snowflake.ID {
+	if s.RedirectChannelID != 0 {
+		return s.RedirectChannelID
+	}
+	return channelID
+}
+
+// LevelUpChannel returns the channel a level-up earned in channelID is
+// announced in.
+func (s GuildSettings) LevelUpChannel(channelID snowflake.ID) snowflake.ID {
+	if s.LevelUpChannelID != 0 {
+		return s.LevelUpChannelID
+	}
+	return channelID
+}
+
+// ShouldSpawn reports whether a character spawns after count messages. Past
+// the minimum threshold each message has an even chance among the remaining
+// counts, so spawns land uniformly between the minimum and maximum.
+func (s GuildSettings) ShouldSpawn(count int) bool {
+	if count < s.SpawnThresholdMin {
+		return false
+	}
+	if count >= s.SpawnThresholdMax {
+		return true
+	}
+	return rand.Intn(s.SpawnThresholdMax-count+1) == 0
+}
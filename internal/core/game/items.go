@@ -0,0 +1,331 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/theoreotm/friemon/constants"
+)
+
+type ItemCategory int
+
+const (
+	ItemCategoryConsumable ItemCategory = iota
+	ItemCategoryLure
+	ItemCategoryMint
+	ItemCategoryHeld
+	ItemCategoryEvolution
+)
+
+func (c ItemCategory) String() string {
+	switch c {
+	case ItemCategoryConsumable:
+		return "Consumable"
+	case ItemCategoryLure:
+		return "Lure"
+	case ItemCategoryMint:
+		return "Mint"
+	case ItemCategoryHeld:
+		return "Held Item"
+	case ItemCategoryEvolution:
+		return "Evolution Item"
+	default:
+		return "Unknown"
+	}
+}
+
+type Item struct {
+	ID          int
+	Name        string
+	Emoji       string
+	Description string
+	Category    ItemCategory
+	Price       int // Shop price in coins, 0 if the shop doesn't sell it
+
+	XP         int                // XP given by candies
+	Levels     int                // Levels given by candies
+	RerollIVs  bool               // Whether the item gives new random IVs
+	StatBoosts map[string]float64 // Stat multipliers while held, keyed like calcStat
+
+	XPMultiplier float64 // How much more XP the holder gains
+
+	LureMessages int     // Lures force a spawn within this many messages
+	LureBoost    float64 // Lures make rare and rarer tiers spawn this many times as often
+}
+
+// NameWithArticle returns the item's name after "a" or "an".
+func (i Item) NameWithArticle() string {
+	if i.Name != "" && strings.ContainsRune("AEIOU", rune(i.Name[0])) {
+		return "an " + i.Name
+	}
+	return "a " + i.Name
+}
+
+// Items registry
+var ItemsRegistry = map[int]Item{}
+
+func init() {
+	initializeItems()
+}
+
+func initializeItems() {
+	// Consumables
+	ItemsRegistry[1] = XPCandyS
+	ItemsRegistry[2] = XPCandyL
+	ItemsRegistry[3] = RareCandy
+	ItemsRegistry[4] = IVRerollCapsule
+
+	// Lures
+	ItemsRegistry[20] = RareSpawnLure
+
+	// Mints
+	ItemsRegistry[40] = PersonalityMint
+
+	// Held items
+	ItemsRegistry[60] = PowerBand
+	ItemsRegistry[61] = GuardCharm
+	ItemsRegistry[62] = FocusLens
+	ItemsRegistry[63] = CalmCharm
+	ItemsRegistry[64] = SwiftFeather
+	ItemsRegistry[65] = StudyGrimoire
+
+	// Evolution items
+	ItemsRegistry[80] = AwakeningCrystal
+}
+
+// Consumables
+var XPCandyS = Item{
+	ID:          1,
+	Name:        "XP Candy S",
+	Emoji:       "🍬",
+	Description: "Gives a character 100 XP.",
+	Category:    ItemCategoryConsumable,
+	Price:       150,
+	XP:          100,
+}
+
+var XPCandyL = Item{
+	ID:          2,
+	Name:        "XP Candy L",
+	Emoji:       "🍭",
+	Description: "Gives a character 1,000 XP.",
+	Category:    ItemCategoryConsumable,
+	Price:       1200,
+	XP:          1000,
+}
+
+var RareCandy = Item{
+	ID:          3,
+	Name:        "Rare Candy",
+	Emoji:       "🍡",
+	Description: "Raises a character's level by one.",
+	Category:    ItemCategoryConsumable,
+	Price:       2500,
+	Levels:      1,
+}
+
+var IVRerollCapsule = Item{
+	ID:          4,
+	Name:        "IV Reroll Capsule",
+	Emoji:       "💊",
+	Description: "Gives a character new random IVs. They can turn out better or worse.",
+	Category:    ItemCategoryConsumable,
+	Price:       10000,
+	RerollIVs:   true,
+}
+
+// Lures
+var RareSpawnLure = Item{
+	ID:          20,
+	Name:        "Rare Spawn Lure",
+	Emoji:       "🪔",
+	Description: "Forces a character to spawn in a channel within a few messages, with rare characters more likely.",
+	Category:    ItemCategoryLure,
+	Price:       2000,
+
+	LureMessages: 5,
+	LureBoost:    3,
+}
+
+// Mints
+var PersonalityMint = Item{
+	ID:          40,
+	Name:        "Personality Mint",
+	Emoji:       "🌿",
+	Description: "Changes a character's personality to one of your choice.",
+	Category:    ItemCategoryMint,
+	Price:       5000,
+}
+
+// Held items
+var PowerBand = Item{
+	ID:          60,
+	Name:        "Power Band",
+	Emoji:       "🎗️",
+	Description: "Raises the holder's Attack by 10%.",
+	Category:    ItemCategoryHeld,
+	Price:       3000,
+	StatBoosts:  map[string]float64{"atk": 1.1},
+}
+
+var GuardCharm = Item{
+	ID:          61,
+	Name:        "Guard Charm",
+	Emoji:       "🛡️",
+	Description: "Raises the holder's Defense by 10%.",
+	Category:    ItemCategoryHeld,
+	Price:       3000,
+	StatBoosts:  map[string]float64{"def": 1.1},
+}
+
+var FocusLens = Item{
+	ID:          62,
+	Name:        "Focus Lens",
+	Emoji:       "🔮",
+	Description: "Raises the holder's Special Attack by 10%.",
+	Category:    ItemCategoryHeld,
+	Price:       3000,
+	StatBoosts:  map[string]float64{"satk": 1.1},
+}
+
+var CalmCharm = Item{
+	ID:          63,
+	Name:        "Calm Charm",
+	Emoji:       "🪬",
+	Description: "Raises the holder's Special Defense by 10%.",
+	Category:    ItemCategoryHeld,
+	Price:       3000,
+	StatBoosts:  map[string]float64{"sdef": 1.1},
+}
+
+var SwiftFeather = Item{
+	ID:          64,
+	Name:        "Swift Feather",
+	Emoji:       "🪶",
+	Description: "Raises the holder's Speed by 10%.",
+	Category:    ItemCategoryHeld,
+	Price:       3000,
+	StatBoosts:  map[string]float64{"spd": 1.1},
+}
+
+var StudyGrimoire = Item{
+	ID:           65,
+	Name:         "Study Grimoire",
+	Emoji:        "📖",
+	Description:  "The holder gains 50% more XP.",
+	Category:     ItemCategoryHeld,
+	Price:        4000,
+	XPMultiplier: 1.5,
+}
+
+// Evolution items
+var AwakeningCrystal = Item{
+	ID:          80,
+	Name:        "Awakening Crystal",
+	Emoji:       "💎",
+	Description: "Awakens a character that has an awakened form. Use it with /evolve.",
+	Category:    ItemCategoryEvolution,
+	Price:       15000,
+}
+
+// InventoryItem is a stack of one item in a user's inventory.
+type InventoryItem struct {
+	ItemID   int
+	Quantity int
+}
+
+// Item returns the definition of the item.
+func (i InventoryItem) Item() Item {
+	return ItemsRegistry[i.ItemID]
+}
+
+// ItemUse is the outcome of using an item on a character.
+type ItemUse struct {
+	LevelUp      LevelUp // What the character got from the levels gained
+	ReturnedItem int     // The item the character held before, -1 if none
+}
+
+// UseItem applies an item to a character. Mints change the personality to the
+// given one and held items are given to the character, the item it held
+// before is returned to the caller.
+func UseItem(item Item, c *Character, personality constants.Personality) (ItemUse, error) {
+	use := ItemUse{ReturnedItem: -1}
+
+	switch item.Category {
+	case ItemCategoryConsumable:
+		switch {
+		case item.XP > 0, item.Levels > 0:
+			if c.Level >= MaxLevel {
+				return use, fmt.Errorf("%s is already at the maximum level", c.CharacterName())
+			}
+			xp := item.XP
+			if item.Levels > 0 {
+				// Each level the item gives fills the XP bar left after its XP
+				after := *c
+				after.AddXP(item.XP)
+				xp += after.XPToLevel(after.Level + item.Levels)
+			}
+			use.LevelUp = c.GainXP(xp)
+		case item.RerollIVs:
+			c.RerollIVs()
+		default:
+			return use, fmt.Errorf("%s can't be used on a character", item.Name)
+		}
+	case ItemCategoryMint:
+		if c.Personality == personality {
+			return use, fmt.Errorf("%s already has the %s personality", c.CharacterName(), personality)
+		}
+		c.Personality = personality
+	case ItemCategoryHeld:
+		if c.HeldItem == item.ID {
+			return use, fmt.Errorf("%s is already holding a %s", c.CharacterName(), item.Name)
+		}
+		use.ReturnedItem = c.HeldItem
+		c.HeldItem = item.ID
+	case ItemCategoryEvolution:
+		return use, fmt.Errorf("evolve %s with /evolve to use the %s", c.CharacterName(), item.Name)
+	default:
+		return use, fmt.Errorf("%s can't be used on a character", item.Name)
+	}
+
+	return use, nil
+}
+
+// FindItem returns the item with the given name, ignoring case.
+func FindItem(name string) (Item, bool) {
+	for _, item := range ItemsRegistry {
+		if strings.EqualFold(item.Name, name) {
+			return item, true
+		}
+	}
+	return Item{}, false
+}
+
+// ShopItems returns the items the shop sells, ordered by ID.
+func ShopItems() []Item {
+	items := make([]Item, 0, len(ItemsRegistry))
+	for _, item := range ItemsRegistry {
+		if item.Price > 0 {
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+	return items
+}
+
+// heldItemMultiplier returns the multiplier a held item applies to a stat.
+func heldItemMultiplier(heldItem int, stat string) float64 {
+	item, ok := ItemsRegistry[heldItem]
+	if !ok {
+		return 1.0
+	}
+
+	if multiplier, ok := item.StatBoosts[stat]; ok {
+		return multiplier
+	}
+	return 1.0
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+type LeaderboardCategory string
+
+const (
+	LeaderboardRating      LeaderboardCategory = "elo"
+	LeaderboardCharacters  LeaderboardCategory = "characters"
+	LeaderboardShinies     LeaderboardCategory = "shinies"
+	LeaderboardBestIV      LeaderboardCategory = "best_iv"
+	LeaderboardTotalLevels LeaderboardCategory = "levels"
+	LeaderboardBattlesWon  LeaderboardCategory = "battles_won"
+)
+
+// LeaderboardCategories lists every category in the order they are offered.
+var LeaderboardCategories = []LeaderboardCategory{
+	LeaderboardRating,
+	LeaderboardCharacters,
+	LeaderboardShinies,
+	LeaderboardBestIV,
+	LeaderboardTotalLevels,
+	LeaderboardBattlesWon,
+}
+
+func (c LeaderboardCategory) String() string {
+	switch c {
+	case LeaderboardRating:
+		return "Rating"
+	case LeaderboardCharacters:
+		return "Total Characters"
+	case LeaderboardShinies:
+		return "Shinies Caught"
+	case LeaderboardBestIV:
+		return "Highest IV Character"
+	case LeaderboardTotalLevels:
+		return "Total Levels"
+	case LeaderboardBattlesWon:
+		return "Battles Won"
+	default:
+		return string(c)
+	}
+}
+
+// FormatScore renders a leaderboard score in the category's unit.
+func (c LeaderboardCategory) FormatScore(score float64) string {
+	switch c {
+	case LeaderboardBestIV:
+		return fmt.Sprintf("%.2f%%", score/MaxIvTotal*100)
+	default:
+		return fmt.Sprintf("%d", int(score))
+	}
+}
+
+// LeaderboardEntry is a ranked player on a leaderboard.
+type LeaderboardEntry struct {
+	Rank   int
+	UserID snowflake.ID
+	Score  float64
+}
+
+// LeaderboardStats are the figures a player is ranked by.
+type LeaderboardStats struct {
+	UserID      snowflake.ID
+	Rating      Rating
+	Ranked      bool // Whether the player has played a ranked battle
+	Characters  int
+	Shinies     int
+	BestIV      float64
+	TotalLevels int
+	BattlesWon  int
+}
+
+// Scores returns the player's score in every category they appear in. Players
+// only appear on the rating leaderboard once they have played ranked, and on
+// the others once they have a non-zero score.
+func (s LeaderboardStats) Scores(rs RatingSystem) map[LeaderboardCategory]float64 {
+	scores := make(map[LeaderboardCategory]float64, len(LeaderboardCategories))
+
+	if s.Ranked {
+		scores[LeaderboardRating] = float64(rs.Conservative(s.Rating))
+	}
+
+	counts := map[LeaderboardCategory]float64{
+		LeaderboardCharacters:  float64(s.Characters),
+		LeaderboardShinies:     float64(s.Shinies),
+		LeaderboardBestIV:      s.BestIV,
+		LeaderboardTotalLevels: float64(s.TotalLevels),
+		LeaderboardBattlesWon:  float64(s.BattlesWon),
+	}
+	for category, score := range counts {
+		if score > 0 {
+			scores[category] = score
+		}
+	}
+
+	return scores
+}
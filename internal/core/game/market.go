@@ -0,0 +1,77 @@
+package game
+
+import (
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	MarketFeePercent      = 5                  // Share of the price kept by the market on a sale
+	MarketListingDuration = 7 * 24 * time.Hour // How long a listing stays up
+	MaxMarketPrice        = 10_000_000
+	MaxMarketListings     = 50 // Most listings a seller can have at once
+)
+
+// MarketListing is a character put up for sale on the global market. The
+// character stays with the seller, locked, until it is bought or unlisted.
+type MarketListing struct {
+	ID        int
+	SellerID  snowflake.ID
+	Character Character
+	Price     int
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// MarketFee is the part of a sale price that goes to the market.
+func MarketFee(price int) int {
+	return price * MarketFeePercent / 100
+}
+
+// SellerProceeds is what the seller receives when a listing sells.
+func (l MarketListing) SellerProceeds() int {
+	return l.Price - MarketFee(l.Price)
+}
+
+// MarketOrder is how market listings are sorted.
+type MarketOrder int
+
+const (
+	MarketOrderPriceAsc MarketOrder = iota
+	MarketOrderPriceDesc
+	MarketOrderNewest
+	MarketOrderIV
+	MarketOrderLevel
+)
+
+var marketOrderNames = map[MarketOrder]string{
+	MarketOrderPriceAsc:  "price",
+	MarketOrderPriceDesc: "price_desc",
+	MarketOrderNewest:    "newest",
+	MarketOrderIV:        "iv",
+	MarketOrderLevel:     "level",
+}
+
+func (o MarketOrder) String() string {
+	return marketOrderNames[o]
+}
+
+// ParseMarketOrder returns the order with the given name.
+func ParseMarketOrder(name string) (MarketOrder, bool) {
+	for order, orderName := range marketOrderNames {
+		if orderName == name {
+			return order, true
+		}
+	}
+	return MarketOrderPriceAsc, false
+}
+
+// MarketFilter narrows down market listings. Zero values match everything.
+type MarketFilter struct {
+	CharacterFilter
+	SellerID snowflake.ID
+	MinPrice int
+	MaxPrice int
+	Order    MarketOrder
+}
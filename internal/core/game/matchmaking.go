@@ -0,0 +1,94 @@
+package game
+
+import (
+	"sort"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	MatchWindowBase     = 100              // Rating difference allowed as soon as a player queues
+	MatchWindowStep     = 50               // Extra rating difference allowed per MatchWindowInterval waited
+	MatchWindowInterval = 30 * time.Second // How often the window widens
+	MatchWindowMax      = 600              // Widest the window gets
+)
+
+// QueueEntry is a player waiting in the ranked queue.
+type QueueEntry struct {
+	PlayerID snowflake.ID `json:"player_id"`
+	Rating   int          `json:"rating"`
+	JoinedAt time.Time    `json:"joined_at"`
+}
+
+// Match pairs two queued players.
+type Match struct {
+	Player1 QueueEntry `json:"player1"`
+	Player2 QueueEntry `json:"player2"`
+}
+
+// MatchWindow returns the rating difference a player accepts after waiting.
+func MatchWindow(waited time.Duration) int {
+	if waited < 0 {
+		waited = 0
+	}
+
+	window := MatchWindowBase + int(waited/MatchWindowInterval)*MatchWindowStep
+	if window > MatchWindowMax {
+		window = MatchWindowMax
+	}
+	return window
+}
+
+// FindMatches pairs queued players, longest waiting first. Each player is
+// matched with the closest rated opponent inside the wider of the two windows.
+func FindMatches(entries []QueueEntry, now time.Time) []Match {
+	queue := make([]QueueEntry, len(entries))
+	copy(queue, entries)
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].JoinedAt.Before(queue[j].JoinedAt)
+	})
+
+	matched := make([]bool, len(queue))
+	matches := make([]Match, 0, len(queue)/2)
+
+	for i, player := range queue {
+		if matched[i] {
+			continue
+		}
+
+		best, bestDiff := -1, 0
+		for j := i + 1; j < len(queue); j++ {
+			if matched[j] {
+				continue
+			}
+
+			opponent := queue[j]
+			diff := abs(player.Rating - opponent.Rating)
+			window := max(MatchWindow(now.Sub(player.JoinedAt)), MatchWindow(now.Sub(opponent.JoinedAt)))
+			if diff > window {
+				continue
+			}
+
+			if best == -1 || diff < bestDiff {
+				best, bestDiff = j, diff
+			}
+		}
+
+		if best == -1 {
+			continue
+		}
+
+		matched[i], matched[best] = true, true
+		matches = append(matches, Match{Player1: player, Player2: queue[best]})
+	}
+
+	return matches
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
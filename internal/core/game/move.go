@@ -2,12 +2,23 @@ package game
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/theoreotm/friemon/constants"
 )
 
 type StatChanges map[string]int
 
+// Stats returns the modified stats in a stable order.
+func (s StatChanges) Stats() []string {
+	stats := make([]string, 0, len(s))
+	for stat := range s {
+		stats = append(stats, stat)
+	}
+	sort.Strings(stats)
+	return stats
+}
+
 type TargetType int
 type MoveCategory int
 
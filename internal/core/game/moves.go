@@ -542,3 +542,12 @@ func GetMovesByCategory(category MoveCategory) []Move {
 	}
 	return moves
 }
+
+// Helper function to build a starting moveset for characters that don't know
+// any moves yet. Starts from Tackle and learns every move of the character's
+// types up to its level, as if it had levelled up from nothing.
+func DefaultMoveset(bc BaseCharacter, level int) []int32 {
+	c := Character{CharacterID: bc.ID, Moves: []int32{int32(Tackle.ID)}}
+	c.LearnMoves(0, level)
+	return c.Moves
+}
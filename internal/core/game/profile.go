@@ -0,0 +1,31 @@
+package game
+
+import "fmt"
+
+// CollectionStats summarises a player's characters.
+type CollectionStats struct {
+	Characters  int
+	Favourites  int
+	Shinies     int
+	TotalLevels int
+}
+
+// Badge is an achievement shown on a player's profile.
+type Badge struct {
+	Emoji string
+	Name  string
+}
+
+func (b Badge) String() string {
+	return fmt.Sprintf("%s %s", b.Emoji, b.Name)
+}
+
+// SeasonBadge is awarded for finishing a season in a bracket whose reward includes a badge.
+func SeasonBadge(seasonNumber int, bracket Bracket) Badge {
+	return Badge{Emoji: bracket.Emoji(), Name: fmt.Sprintf("Season %d %s", seasonNumber, bracket)}
+}
+
+// TournamentBadge is awarded for winning a tournament.
+func TournamentBadge(tournamentName string) Badge {
+	return Badge{Emoji: "🏆", Name: fmt.Sprintf("%s Champion", tournamentName)}
+}
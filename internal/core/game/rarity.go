@@ -0,0 +1,212 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/theoreotm/friemon/constants"
+)
+
+type Rarity int
+
+const (
+	RarityCommon Rarity = iota
+	RarityUncommon
+	RarityRare
+	RarityLegendary
+	RarityMythical
+)
+
+// Rarities lists every rarity tier from most to least common.
+var Rarities = []Rarity{RarityCommon, RarityUncommon, RarityRare, RarityLegendary, RarityMythical}
+
+func (r Rarity) String() string {
+	switch r {
+	case RarityCommon:
+		return "Common"
+	case RarityUncommon:
+		return "Uncommon"
+	case RarityRare:
+		return "Rare"
+	case RarityLegendary:
+		return "Legendary"
+	case RarityMythical:
+		return "Mythical"
+	default:
+		return "Unknown"
+	}
+}
+
+// Color returns the embed colour of the tier.
+func (r Rarity) Color() int {
+	switch r {
+	case RarityUncommon:
+		return constants.ColorUncommon
+	case RarityRare:
+		return constants.ColorRare
+	case RarityLegendary:
+		return constants.ColorLegendary
+	case RarityMythical:
+		return constants.ColorMythical
+	default:
+		return constants.ColorCommon
+	}
+}
+
+// ParseRarity returns the rarity with the given name, ignoring case.
+func ParseRarity(name string) (Rarity, bool) {
+	for _, r := range Rarities {
+		if strings.EqualFold(r.String(), name) {
+			return r, true
+		}
+	}
+	return RarityCommon, false
+}
+
+// DefaultRarityWeights are the relative chances of a spawn being of each tier.
+var DefaultRarityWeights = map[Rarity]int{
+	RarityCommon:    600,
+	RarityUncommon:  270,
+	RarityRare:      100,
+	RarityLegendary: 25,
+	RarityMythical:  5,
+}
+
+// rarityWeights are the weights in use, a tier's weight is shared evenly
+// between its characters.
+var rarityWeights = DefaultRarityWeights
+
+// SetRarityWeights replaces the spawn weights of the tiers. Tiers left out
+// keep their default weight.
+func SetRarityWeights(weights map[Rarity]int) {
+	merged := make(map[Rarity]int, len(DefaultRarityWeights))
+	for r, w := range DefaultRarityWeights {
+		merged[r] = w
+	}
+	for r, w := range weights {
+		merged[r] = w
+	}
+	rarityWeights = merged
+}
+
+// ParseRarityWeights parses weights written as "common=600,rare=100".
+func ParseRarityWeights(s string) (map[Rarity]int, error) {
+	weights := map[Rarity]int{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rarity weight %q, expected tier=weight", part)
+		}
+		rarity, ok := ParseRarity(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown rarity %q", name)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight for %s: %q", rarity, value)
+		}
+
+		weights[rarity] = weight
+	}
+
+	return weights, nil
+}
+
+// charactersByRarity groups the characters that spawn wild by tier, ordered
+// by ID.
+func charactersByRarity() map[Rarity][]BaseCharacter {
+	tiers := map[Rarity][]BaseCharacter{}
+	for _, character := range Characters {
+		if character.IsForm() {
+			continue
+		}
+		tiers[character.Rarity] = append(tiers[character.Rarity], character)
+	}
+	for _, characters := range tiers {
+		sort.Slice(characters, func(i, j int) bool {
+			return characters[i].ID < characters[j].ID
+		})
+	}
+	return tiers
+}
+
+// SpawnChances returns the chance of each character spawning, keyed by ID.
+// Tiers without characters are left out, so the chances always add up to one.
+func SpawnChances() map[int]float64 {
+	tiers := charactersByRarity()
+
+	total := 0
+	for rarity := range tiers {
+		total += rarityWeights[rarity]
+	}
+
+	chances := make(map[int]float64, len(Characters))
+	if total == 0 {
+		return chances
+	}
+	for rarity, characters := range tiers {
+		for _, character := range characters {
+			chances[character.ID] = float64(rarityWeights[rarity]) / float64(total) / float64(len(characters))
+		}
+	}
+	return chances
+}
+
+// RandomBaseCharacter picks a character to spawn, first a tier by weight and
+// then one of the tier's characters evenly.
+func RandomBaseCharacter() BaseCharacter {
+	return RandomBaseCharacterWith(DefaultSpawnModifiers())
+}
+
+// RandomBaseCharacterWith picks a character to spawn like RandomBaseCharacter,
+// with each character's share scaled by the modifiers' boosts.
+func RandomBaseCharacterWith(modifiers SpawnModifiers) BaseCharacter {
+	tiers := charactersByRarity()
+
+	var (
+		candidates []BaseCharacter
+		weights    []float64
+		total      float64
+	)
+	for _, rarity := range Rarities {
+		characters := tiers[rarity]
+		for _, character := range characters {
+			weight := float64(rarityWeights[rarity]) / float64(len(characters)) * modifiers.weight(character)
+			candidates = append(candidates, character)
+			weights = append(weights, weight)
+			total += weight
+		}
+	}
+
+	if total > 0 {
+		roll := rand.Float64() * total
+		last := 0
+		for i, character := range candidates {
+			if weights[i] <= 0 {
+				continue
+			}
+			if roll < weights[i] {
+				return character
+			}
+			roll -= weights[i]
+			last = i
+		}
+
+		// Rounding left the roll past the last weight
+		return candidates[last]
+	}
+
+	// Every tier has no weight, fall back to an even pick
+	if len(candidates) > 0 {
+		return candidates[rand.Intn(len(candidates))]
+	}
+	return BaseCharacter{}
+}
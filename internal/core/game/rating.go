@@ -0,0 +1,200 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	DefaultDeviation  = 350.0 // Rating deviation of a new player
+	DefaultVolatility = 0.06  // Glicko-2 volatility of a new player
+
+	glicko2Scale   = 173.7178
+	glicko2Tau     = 0.5 // Constrains how quickly volatility changes
+	glicko2Epsilon = 0.000001
+)
+
+// Rating is a player's skill estimate. Deviation and Volatility are only used
+// by rating systems that track uncertainty.
+type Rating struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+}
+
+// Conservative returns the rating minus two deviations, a lower bound the
+// player is very likely above. Used for matchmaking and leaderboards.
+func (r Rating) Conservative() int {
+	return int(math.Round(r.Rating - 2*r.Deviation))
+}
+
+// Value returns the rating rounded to a whole number.
+func (r Rating) Value() int {
+	return int(math.Round(r.Rating))
+}
+
+// RatingSystem updates ratings after battles.
+type RatingSystem interface {
+	Name() string
+	// Update returns the player's new rating after a battle against opponent.
+	// score is 1 for a win, 0.5 for a draw and 0 for a loss.
+	Update(player, opponent Rating, score float64) Rating
+	// Decay returns the rating after the given number of rating periods without battles.
+	Decay(r Rating, periods int) Rating
+	// Conservative returns the rating used for matchmaking and leaderboards.
+	Conservative(r Rating) int
+}
+
+// NewRatingSystem returns the rating system with the given name.
+func NewRatingSystem(name string, kFactor int) (RatingSystem, error) {
+	switch strings.ToLower(name) {
+	case "", "elo":
+		return ELOSystem{KFactor: kFactor}, nil
+	case "glicko2", "glicko-2":
+		return Glicko2System{}, nil
+	default:
+		return nil, fmt.Errorf("unknown rating system: %s", name)
+	}
+}
+
+// ELOSystem is the classic fixed K-factor ELO rating. It has no deviation, so
+// its conservative rating is the rating itself.
+type ELOSystem struct {
+	KFactor int
+}
+
+func (ELOSystem) Name() string {
+	return "ELO"
+}
+
+func (s ELOSystem) Update(player, opponent Rating, score float64) Rating {
+	newRating, _ := CalculateELO(player.Value(), opponent.Value(), s.KFactor, score)
+	return Rating{Rating: float64(newRating), Deviation: player.Deviation, Volatility: player.Volatility}
+}
+
+func (ELOSystem) Decay(r Rating, _ int) Rating {
+	return r
+}
+
+func (ELOSystem) Conservative(r Rating) int {
+	return r.Value()
+}
+
+// Glicko2System implements Glicko-2, treating every battle as its own rating period.
+type Glicko2System struct{}
+
+func (Glicko2System) Name() string {
+	return "Glicko-2"
+}
+
+func (Glicko2System) Update(player, opponent Rating, score float64) Rating {
+	return glicko2Update(player, glicko2Result{Opponent: opponent, Score: score})
+}
+
+// glicko2Result is one game of a rating period.
+type glicko2Result struct {
+	Opponent Rating
+	Score    float64
+}
+
+// glicko2Update rates a player over a rating period with the given games.
+func glicko2Update(player Rating, results ...glicko2Result) Rating {
+	player = withDefaults(player)
+	mu, phi := toGlicko2(player)
+
+	var invVariance, improvement float64
+	for _, result := range results {
+		muJ, phiJ := toGlicko2(withDefaults(result.Opponent))
+		g := glicko2G(phiJ)
+		expected := 1 / (1 + math.Exp(-g*(mu-muJ)))
+		invVariance += g * g * expected * (1 - expected)
+		improvement += g * (result.Score - expected)
+	}
+	variance := 1 / invVariance
+	delta := variance * improvement
+
+	sigma := glicko2Volatility(phi, player.Volatility, variance, delta)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/variance)
+	newMu := mu + newPhi*newPhi*improvement
+
+	return Rating{
+		Rating:     newMu*glicko2Scale + DefaultRating,
+		Deviation:  math.Min(newPhi*glicko2Scale, DefaultDeviation),
+		Volatility: sigma,
+	}
+}
+
+// Decay widens the deviation of a player who has not battled, as Glicko-2 does
+// for rating periods without games.
+func (Glicko2System) Decay(r Rating, periods int) Rating {
+	r = withDefaults(r)
+
+	_, phi := toGlicko2(r)
+	for i := 0; i < periods; i++ {
+		phi = math.Sqrt(phi*phi + r.Volatility*r.Volatility)
+	}
+
+	r.Deviation = math.Min(phi*glicko2Scale, DefaultDeviation)
+	return r
+}
+
+func (Glicko2System) Conservative(r Rating) int {
+	return withDefaults(r).Conservative()
+}
+
+func withDefaults(r Rating) Rating {
+	if r.Deviation <= 0 {
+		r.Deviation = DefaultDeviation
+	}
+	if r.Volatility <= 0 {
+		r.Volatility = DefaultVolatility
+	}
+	return r
+}
+
+func toGlicko2(r Rating) (mu, phi float64) {
+	return (r.Rating - DefaultRating) / glicko2Scale, r.Deviation / glicko2Scale
+}
+
+func glicko2G(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// glicko2Volatility finds the new volatility with the Illinois algorithm.
+func glicko2Volatility(phi, sigma, variance, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + variance + ex
+		return ex*(delta*delta-phi*phi-variance-ex)/(2*d*d) - (x-a)/(glicko2Tau*glicko2Tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+variance {
+		B = math.Log(delta*delta - phi*phi - variance)
+	} else {
+		k := 1.0
+		for f(a-k*glicko2Tau) < 0 {
+			k++
+		}
+		B = a - k*glicko2Tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glicko2Epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
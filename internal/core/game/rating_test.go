@@ -0,0 +1,150 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+// paperRating converts a rating from Glickman's paper, which centres ratings on
+// 1500, to this package's scale.
+func paperRating(r float64) float64 {
+	return r - 1500 + DefaultRating
+}
+
+func TestGlicko2UpdatePaperExample(t *testing.T) {
+	// The worked example from "Example of the Glicko-2 system", Glickman (2013)
+	player := Rating{Rating: paperRating(1500), Deviation: 200, Volatility: 0.06}
+	got := glicko2Update(player,
+		glicko2Result{Opponent: Rating{Rating: paperRating(1400), Deviation: 30}, Score: 1},
+		glicko2Result{Opponent: Rating{Rating: paperRating(1550), Deviation: 100}, Score: 0},
+		glicko2Result{Opponent: Rating{Rating: paperRating(1700), Deviation: 300}, Score: 0},
+	)
+
+	if want := paperRating(1464.06); math.Abs(got.Rating-want) > 0.01 {
+		t.Errorf("rating = %.2f, want %.2f", got.Rating, want)
+	}
+	if want := 151.52; math.Abs(got.Deviation-want) > 0.01 {
+		t.Errorf("deviation = %.2f, want %.2f", got.Deviation, want)
+	}
+	if want := 0.05999; math.Abs(got.Volatility-want) > 0.00001 {
+		t.Errorf("volatility = %.5f, want %.5f", got.Volatility, want)
+	}
+}
+
+func TestGlicko2Update(t *testing.T) {
+	player := Rating{Rating: DefaultRating, Deviation: 200, Volatility: DefaultVolatility}
+	opponent := Rating{Rating: DefaultRating, Deviation: 200, Volatility: DefaultVolatility}
+	system := Glicko2System{}
+
+	win := system.Update(player, opponent, 1)
+	if want := glicko2Update(player, glicko2Result{Opponent: opponent, Score: 1}); win != want {
+		t.Errorf("Update = %+v, want %+v", win, want)
+	}
+	if win.Rating <= player.Rating {
+		t.Errorf("rating after a win = %.2f, want above %.2f", win.Rating, player.Rating)
+	}
+	if win.Deviation >= player.Deviation {
+		t.Errorf("deviation after a battle = %.2f, want below %.2f", win.Deviation, player.Deviation)
+	}
+
+	loss := system.Update(player, opponent, 0)
+	if math.Abs((win.Rating-player.Rating)+(loss.Rating-player.Rating)) > 0.01 {
+		t.Errorf("win and loss against an equal opponent are not symmetric: %.2f, %.2f", win.Rating, loss.Rating)
+	}
+
+	// New players start from the default deviation and volatility
+	if got, want := system.Update(Rating{Rating: DefaultRating}, opponent, 1), system.Update(Rating{Rating: DefaultRating, Deviation: DefaultDeviation, Volatility: DefaultVolatility}, opponent, 1); got != want {
+		t.Errorf("Update without deviation = %+v, want %+v", got, want)
+	}
+}
+
+func TestGlicko2Decay(t *testing.T) {
+	tests := []struct {
+		name      string
+		rating    Rating
+		periods   int
+		deviation float64
+	}{
+		{"no periods", Rating{Rating: 1200, Deviation: 50, Volatility: 0.06}, 0, 50},
+		{"one period", Rating{Rating: 1200, Deviation: 50, Volatility: 0.06}, 1, 51.07},
+		{"ten periods", Rating{Rating: 1200, Deviation: 50, Volatility: 0.06}, 10, 59.89},
+		{"capped at default", Rating{Rating: 1200, Deviation: 349.9, Volatility: 0.06}, 100, DefaultDeviation},
+		{"new player", Rating{Rating: 1200}, 1, DefaultDeviation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Glicko2System{}.Decay(tt.rating, tt.periods)
+			if math.Abs(got.Deviation-tt.deviation) > 0.01 {
+				t.Errorf("deviation = %.2f, want %.2f", got.Deviation, tt.deviation)
+			}
+			if got.Rating != tt.rating.Rating {
+				t.Errorf("rating = %.2f, want %.2f", got.Rating, tt.rating.Rating)
+			}
+		})
+	}
+}
+
+func TestELOSystem(t *testing.T) {
+	system := ELOSystem{KFactor: 32}
+
+	tests := []struct {
+		name     string
+		player   float64
+		opponent float64
+		score    float64
+		want     float64
+	}{
+		{"win against equal", 1000, 1000, 1, 1016},
+		{"loss against equal", 1000, 1000, 0, 984},
+		{"draw against weaker", 1200, 1000, 0.5, 1192},
+		{"win against stronger", 1000, 1200, 1, 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := Rating{Rating: tt.player, Deviation: 80, Volatility: 0.05}
+			got := system.Update(player, Rating{Rating: tt.opponent}, tt.score)
+			if got.Rating != tt.want {
+				t.Errorf("rating = %.0f, want %.0f", got.Rating, tt.want)
+			}
+			if got.Deviation != player.Deviation || got.Volatility != player.Volatility {
+				t.Errorf("Update changed deviation or volatility: %+v", got)
+			}
+		})
+	}
+
+	// ELO has no uncertainty, so decay and the conservative rating leave it as is
+	r := Rating{Rating: 1234.4, Deviation: 200}
+	if got := system.Decay(r, 5); got != r {
+		t.Errorf("Decay = %+v, want %+v", got, r)
+	}
+	if got := system.Conservative(r); got != 1234 {
+		t.Errorf("Conservative = %d, want 1234", got)
+	}
+}
+
+func TestNewRatingSystem(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", "ELO", false},
+		{"elo", "ELO", false},
+		{"Glicko2", "Glicko-2", false},
+		{"glicko-2", "Glicko-2", false},
+		{"trueskill", "", true},
+	}
+
+	for _, tt := range tests {
+		system, err := NewRatingSystem(tt.name, 32)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewRatingSystem(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && system.Name() != tt.want {
+			t.Errorf("NewRatingSystem(%q) = %s, want %s", tt.name, system.Name(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"github.com/theoreotm/friemon/constants"
+)
+
+// CharacterSnapshot holds everything about a character that affects a battle.
+type CharacterSnapshot struct {
+	ID          uuid.UUID             `json:"id"`
+	CharacterID int                   `json:"character_id"`
+	Level       int                   `json:"level"`
+	Personality constants.Personality `json:"personality"`
+	Shiny       bool                  `json:"shiny"`
+	Nickname    string                `json:"nickname,omitempty"`
+	IvHP        int                   `json:"iv_hp"`
+	IvAtk       int                   `json:"iv_atk"`
+	IvDef       int                   `json:"iv_def"`
+	IvSpAtk     int                   `json:"iv_sp_atk"`
+	IvSpDef     int                   `json:"iv_sp_def"`
+	IvSpd       int                   `json:"iv_spd"`
+	HeldItem    int                   `json:"held_item,omitempty"`
+	Moves       []int32               `json:"moves"`
+}
+
+func NewCharacterSnapshot(c *Character) CharacterSnapshot {
+	return CharacterSnapshot{
+		ID:          c.ID,
+		CharacterID: c.CharacterID,
+		Level:       c.Level,
+		Personality: c.Personality,
+		Shiny:       c.Shiny,
+		Nickname:    c.Nickname,
+		IvHP:        c.IvHP,
+		IvAtk:       c.IvAtk,
+		IvDef:       c.IvDef,
+		IvSpAtk:     c.IvSpAtk,
+		IvSpDef:     c.IvSpDef,
+		IvSpd:       c.IvSpd,
+		HeldItem:    c.HeldItem,
+		Moves:       append([]int32(nil), c.Moves...),
+	}
+}
+
+// Character rebuilds a battle ready character from the snapshot.
+func (s CharacterSnapshot) Character() *Character {
+	return &Character{
+		ID:          s.ID,
+		CharacterID: s.CharacterID,
+		Level:       s.Level,
+		Personality: s.Personality,
+		Shiny:       s.Shiny,
+		Nickname:    s.Nickname,
+		IvHP:        s.IvHP,
+		IvAtk:       s.IvAtk,
+		IvDef:       s.IvDef,
+		IvSpAtk:     s.IvSpAtk,
+		IvSpDef:     s.IvSpDef,
+		IvSpd:       s.IvSpd,
+		IvTotal:     float64(s.IvHP + s.IvAtk + s.IvDef + s.IvSpAtk + s.IvSpDef + s.IvSpd),
+		HeldItem:    s.HeldItem,
+		Moves:       append([]int32(nil), s.Moves...),
+		IsInBattle:  true,
+	}
+}
+
+func snapshotTeam(team []*Character) []CharacterSnapshot {
+	snapshots := make([]CharacterSnapshot, 0, len(team))
+	for _, char := range team {
+		snapshots = append(snapshots, NewCharacterSnapshot(char))
+	}
+	return snapshots
+}
+
+type ReplayPlayer struct {
+	ID   snowflake.ID        `json:"id"`
+	Team []CharacterSnapshot `json:"team"`
+}
+
+// BattleReplay is everything needed to re-simulate a finished battle turn by turn.
+type BattleReplay struct {
+	BattleID   uuid.UUID        `json:"battle_id"`
+	Seed       int64            `json:"seed"`
+	Settings   GameSettings     `json:"settings"`
+	Player1    ReplayPlayer     `json:"player1"`
+	Player2    ReplayPlayer     `json:"player2"`
+	Turns      [][]PlayerAction `json:"turns"`
+	Winner     *snowflake.ID    `json:"winner,omitempty"`
+	StartedAt  time.Time        `json:"started_at"`
+	FinishedAt time.Time        `json:"finished_at"`
+}
+
+// ShortID returns the 8 character ID shown in battle summaries.
+func (r *BattleReplay) ShortID() string {
+	return ShortBattleID(r.BattleID)
+}
+
+// ShortBattleID returns the 8 character prefix of a battle ID.
+func ShortBattleID(id uuid.UUID) string {
+	return id.String()[:8]
+}
+
+// Replay returns the replay record of the battle, or nil if it never started.
+func (b *Battle) Replay() *BattleReplay {
+	if b.startTeams[0] == nil || b.startTeams[1] == nil {
+		return nil
+	}
+
+	finishedAt := time.Now()
+	if b.FinishedAt != nil {
+		finishedAt = *b.FinishedAt
+	}
+
+	turns := make([][]PlayerAction, len(b.Turns))
+	for i, actions := range b.Turns {
+		turns[i] = append([]PlayerAction(nil), actions...)
+	}
+
+	return &BattleReplay{
+		BattleID:   b.ID,
+		Seed:       b.Seed,
+		Settings:   b.Settings,
+		Player1:    ReplayPlayer{ID: b.Player1.ID, Team: b.startTeams[0]},
+		Player2:    ReplayPlayer{ID: b.Player2.ID, Team: b.startTeams[1]},
+		Turns:      turns,
+		Winner:     b.Winner,
+		StartedAt:  b.StartedAt,
+		FinishedAt: finishedAt,
+	}
+}
+
+// Simulate re-runs a replay and returns the state of the battle at the start
+// and after every turn.
+func Simulate(replay *BattleReplay) ([]TurnSummary, error) {
+	battle := NewBattle(0, replay.Player1.ID, replay.Player2.ID)
+	battle.ID = replay.BattleID
+	battle.Seed = replay.Seed
+	battle.Settings = replay.Settings
+	battle.StartedAt = replay.StartedAt
+	battle.State = BattleStateTeamSelection
+
+	for _, snapshot := range replay.Player1.Team {
+		battle.Player1.Team = append(battle.Player1.Team, snapshot.Character())
+	}
+	for _, snapshot := range replay.Player2.Team {
+		battle.Player2.Team = append(battle.Player2.Team, snapshot.Character())
+	}
+
+	if err := battle.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start replay: %w", err)
+	}
+
+	frames := make([]TurnSummary, 0, len(replay.Turns)+1)
+	frames = append(frames, battle.TurnSummary())
+
+	for i, actions := range replay.Turns {
+		if battle.State != BattleStateInProgress {
+			return frames, fmt.Errorf("replay has %d turns but the battle ended after %d", len(replay.Turns), i)
+		}
+
+		battle.Player1.ActionsThisTurn = make([]PlayerAction, 0)
+		battle.Player2.ActionsThisTurn = make([]PlayerAction, 0)
+		for _, action := range actions {
+			if player := battle.GetPlayer(action.PlayerID); player != nil {
+				player.ActionsThisTurn = append(player.ActionsThisTurn, action)
+			}
+		}
+
+		if err := battle.ProcessTurn(); err != nil {
+			return frames, fmt.Errorf("failed to simulate turn %d: %w", i+1, err)
+		}
+		frames = append(frames, battle.TurnSummary())
+	}
+
+	return frames, nil
+}
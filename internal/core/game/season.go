@@ -0,0 +1,181 @@
+package game
+
+import (
+	"sort"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	DefaultRating  = 1000
+	SeasonLength   = 28 * 24 * time.Hour
+	MinSeasonGames = 5 // Ranked battles needed in a season to earn rewards
+)
+
+type Season struct {
+	ID       int
+	Number   int
+	StartsAt time.Time
+	EndsAt   time.Time
+	Active   bool
+}
+
+// Remaining returns how long is left in the season.
+func (s Season) Remaining(now time.Time) time.Duration {
+	if now.After(s.EndsAt) {
+		return 0
+	}
+	return s.EndsAt.Sub(now)
+}
+
+// Next returns the season that follows this one.
+func (s Season) Next(now time.Time) Season {
+	return Season{
+		Number:   s.Number + 1,
+		StartsAt: now,
+		EndsAt:   now.Add(SeasonLength),
+		Active:   true,
+	}
+}
+
+type Bracket int
+
+const (
+	BracketBronze Bracket = iota
+	BracketSilver
+	BracketGold
+	BracketPlatinum
+	BracketDiamond
+)
+
+var bracketFloors = []struct {
+	bracket Bracket
+	rating  int
+}{
+	{BracketDiamond, 1600},
+	{BracketPlatinum, 1400},
+	{BracketGold, 1200},
+	{BracketSilver, 1000},
+}
+
+// BracketForRating returns the bracket a rating falls in.
+func BracketForRating(rating int) Bracket {
+	for _, floor := range bracketFloors {
+		if rating >= floor.rating {
+			return floor.bracket
+		}
+	}
+	return BracketBronze
+}
+
+func (b Bracket) String() string {
+	switch b {
+	case BracketSilver:
+		return "Silver"
+	case BracketGold:
+		return "Gold"
+	case BracketPlatinum:
+		return "Platinum"
+	case BracketDiamond:
+		return "Diamond"
+	default:
+		return "Bronze"
+	}
+}
+
+func (b Bracket) Emoji() string {
+	switch b {
+	case BracketSilver:
+		return "🥈"
+	case BracketGold:
+		return "🥇"
+	case BracketPlatinum:
+		return "💠"
+	case BracketDiamond:
+		return "💎"
+	default:
+		return "🥉"
+	}
+}
+
+// SeasonReward is what a player earns for finishing a season in a bracket.
+type SeasonReward struct {
+	Balance int  // Currency
+	Shiny   bool // An exclusive shiny character
+	Badge   bool // The season badge shown on the profile
+}
+
+func (r SeasonReward) IsZero() bool {
+	return r == SeasonReward{}
+}
+
+// Reward returns the end of season reward for the bracket.
+func (b Bracket) Reward() SeasonReward {
+	switch b {
+	case BracketDiamond:
+		return SeasonReward{Balance: 10000, Shiny: true, Badge: true}
+	case BracketPlatinum:
+		return SeasonReward{Balance: 5000, Badge: true}
+	case BracketGold:
+		return SeasonReward{Balance: 2500}
+	case BracketSilver:
+		return SeasonReward{Balance: 1000}
+	default:
+		return SeasonReward{}
+	}
+}
+
+type SeasonResult int
+
+const (
+	SeasonResultWin SeasonResult = iota
+	SeasonResultLoss
+	SeasonResultDraw
+)
+
+// SeasonStanding is a player's record in a season.
+type SeasonStanding struct {
+	SeasonID   int
+	UserID     snowflake.ID
+	Rating     int
+	PeakRating int
+	Wins       int
+	Losses     int
+	Draws      int
+	Rank       int // Final rank, set when the season is archived
+	Bracket    Bracket
+	Rewarded   bool
+}
+
+func (s SeasonStanding) Games() int {
+	return s.Wins + s.Losses + s.Draws
+}
+
+// Reward returns what the standing earns at the end of the season.
+func (s SeasonStanding) Reward() SeasonReward {
+	if s.Games() < MinSeasonGames {
+		return SeasonReward{}
+	}
+	return s.Bracket.Reward()
+}
+
+// RankStandings orders standings by rating and assigns final ranks and brackets.
+func RankStandings(standings []SeasonStanding) {
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Rating != standings[j].Rating {
+			return standings[i].Rating > standings[j].Rating
+		}
+		return standings[i].Wins > standings[j].Wins
+	})
+
+	for i := range standings {
+		standings[i].Rank = i + 1
+		standings[i].Bracket = BracketForRating(standings[i].Rating)
+	}
+}
+
+// SoftResetRating pulls a rating halfway back toward the default rating.
+func SoftResetRating(rating int) int {
+	return DefaultRating + (rating-DefaultRating)/2
+}
@@ -0,0 +1,72 @@
+package game
+
+import (
+	"math"
+	"unicode"
+)
+
+const (
+	DefaultMinMessageLength  = 3
+	DefaultMessageRateLimit  = 8   // Counted messages per user per minute
+	DefaultMaxUserSpawnShare = 0.5 // Share of a spawn's messages one user can send once others talk too
+	MaxMinMessageLength      = 50
+	MaxMessageRateLimit      = 60
+)
+
+// SpamReason is why a message didn't count toward spawns or XP.
+type SpamReason int
+
+const (
+	SpamTooShort SpamReason = iota
+	SpamDuplicate
+	SpamRateLimited
+	SpamSpawnShare
+)
+
+func (r SpamReason) String() string {
+	switch r {
+	case SpamTooShort:
+		return "Too short"
+	case SpamDuplicate:
+		return "Duplicate message"
+	case SpamRateLimited:
+		return "Rate limited"
+	case SpamSpawnShare:
+		return "Too many of a spawn's messages"
+	default:
+		return "Unknown"
+	}
+}
+
+// MessageLength counts the letters and digits in a message, so padding it
+// with spaces or punctuation doesn't make it count.
+func MessageLength(content string) int {
+	length := 0
+	for _, r := range content {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			length++
+		}
+	}
+	return length
+}
+
+// MessageFingerprint reduces a message to what makes it different from
+// another, so "Hello!!" and "hello" are the same message.
+func MessageFingerprint(content string) string {
+	runes := make([]rune, 0, len(content))
+	for _, r := range NormalizeName(content) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			runes = append(runes, r)
+		}
+	}
+	return string(runes)
+}
+
+// MaxUserSpawnMessages returns how many of the messages toward a spawn one
+// user can send. Zero means there is no limit.
+func (s GuildSettings) MaxUserSpawnMessages() int {
+	if s.MaxUserSpawnShare >= 1 {
+		return 0
+	}
+	return max(1, int(math.Ceil(s.MaxUserSpawnShare*float64(s.SpawnThresholdMax))))
+}
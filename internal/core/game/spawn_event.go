@@ -0,0 +1,158 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	MaxEventBoost        = 10.0
+	MaxEventDuration     = 14 * 24 * time.Hour
+	MinEventRecurrence   = time.Hour
+	MaxEventNameLength   = 50
+	MaxEventMinimumIV    = 31
+	MaxBoostedCharacters = 10
+	LureDuration         = time.Hour // How long a lure waits for its spawn
+)
+
+// SpawnEvent changes spawns and XP everywhere while it runs. A recurring event
+// moves forward by its recurrence once it ends.
+type SpawnEvent struct {
+	ID                int
+	Name              string
+	BoostedCharacters []int   // IDs of characters that spawn more often
+	BoostMultiplier   float64 // How many times as often boosted characters spawn
+	ShinyMultiplier   float64
+	MinIV             int     // Every IV of a spawn is at least this
+	XPMultiplier      float64 // How much more XP characters gain
+	StartsAt          time.Time
+	EndsAt            time.Time
+	RecurEvery        time.Duration // Zero for a one-off event
+}
+
+// Validate checks the event is within the allowed limits.
+func (e SpawnEvent) Validate() error {
+	if e.Name == "" || len([]rune(e.Name)) > MaxEventNameLength {
+		return fmt.Errorf("the event name must be between 1 and %d characters", MaxEventNameLength)
+	}
+	if len(e.BoostedCharacters) > MaxBoostedCharacters {
+		return fmt.Errorf("at most %d characters can be boosted", MaxBoostedCharacters)
+	}
+	if e.BoostMultiplier < 1 || e.BoostMultiplier > MaxEventBoost {
+		return fmt.Errorf("the boost must be between 1 and %g", MaxEventBoost)
+	}
+	if e.ShinyMultiplier < 1 || e.ShinyMultiplier > MaxShinyMultiplier {
+		return fmt.Errorf("the shiny multiplier must be between 1 and %g", MaxShinyMultiplier)
+	}
+	if e.XPMultiplier < 1 || e.XPMultiplier > MaxXPMultiplier {
+		return fmt.Errorf("the XP multiplier must be between 1 and %g", MaxXPMultiplier)
+	}
+	if e.MinIV < 0 || e.MinIV > MaxEventMinimumIV {
+		return fmt.Errorf("the minimum IV must be between 0 and %d", MaxEventMinimumIV)
+	}
+	if !e.EndsAt.After(e.StartsAt) || e.EndsAt.Sub(e.StartsAt) > MaxEventDuration {
+		return fmt.Errorf("events must last more than nothing and at most %s", MaxEventDuration)
+	}
+	if e.RecurEvery != 0 && (e.RecurEvery < MinEventRecurrence || e.RecurEvery < e.EndsAt.Sub(e.StartsAt)) {
+		return fmt.Errorf("recurring events must repeat at least every %s and not overlap themselves", MinEventRecurrence)
+	}
+	return nil
+}
+
+// Active reports whether the event is running at t.
+func (e SpawnEvent) Active(t time.Time) bool {
+	return !t.Before(e.StartsAt) && t.Before(e.EndsAt)
+}
+
+// NextOccurrence moves a recurring event forward until it has not ended by t.
+// It reports false for one-off events.
+func (e SpawnEvent) NextOccurrence(t time.Time) (SpawnEvent, bool) {
+	if e.RecurEvery <= 0 {
+		return e, false
+	}
+
+	for !e.EndsAt.After(t) {
+		e.StartsAt = e.StartsAt.Add(e.RecurEvery)
+		e.EndsAt = e.EndsAt.Add(e.RecurEvery)
+	}
+	return e, true
+}
+
+// SpawnModifiers change how a single spawn is rolled.
+type SpawnModifiers struct {
+	CharacterBoosts map[int]float64    // Weight multipliers by character ID
+	RarityBoosts    map[Rarity]float64 // Weight multipliers by tier
+	ShinyMultiplier float64
+	MinIV           int
+}
+
+// DefaultSpawnModifiers leave spawns unchanged.
+func DefaultSpawnModifiers() SpawnModifiers {
+	return SpawnModifiers{ShinyMultiplier: 1}
+}
+
+// Apply adds an event's effects to the modifiers. Boosts and shiny rates from
+// several events multiply, the highest minimum IV wins.
+func (m *SpawnModifiers) Apply(event SpawnEvent) {
+	if m.CharacterBoosts == nil {
+		m.CharacterBoosts = map[int]float64{}
+	}
+	for _, id := range event.BoostedCharacters {
+		if _, ok := m.CharacterBoosts[id]; !ok {
+			m.CharacterBoosts[id] = 1
+		}
+		m.CharacterBoosts[id] *= event.BoostMultiplier
+	}
+
+	m.ShinyMultiplier *= event.ShinyMultiplier
+	m.MinIV = max(m.MinIV, event.MinIV)
+}
+
+// ApplyLure adds a lure's boost to the modifiers.
+func (m *SpawnModifiers) ApplyLure(lure ChannelLure) {
+	item := lure.Item()
+	if item.LureBoost <= 1 {
+		return
+	}
+
+	if m.RarityBoosts == nil {
+		m.RarityBoosts = map[Rarity]float64{}
+	}
+	for _, rarity := range Rarities {
+		if rarity >= RarityRare {
+			m.RarityBoosts[rarity] = item.LureBoost
+		}
+	}
+}
+
+// ChannelLure is a lure placed in a channel. It forces the next spawn there
+// and boosts it, and is given back if nothing spawned within LureDuration.
+type ChannelLure struct {
+	ID     uuid.UUID
+	ItemID int
+	UserID snowflake.ID
+}
+
+func (l ChannelLure) Item() Item {
+	return ItemsRegistry[l.ItemID]
+}
+
+// ForcesSpawn reports whether a lure spawns a character after count messages.
+func (l ChannelLure) ForcesSpawn(count int) bool {
+	return count+1 >= l.Item().LureMessages
+}
+
+// weight returns how much more likely a character is to spawn.
+func (m SpawnModifiers) weight(character BaseCharacter) float64 {
+	weight := 1.0
+	if boost, ok := m.CharacterBoosts[character.ID]; ok {
+		weight *= boost
+	}
+	if boost, ok := m.RarityBoosts[character.Rarity]; ok {
+		weight *= boost
+	}
+	return weight
+}
@@ -0,0 +1,574 @@
+package game
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	MinTournamentPlayers = 2
+	MaxTournamentPlayers = 64
+)
+
+type TournamentFormat int
+
+const (
+	TournamentSingleElimination TournamentFormat = iota
+	TournamentDoubleElimination
+	TournamentSwiss
+)
+
+func (f TournamentFormat) String() string {
+	switch f {
+	case TournamentSingleElimination:
+		return "Single Elimination"
+	case TournamentDoubleElimination:
+		return "Double Elimination"
+	case TournamentSwiss:
+		return "Swiss"
+	default:
+		return "Unknown"
+	}
+}
+
+// ParseTournamentFormat parses a format name as used by the tournament command.
+func ParseTournamentFormat(name string) (TournamentFormat, error) {
+	switch strings.ToLower(name) {
+	case "single", "single_elimination":
+		return TournamentSingleElimination, nil
+	case "double", "double_elimination":
+		return TournamentDoubleElimination, nil
+	case "swiss":
+		return TournamentSwiss, nil
+	default:
+		return 0, fmt.Errorf("unknown tournament format: %s", name)
+	}
+}
+
+type TournamentState int
+
+const (
+	TournamentStateSignUp TournamentState = iota
+	TournamentStateInProgress
+	TournamentStateFinished
+	TournamentStateCancelled
+)
+
+func (s TournamentState) String() string {
+	switch s {
+	case TournamentStateSignUp:
+		return "Sign-up"
+	case TournamentStateInProgress:
+		return "In Progress"
+	case TournamentStateFinished:
+		return "Finished"
+	case TournamentStateCancelled:
+		return "Cancelled"
+	default:
+		return "Unknown"
+	}
+}
+
+// TournamentPresets are the battle settings a tournament can be played with.
+var TournamentPresets = map[string]func() GameSettings{
+	"standard": DefaultGameSettings,
+	"quick": func() GameSettings {
+		settings := DefaultGameSettings()
+		settings.TeamSize = 1
+		settings.MaxTurns = 15
+		return settings
+	},
+	"level50": func() GameSettings {
+		settings := DefaultGameSettings()
+		settings.LevelCap = 50
+		return settings
+	},
+}
+
+type TournamentParticipant struct {
+	UserID    snowflake.ID   `json:"user_id"`
+	Rating    int            `json:"rating"`
+	Seed      int            `json:"seed"`
+	Wins      int            `json:"wins"`
+	Losses    int            `json:"losses"`
+	Draws     int            `json:"draws"`
+	Byes      int            `json:"byes"`
+	Opponents []snowflake.ID `json:"opponents"`
+}
+
+// Points is the participant's Swiss score, byes count as wins.
+func (p *TournamentParticipant) Points() float64 {
+	return float64(p.Wins) + float64(p.Draws)/2
+}
+
+func (p *TournamentParticipant) hasPlayed(opponent snowflake.ID) bool {
+	for _, id := range p.Opponents {
+		if id == opponent {
+			return true
+		}
+	}
+	return false
+}
+
+// TournamentMatch is a pairing in a tournament round. A match without a second
+// player is a bye.
+type TournamentMatch struct {
+	ID       int           `json:"id"`
+	Round    int           `json:"round"`
+	Player1  snowflake.ID  `json:"player1"`
+	Player2  snowflake.ID  `json:"player2"`
+	BattleID uuid.UUID     `json:"battle_id"`
+	Winner   *snowflake.ID `json:"winner,omitempty"`
+	Done     bool          `json:"done"`
+	Walkover bool          `json:"walkover"`
+}
+
+func (m *TournamentMatch) IsBye() bool {
+	return m.Player2 == 0
+}
+
+func (m *TournamentMatch) Loser() snowflake.ID {
+	if m.Winner == nil || m.IsBye() {
+		return 0
+	}
+	if *m.Winner == m.Player1 {
+		return m.Player2
+	}
+	return m.Player1
+}
+
+type Tournament struct {
+	ID             uuid.UUID                `json:"id"`
+	GuildID        snowflake.ID             `json:"guild_id"`
+	ChannelID      snowflake.ID             `json:"channel_id"`
+	ThreadID       snowflake.ID             `json:"thread_id"`
+	CreatorID      snowflake.ID             `json:"creator_id"`
+	Name           string                   `json:"name"`
+	Format         TournamentFormat         `json:"format"`
+	Preset         string                   `json:"preset"`
+	Settings       GameSettings             `json:"settings"`
+	State          TournamentState          `json:"state"`
+	SignupDeadline time.Time                `json:"signup_deadline"`
+	Round          int                      `json:"round"`
+	SwissRounds    int                      `json:"swiss_rounds"`
+	Participants   []*TournamentParticipant `json:"participants"`
+	Matches        []*TournamentMatch       `json:"matches"`
+	Winner         *snowflake.ID            `json:"winner,omitempty"`
+	CreatedAt      time.Time                `json:"created_at"`
+}
+
+func NewTournament(name string, format TournamentFormat, preset string, guildID, channelID, creatorID snowflake.ID, deadline time.Time) (*Tournament, error) {
+	newSettings, ok := TournamentPresets[preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown settings preset: %s", preset)
+	}
+
+	settings := newSettings()
+	settings.ELOEnabled = false
+
+	return &Tournament{
+		ID:             uuid.New(),
+		GuildID:        guildID,
+		ChannelID:      channelID,
+		CreatorID:      creatorID,
+		Name:           name,
+		Format:         format,
+		Preset:         preset,
+		Settings:       settings,
+		State:          TournamentStateSignUp,
+		SignupDeadline: deadline,
+		Participants:   make([]*TournamentParticipant, 0),
+		Matches:        make([]*TournamentMatch, 0),
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+func (t *Tournament) Participant(userID snowflake.ID) *TournamentParticipant {
+	for _, p := range t.Participants {
+		if p.UserID == userID {
+			return p
+		}
+	}
+	return nil
+}
+
+func (t *Tournament) Match(matchID int) *TournamentMatch {
+	for _, m := range t.Matches {
+		if m.ID == matchID {
+			return m
+		}
+	}
+	return nil
+}
+
+// MatchForBattle returns the match played by the given battle.
+func (t *Tournament) MatchForBattle(battleID uuid.UUID) *TournamentMatch {
+	for _, m := range t.Matches {
+		if m.BattleID == battleID {
+			return m
+		}
+	}
+	return nil
+}
+
+func (t *Tournament) RoundMatches(round int) []*TournamentMatch {
+	matches := make([]*TournamentMatch, 0)
+	for _, m := range t.Matches {
+		if m.Round == round {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+func (t *Tournament) Join(userID snowflake.ID, rating int) error {
+	if t.State != TournamentStateSignUp {
+		return fmt.Errorf("sign-ups for this tournament are closed")
+	}
+	if t.Participant(userID) != nil {
+		return fmt.Errorf("you have already joined this tournament")
+	}
+	if len(t.Participants) >= MaxTournamentPlayers {
+		return fmt.Errorf("this tournament is full (%d players)", MaxTournamentPlayers)
+	}
+
+	t.Participants = append(t.Participants, &TournamentParticipant{
+		UserID:    userID,
+		Rating:    rating,
+		Opponents: make([]snowflake.ID, 0),
+	})
+	return nil
+}
+
+func (t *Tournament) Leave(userID snowflake.ID) error {
+	if t.State != TournamentStateSignUp {
+		return fmt.Errorf("you can only leave a tournament during sign-ups")
+	}
+
+	for i, p := range t.Participants {
+		if p.UserID == userID {
+			t.Participants = append(t.Participants[:i], t.Participants[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("you have not joined this tournament")
+}
+
+// Start closes sign-ups, seeds participants by rating and pairs the first round.
+func (t *Tournament) Start() ([]*TournamentMatch, error) {
+	if t.State != TournamentStateSignUp {
+		return nil, fmt.Errorf("tournament has already started")
+	}
+	if len(t.Participants) < MinTournamentPlayers {
+		return nil, fmt.Errorf("at least %d players are needed to start", MinTournamentPlayers)
+	}
+
+	sort.SliceStable(t.Participants, func(i, j int) bool {
+		return t.Participants[i].Rating > t.Participants[j].Rating
+	})
+	for i, p := range t.Participants {
+		p.Seed = i + 1
+	}
+
+	// Enough Swiss rounds for a single undefeated player to remain
+	t.SwissRounds = bits.Len(uint(len(t.Participants) - 1))
+	t.State = TournamentStateInProgress
+
+	return t.nextRound(), nil
+}
+
+func (t *Tournament) Cancel() {
+	t.State = TournamentStateCancelled
+}
+
+// RecordResult stores a match result and, once every match of the round is
+// done, pairs the next round or finishes the tournament. A nil winner is a
+// draw; in elimination formats the higher seed advances.
+func (t *Tournament) RecordResult(matchID int, winner *snowflake.ID, walkover bool) ([]*TournamentMatch, error) {
+	if t.State != TournamentStateInProgress {
+		return nil, fmt.Errorf("tournament is not in progress")
+	}
+
+	match := t.Match(matchID)
+	if match == nil {
+		return nil, fmt.Errorf("match %d not found", matchID)
+	}
+	if match.Done {
+		return nil, fmt.Errorf("match %d already has a result", matchID)
+	}
+	if winner != nil && *winner != match.Player1 && *winner != match.Player2 {
+		return nil, fmt.Errorf("winner is not part of match %d", matchID)
+	}
+
+	p1, p2 := t.Participant(match.Player1), t.Participant(match.Player2)
+	if winner == nil && t.Format != TournamentSwiss {
+		higherSeed := match.Player1
+		if p2.Seed < p1.Seed {
+			higherSeed = match.Player2
+		}
+		winner = &higherSeed
+	}
+
+	match.Done = true
+	match.Walkover = walkover
+	match.Winner = winner
+
+	switch {
+	case winner == nil:
+		p1.Draws++
+		p2.Draws++
+	case *winner == p1.UserID:
+		p1.Wins++
+		p2.Losses++
+	default:
+		p2.Wins++
+		p1.Losses++
+	}
+
+	if !t.roundComplete() {
+		return nil, nil
+	}
+	return t.nextRound(), nil
+}
+
+func (t *Tournament) roundComplete() bool {
+	for _, m := range t.RoundMatches(t.Round) {
+		if !m.Done {
+			return false
+		}
+	}
+	return true
+}
+
+// maxLosses is the number of losses that eliminates a player, or 0 for Swiss.
+func (t *Tournament) maxLosses() int {
+	switch t.Format {
+	case TournamentSingleElimination:
+		return 1
+	case TournamentDoubleElimination:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// Active returns the participants still in the running, ordered by seed.
+func (t *Tournament) Active() []*TournamentParticipant {
+	active := make([]*TournamentParticipant, 0, len(t.Participants))
+	for _, p := range t.Participants {
+		if t.maxLosses() == 0 || p.Losses < t.maxLosses() {
+			active = append(active, p)
+		}
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].Seed < active[j].Seed
+	})
+	return active
+}
+
+// nextRound pairs the next round, or finishes the tournament if a winner is decided.
+// Rounds made up only of byes are skipped.
+func (t *Tournament) nextRound() []*TournamentMatch {
+	for t.State == TournamentStateInProgress {
+		if t.finished() {
+			t.finish()
+			return nil
+		}
+
+		t.Round++
+		var pairs [][2]*TournamentParticipant
+		if t.Format == TournamentSwiss {
+			pairs = t.swissPairs()
+		} else {
+			pairs = t.eliminationPairs()
+		}
+
+		matches := make([]*TournamentMatch, 0, len(pairs))
+		for _, pair := range pairs {
+			match := t.addMatch(pair[0], pair[1])
+			if !match.IsBye() {
+				matches = append(matches, match)
+			}
+		}
+
+		if len(matches) > 0 {
+			return matches
+		}
+	}
+	return nil
+}
+
+func (t *Tournament) finished() bool {
+	if t.Format == TournamentSwiss {
+		return t.Round >= t.SwissRounds
+	}
+	return len(t.Active()) <= 1
+}
+
+func (t *Tournament) finish() {
+	t.State = TournamentStateFinished
+	if standings := t.Standings(); len(standings) > 0 {
+		t.Winner = &standings[0].UserID
+	}
+}
+
+func (t *Tournament) addMatch(p1, p2 *TournamentParticipant) *TournamentMatch {
+	match := &TournamentMatch{
+		ID:      len(t.Matches) + 1,
+		Round:   t.Round,
+		Player1: p1.UserID,
+	}
+
+	if p2 == nil {
+		match.Winner = &p1.UserID
+		match.Done = true
+		p1.Byes++
+		p1.Wins++
+	} else {
+		match.Player2 = p2.UserID
+		p1.Opponents = append(p1.Opponents, p2.UserID)
+		p2.Opponents = append(p2.Opponents, p1.UserID)
+	}
+
+	t.Matches = append(t.Matches, match)
+	return match
+}
+
+// eliminationPairs pairs players who have the same number of losses, highest
+// seed against lowest. The last two players always meet, which makes the
+// double elimination grand final (and its reset) fall out naturally.
+func (t *Tournament) eliminationPairs() [][2]*TournamentParticipant {
+	active := t.Active()
+	if len(active) == 2 {
+		return [][2]*TournamentParticipant{{active[0], active[1]}}
+	}
+
+	pairs := make([][2]*TournamentParticipant, 0, len(active)/2+1)
+	for losses := 0; losses < t.maxLosses(); losses++ {
+		group := make([]*TournamentParticipant, 0)
+		for _, p := range active {
+			if p.Losses == losses {
+				group = append(group, p)
+			}
+		}
+
+		if len(group)%2 == 1 {
+			bye := byeCandidate(group, false)
+			pairs = append(pairs, [2]*TournamentParticipant{bye, nil})
+			group = without(group, bye)
+		}
+
+		for i := 0; i < len(group)/2; i++ {
+			pairs = append(pairs, [2]*TournamentParticipant{group[i], group[len(group)-1-i]})
+		}
+	}
+
+	return pairs
+}
+
+// swissPairs pairs players with similar scores, avoiding rematches where possible.
+func (t *Tournament) swissPairs() [][2]*TournamentParticipant {
+	players := t.Standings()
+	pairs := make([][2]*TournamentParticipant, 0, len(players)/2+1)
+
+	if len(players)%2 == 1 {
+		bye := byeCandidate(players, true)
+		pairs = append(pairs, [2]*TournamentParticipant{bye, nil})
+		players = without(players, bye)
+	}
+
+	for len(players) > 0 {
+		player := players[0]
+		opponent := players[1]
+		for _, candidate := range players[1:] {
+			if !player.hasPlayed(candidate.UserID) {
+				opponent = candidate
+				break
+			}
+		}
+
+		pairs = append(pairs, [2]*TournamentParticipant{player, opponent})
+		players = without(without(players, player), opponent)
+	}
+
+	return pairs
+}
+
+// byeCandidate picks who sits out a round among players with the fewest byes:
+// the highest seed in elimination formats, the lowest ranked player in Swiss.
+func byeCandidate(players []*TournamentParticipant, lowest bool) *TournamentParticipant {
+	var candidate *TournamentParticipant
+	for i := range players {
+		p := players[i]
+		if lowest {
+			p = players[len(players)-1-i]
+		}
+		if candidate == nil || p.Byes < candidate.Byes {
+			candidate = p
+		}
+	}
+	return candidate
+}
+
+func without(players []*TournamentParticipant, player *TournamentParticipant) []*TournamentParticipant {
+	result := make([]*TournamentParticipant, 0, len(players))
+	for _, p := range players {
+		if p != player {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Buchholz is the sum of a participant's opponents' points, the Swiss tiebreaker.
+func (t *Tournament) Buchholz(p *TournamentParticipant) float64 {
+	total := 0.0
+	for _, id := range p.Opponents {
+		if opponent := t.Participant(id); opponent != nil {
+			total += opponent.Points()
+		}
+	}
+	return total
+}
+
+// Standings orders participants by their tournament result. Swiss tournaments
+// rank by points then Buchholz; elimination formats by losses then wins.
+// Seed breaks any remaining tie.
+func (t *Tournament) Standings() []*TournamentParticipant {
+	standings := make([]*TournamentParticipant, len(t.Participants))
+	copy(standings, t.Participants)
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if t.Format == TournamentSwiss {
+			if a.Points() != b.Points() {
+				return a.Points() > b.Points()
+			}
+			if ba, bb := t.Buchholz(a), t.Buchholz(b); ba != bb {
+				return ba > bb
+			}
+		} else {
+			if a.Losses != b.Losses {
+				return a.Losses < b.Losses
+			}
+			if a.Wins != b.Wins {
+				return a.Wins > b.Wins
+			}
+		}
+		return a.Seed < b.Seed
+	})
+
+	return standings
+}
+
+// ShortID returns the short form of the tournament ID shown to players.
+func (t *Tournament) ShortID() string {
+	return t.ID.String()[:8]
+}
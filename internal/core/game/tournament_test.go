@@ -0,0 +1,229 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// newTestTournament returns a started tournament whose players are seeded in
+// the order of their IDs, player 1 being the top seed.
+func newTestTournament(t *testing.T, format TournamentFormat, players int) (*Tournament, []*TournamentMatch) {
+	t.Helper()
+
+	tournament, err := NewTournament("Test Cup", format, "standard", 1, 2, 3, time.Now())
+	if err != nil {
+		t.Fatalf("NewTournament: %v", err)
+	}
+	for i := 1; i <= players; i++ {
+		if err := tournament.Join(snowflake.ID(i), 2000-i); err != nil {
+			t.Fatalf("Join(%d): %v", i, err)
+		}
+	}
+
+	matches, err := tournament.Start()
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	return tournament, matches
+}
+
+// pairings returns the players of each match as pairs of IDs.
+func pairings(matches []*TournamentMatch) [][2]snowflake.ID {
+	pairs := make([][2]snowflake.ID, 0, len(matches))
+	for _, m := range matches {
+		pairs = append(pairs, [2]snowflake.ID{m.Player1, m.Player2})
+	}
+	return pairs
+}
+
+func assertPairings(t *testing.T, matches []*TournamentMatch, want [][2]snowflake.ID) {
+	t.Helper()
+
+	got := pairings(matches)
+	if len(got) != len(want) {
+		t.Fatalf("pairings = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("pairings = %v, want %v", got, want)
+		}
+	}
+}
+
+// win records a win for the given player in their match of the current round.
+func win(t *testing.T, tournament *Tournament, winner snowflake.ID) []*TournamentMatch {
+	t.Helper()
+
+	for _, m := range tournament.RoundMatches(tournament.Round) {
+		if !m.Done && (m.Player1 == winner || m.Player2 == winner) {
+			next, err := tournament.RecordResult(m.ID, &winner, false)
+			if err != nil {
+				t.Fatalf("RecordResult(%d): %v", m.ID, err)
+			}
+			return next
+		}
+	}
+
+	t.Fatalf("player %d has no match in round %d", winner, tournament.Round)
+	return nil
+}
+
+func TestTournamentStart(t *testing.T) {
+	tournament, err := NewTournament("Test Cup", TournamentSingleElimination, "standard", 1, 2, 3, time.Now())
+	if err != nil {
+		t.Fatalf("NewTournament: %v", err)
+	}
+
+	tournament.Join(1, 1000)
+	if _, err := tournament.Start(); err == nil {
+		t.Fatal("Start with one player succeeded")
+	}
+
+	tournament.Join(2, 1500)
+	tournament.Join(3, 1200)
+	if _, err := tournament.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	for id, seed := range map[snowflake.ID]int{2: 1, 3: 2, 1: 3} {
+		if got := tournament.Participant(id).Seed; got != seed {
+			t.Errorf("player %d seed = %d, want %d", id, got, seed)
+		}
+	}
+	if tournament.State != TournamentStateInProgress {
+		t.Errorf("state = %s, want %s", tournament.State, TournamentStateInProgress)
+	}
+	if _, err := tournament.Start(); err == nil {
+		t.Error("starting a started tournament succeeded")
+	}
+	if err := tournament.Join(4, 1000); err == nil {
+		t.Error("joining a started tournament succeeded")
+	}
+}
+
+func TestSingleElimination(t *testing.T) {
+	tournament, matches := newTestTournament(t, TournamentSingleElimination, 4)
+	assertPairings(t, matches, [][2]snowflake.ID{{1, 4}, {2, 3}})
+
+	if next := win(t, tournament, 4); next != nil {
+		t.Fatalf("next round paired before the round finished: %v", pairings(next))
+	}
+	next := win(t, tournament, 2)
+	assertPairings(t, next, [][2]snowflake.ID{{2, 4}})
+
+	if next := win(t, tournament, 4); next != nil {
+		t.Fatalf("matches paired after the final: %v", pairings(next))
+	}
+	if tournament.State != TournamentStateFinished {
+		t.Fatalf("state = %s, want %s", tournament.State, TournamentStateFinished)
+	}
+	if tournament.Winner == nil || *tournament.Winner != 4 {
+		t.Errorf("winner = %v, want 4", tournament.Winner)
+	}
+}
+
+func TestSingleEliminationBye(t *testing.T) {
+	tournament, matches := newTestTournament(t, TournamentSingleElimination, 3)
+
+	// The top seed sits out the first round
+	assertPairings(t, matches, [][2]snowflake.ID{{2, 3}})
+	if p := tournament.Participant(1); p.Byes != 1 || p.Wins != 1 {
+		t.Errorf("top seed byes = %d, wins = %d, want 1 and 1", p.Byes, p.Wins)
+	}
+
+	next := win(t, tournament, 3)
+	assertPairings(t, next, [][2]snowflake.ID{{1, 3}})
+}
+
+func TestDoubleEliminationGrandFinalReset(t *testing.T) {
+	tournament, matches := newTestTournament(t, TournamentDoubleElimination, 2)
+	assertPairings(t, matches, [][2]snowflake.ID{{1, 2}})
+
+	// Losing once does not eliminate, so the final is played again
+	assertPairings(t, win(t, tournament, 1), [][2]snowflake.ID{{1, 2}})
+	assertPairings(t, win(t, tournament, 2), [][2]snowflake.ID{{1, 2}})
+
+	win(t, tournament, 2)
+	if tournament.State != TournamentStateFinished {
+		t.Fatalf("state = %s, want %s", tournament.State, TournamentStateFinished)
+	}
+	if tournament.Winner == nil || *tournament.Winner != 2 {
+		t.Errorf("winner = %v, want 2", tournament.Winner)
+	}
+}
+
+func TestSwissPairing(t *testing.T) {
+	tournament, matches := newTestTournament(t, TournamentSwiss, 4)
+	if tournament.SwissRounds != 2 {
+		t.Fatalf("swiss rounds = %d, want 2", tournament.SwissRounds)
+	}
+	assertPairings(t, matches, [][2]snowflake.ID{{1, 2}, {3, 4}})
+
+	win(t, tournament, 1)
+	next := win(t, tournament, 3)
+
+	// Winners meet winners, nobody plays the same opponent twice
+	assertPairings(t, next, [][2]snowflake.ID{{1, 3}, {2, 4}})
+
+	win(t, tournament, 3)
+	if next := win(t, tournament, 2); next != nil {
+		t.Fatalf("matches paired after the last round: %v", pairings(next))
+	}
+	if tournament.State != TournamentStateFinished {
+		t.Fatalf("state = %s, want %s", tournament.State, TournamentStateFinished)
+	}
+
+	// Players 1 and 3 both have a win, player 3's opponents scored more
+	standings := tournament.Standings()
+	want := []snowflake.ID{3, 1, 2, 4}
+	for i, p := range standings {
+		if p.UserID != want[i] {
+			t.Fatalf("standings[%d] = %d, want %d", i, p.UserID, want[i])
+		}
+	}
+}
+
+func TestRecordResultDraw(t *testing.T) {
+	swiss, matches := newTestTournament(t, TournamentSwiss, 2)
+	if _, err := swiss.RecordResult(matches[0].ID, nil, false); err != nil {
+		t.Fatalf("RecordResult: %v", err)
+	}
+	if p1, p2 := swiss.Participant(1), swiss.Participant(2); p1.Draws != 1 || p2.Draws != 1 || p1.Points() != 0.5 {
+		t.Errorf("draws = %d and %d, points = %.1f, want 1, 1 and 0.5", p1.Draws, p2.Draws, p1.Points())
+	}
+
+	// Elimination formats cannot draw, the higher seed advances
+	elimination, matches := newTestTournament(t, TournamentSingleElimination, 2)
+	if _, err := elimination.RecordResult(matches[0].ID, nil, false); err != nil {
+		t.Fatalf("RecordResult: %v", err)
+	}
+	if winner := matches[0].Winner; winner == nil || *winner != 1 {
+		t.Errorf("winner = %v, want 1", winner)
+	}
+}
+
+func TestRecordResultErrors(t *testing.T) {
+	tournament, matches := newTestTournament(t, TournamentSingleElimination, 4)
+	match := matches[0]
+
+	outsider := snowflake.ID(2)
+	if _, err := tournament.RecordResult(match.ID, &outsider, false); err == nil {
+		t.Error("recording a winner from outside the match succeeded")
+	}
+	if _, err := tournament.RecordResult(99, nil, false); err == nil {
+		t.Error("recording an unknown match succeeded")
+	}
+
+	winner := match.Player1
+	if _, err := tournament.RecordResult(match.ID, &winner, true); err != nil {
+		t.Fatalf("RecordResult: %v", err)
+	}
+	if !match.Walkover {
+		t.Error("walkover was not recorded")
+	}
+	if _, err := tournament.RecordResult(match.ID, &winner, false); err == nil {
+		t.Error("recording a result twice succeeded")
+	}
+}
@@ -0,0 +1,319 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	TradeTimeout       = 10 * time.Minute // How long a trade stays open without changes
+	MaxTradeCharacters = 20               // Most characters one side can offer
+	tradeSideCount     = 2
+)
+
+var ErrNotInTrade = errors.New("you are not in a trade")
+
+// TradeSide is what one player offers in a trade.
+type TradeSide struct {
+	UserID     snowflake.ID
+	Characters []Character
+	Coins      int
+	Confirmed  bool
+}
+
+// Trade is an open exchange of characters and coins between two players. Both
+// sides must confirm the same offer before it is carried out.
+type Trade struct {
+	ID        uuid.UUID
+	ChannelID snowflake.ID
+	MessageID snowflake.ID
+	Sides     [tradeSideCount]TradeSide
+	Executing bool // Set while the exchange is being written, freezing the offer
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Side returns the offer of a player in the trade, or nil if they are not part of it.
+func (t *Trade) Side(userID snowflake.ID) *TradeSide {
+	for i := range t.Sides {
+		if t.Sides[i].UserID == userID {
+			return &t.Sides[i]
+		}
+	}
+	return nil
+}
+
+// Partner returns the offer of the other player in the trade.
+func (t *Trade) Partner(userID snowflake.ID) *TradeSide {
+	for i := range t.Sides {
+		if t.Sides[i].UserID != userID {
+			return &t.Sides[i]
+		}
+	}
+	return nil
+}
+
+// Confirmed reports whether both players confirmed the current offer.
+func (t *Trade) Confirmed() bool {
+	for _, side := range t.Sides {
+		if !side.Confirmed {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty reports whether neither player offered anything.
+func (t *Trade) IsEmpty() bool {
+	for _, side := range t.Sides {
+		if len(side.Characters) > 0 || side.Coins > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// changed resets both confirmations after the offer changed, so nobody
+// confirms something they have not seen.
+func (t *Trade) changed() {
+	for i := range t.Sides {
+		t.Sides[i].Confirmed = false
+	}
+	t.ExpiresAt = time.Now().Add(TradeTimeout)
+}
+
+func (t *Trade) clone() *Trade {
+	c := *t
+	for i := range c.Sides {
+		c.Sides[i].Characters = append([]Character(nil), t.Sides[i].Characters...)
+	}
+	return &c
+}
+
+// TradeManager keeps track of open trades. A player can only be in one trade
+// at a time. Methods return copies, so callers can read them freely.
+type TradeManager struct {
+	trades       map[uuid.UUID]*Trade
+	playerTrades map[snowflake.ID]uuid.UUID
+	mutex        sync.Mutex
+}
+
+func NewTradeManager() *TradeManager {
+	return &TradeManager{
+		trades:       make(map[uuid.UUID]*Trade),
+		playerTrades: make(map[snowflake.ID]uuid.UUID),
+	}
+}
+
+// Open starts a trade between two players.
+func (tm *TradeManager) Open(initiator, partner, channelID snowflake.ID) (*Trade, error) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	tm.cleanupExpired()
+
+	if initiator == partner {
+		return nil, fmt.Errorf("you can't trade with yourself")
+	}
+	if _, exists := tm.playerTrades[initiator]; exists {
+		return nil, fmt.Errorf("you are already in a trade")
+	}
+	if _, exists := tm.playerTrades[partner]; exists {
+		return nil, fmt.Errorf("that player is already in a trade")
+	}
+
+	now := time.Now()
+	trade := &Trade{
+		ID:        uuid.New(),
+		ChannelID: channelID,
+		Sides: [tradeSideCount]TradeSide{
+			{UserID: initiator},
+			{UserID: partner},
+		},
+		CreatedAt: now,
+		ExpiresAt: now.Add(TradeTimeout),
+	}
+
+	tm.trades[trade.ID] = trade
+	tm.playerTrades[initiator] = trade.ID
+	tm.playerTrades[partner] = trade.ID
+
+	return trade.clone(), nil
+}
+
+// GetPlayerTrade returns the open trade of a player.
+func (tm *TradeManager) GetPlayerTrade(userID snowflake.ID) (*Trade, bool) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	tm.cleanupExpired()
+
+	trade, exists := tm.trades[tm.playerTrades[userID]]
+	if !exists {
+		return nil, false
+	}
+	return trade.clone(), true
+}
+
+// SetMessage records the message showing the trade, so it can be kept up to date.
+func (tm *TradeManager) SetMessage(tradeID uuid.UUID, messageID snowflake.ID) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	if trade, exists := tm.trades[tradeID]; exists {
+		trade.MessageID = messageID
+	}
+}
+
+// AddCharacter adds a character to a player's offer.
+func (tm *TradeManager) AddCharacter(userID snowflake.ID, character Character) (*Trade, error) {
+	return tm.update(userID, func(trade *Trade, side *TradeSide) error {
+		if len(side.Characters) >= MaxTradeCharacters {
+			return fmt.Errorf("you can offer at most %d characters", MaxTradeCharacters)
+		}
+		for _, offered := range side.Characters {
+			if offered.ID == character.ID {
+				return fmt.Errorf("that character is already in the trade")
+			}
+		}
+
+		side.Characters = append(side.Characters, character)
+		return nil
+	})
+}
+
+// RemoveCharacter takes a character out of a player's offer.
+func (tm *TradeManager) RemoveCharacter(userID snowflake.ID, characterID uuid.UUID) (*Trade, error) {
+	return tm.update(userID, func(trade *Trade, side *TradeSide) error {
+		for i, offered := range side.Characters {
+			if offered.ID == characterID {
+				side.Characters = append(side.Characters[:i], side.Characters[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("that character is not in the trade")
+	})
+}
+
+// SetCoins changes how many coins a player offers.
+func (tm *TradeManager) SetCoins(userID snowflake.ID, coins int) (*Trade, error) {
+	return tm.update(userID, func(trade *Trade, side *TradeSide) error {
+		if coins < 0 {
+			return fmt.Errorf("you can't offer a negative amount")
+		}
+		side.Coins = coins
+		return nil
+	})
+}
+
+// Confirm accepts the current offer for a player. Once both players confirmed,
+// the trade is frozen until Finish or Abort is called.
+func (tm *TradeManager) Confirm(userID snowflake.ID) (*Trade, error) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	trade, side, err := tm.playerSide(userID)
+	if err != nil {
+		return nil, err
+	}
+	if trade.IsEmpty() {
+		return nil, fmt.Errorf("add something to the trade first")
+	}
+
+	side.Confirmed = true
+	if trade.Confirmed() {
+		trade.Executing = true
+	}
+
+	return trade.clone(), nil
+}
+
+// Cancel closes a player's trade without exchanging anything.
+func (tm *TradeManager) Cancel(userID snowflake.ID) (*Trade, error) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	trade, _, err := tm.playerSide(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tm.remove(trade)
+	return trade.clone(), nil
+}
+
+// Finish closes a trade after it was carried out.
+func (tm *TradeManager) Finish(tradeID uuid.UUID) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	if trade, exists := tm.trades[tradeID]; exists {
+		tm.remove(trade)
+	}
+}
+
+// Abort reopens a trade that could not be carried out, resetting both
+// confirmations.
+func (tm *TradeManager) Abort(tradeID uuid.UUID) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	if trade, exists := tm.trades[tradeID]; exists {
+		trade.Executing = false
+		trade.changed()
+	}
+}
+
+func (tm *TradeManager) update(userID snowflake.ID, fn func(*Trade, *TradeSide) error) (*Trade, error) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	trade, side, err := tm.playerSide(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fn(trade, side); err != nil {
+		return nil, err
+	}
+
+	trade.changed()
+	return trade.clone(), nil
+}
+
+func (tm *TradeManager) playerSide(userID snowflake.ID) (*Trade, *TradeSide, error) {
+	tm.cleanupExpired()
+
+	trade, exists := tm.trades[tm.playerTrades[userID]]
+	if !exists {
+		return nil, nil, ErrNotInTrade
+	}
+	if trade.Executing {
+		return nil, nil, fmt.Errorf("the trade is being completed")
+	}
+
+	return trade, trade.Side(userID), nil
+}
+
+func (tm *TradeManager) remove(trade *Trade) {
+	delete(tm.trades, trade.ID)
+	for _, side := range trade.Sides {
+		if tm.playerTrades[side.UserID] == trade.ID {
+			delete(tm.playerTrades, side.UserID)
+		}
+	}
+}
+
+func (tm *TradeManager) cleanupExpired() {
+	now := time.Now()
+	for _, trade := range tm.trades {
+		if !trade.Executing && now.After(trade.ExpiresAt) {
+			tm.remove(trade)
+		}
+	}
+}
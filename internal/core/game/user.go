@@ -1,6 +1,9 @@
 package game
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/disgoorg/snowflake/v2"
 	"github.com/google/uuid"
 )
@@ -13,11 +16,40 @@ const (
 	OrderByLevel
 )
 
+var orderByNames = map[OrderBy]string{
+	OrderByIDX:   "number",
+	OrderByIV:    "iv",
+	OrderByLevel: "level",
+}
+
+// OrderByName returns the name of a sort key as used by commands.
+func OrderByName(o OrderBy) string {
+	return orderByNames[o]
+}
+
+// ParseOrderBy returns the sort key with the given name.
+func ParseOrderBy(name string) (OrderBy, bool) {
+	for o, n := range orderByNames {
+		if n == name {
+			return o, true
+		}
+	}
+	return OrderByIDX, false
+}
+
 type OrderOptions struct {
 	OrderBy OrderBy
 	Desc    bool
 }
 
+func (o OrderOptions) String() string {
+	direction := "ascending"
+	if o.Desc {
+		direction = "descending"
+	}
+	return fmt.Sprintf("%s, %s", OrderByName(o.OrderBy), direction)
+}
+
 type User struct {
 	ID            snowflake.ID
 	Balance       int
@@ -26,4 +58,32 @@ type User struct {
 	NextIdx       int
 	ShiniesCaught int
 	ELO           int
+	BattlesWon    int
+	BattlesLost   int
+	JoinedAt      time.Time
+
+	RatingDeviation  float64
+	RatingVolatility float64
+	LastRankedAt     time.Time
+
+	DailyStreak int
+	LastDailyAt time.Time
+
+	DexMilestones int // How many of DexMilestones were rewarded
+}
+
+// Rating returns the user's rating for the rating system.
+func (u *User) Rating() Rating {
+	return Rating{
+		Rating:     float64(u.ELO),
+		Deviation:  u.RatingDeviation,
+		Volatility: u.RatingVolatility,
+	}
+}
+
+// SetRating stores a new rating on the user.
+func (u *User) SetRating(r Rating) {
+	u.ELO = r.Value()
+	u.RatingDeviation = r.Deviation
+	u.RatingVolatility = r.Volatility
 }
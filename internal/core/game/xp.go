@@ -0,0 +1,206 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	MinMessageXP         = 15
+	MaxMessageXP         = 25
+	XPCooldown           = time.Minute // How often a user's messages give XP
+	BattleWinXP          = 100         // XP for each character on the winning team
+	MaxXPMultiplier      = 5.0
+	LevelUpReward        = 10 // Coins for each level, times the level reached
+	MilestoneLevel       = 10 // Every this many levels also gives MilestoneRewardItem
+	MilestoneRewardItem  = 2  // XP Candy L
+	MaxMoves             = 4
+	StatusMoveLearnLevel = 20
+)
+
+// XPGain is XP waiting to be given to a character, and where to announce any
+// level it reaches.
+type XPGain struct {
+	CharacterID uuid.UUID
+	UserID      snowflake.ID
+	GuildID     snowflake.ID // Zero outside a guild
+	ChannelID   snowflake.ID
+	XP          int
+}
+
+// MessageXP rolls the XP a message gives before multipliers.
+func MessageXP() int {
+	return MinMessageXP + rand.Intn(MaxMessageXP-MinMessageXP+1)
+}
+
+// ApplyXPMultiplier scales XP by a multiplier, capped at MaxXPMultiplier.
+func ApplyXPMultiplier(xp int, multiplier float64) int {
+	return int(float64(xp)*min(multiplier, MaxXPMultiplier) + 0.5)
+}
+
+// XPMultiplier returns how much more XP the character gains from its held item.
+func (c *Character) XPMultiplier() float64 {
+	if item, ok := ItemsRegistry[c.HeldItem]; ok && item.XPMultiplier > 0 {
+		return item.XPMultiplier
+	}
+	return 1
+}
+
+// LevelUpNotify is where a guild announces level-ups.
+type LevelUpNotify int
+
+const (
+	LevelUpInChannel LevelUpNotify = iota
+	LevelUpInDM
+	LevelUpOff
+)
+
+func (n LevelUpNotify) String() string {
+	switch n {
+	case LevelUpInChannel:
+		return "Channel"
+	case LevelUpInDM:
+		return "Direct message"
+	case LevelUpOff:
+		return "Off"
+	default:
+		return "Unknown"
+	}
+}
+
+// LevelUp is everything a character got from gaining levels.
+type LevelUp struct {
+	FromLevel int
+	ToLevel   int
+	Moves     []LearnedMove
+	Coins     int
+	Items     []int      // IDs of reward items, one entry per item
+	Evolution *Evolution // The evolution the new level unlocked, nil if none
+}
+
+// LearnedMove is a move learned on level-up and the move it replaced, if any.
+type LearnedMove struct {
+	Move   Move
+	Forgot *Move
+}
+
+func (m LearnedMove) String() string {
+	if m.Forgot != nil {
+		return fmt.Sprintf("learned %s, forgot %s", m.Move.Name, m.Forgot.Name)
+	}
+	return "learned " + m.Move.Name
+}
+
+// GainXP gives the character XP and runs the level-up hooks: it learns the
+// moves of each new level, earns the level rewards and finds any evolution
+// the level unlocked. The zero LevelUp is returned when no level was gained.
+func (c *Character) GainXP(xp int) LevelUp {
+	levelUp := LevelUp{FromLevel: c.Level}
+	if c.AddXP(xp) == 0 {
+		return LevelUp{}
+	}
+	levelUp.ToLevel = c.Level
+	levelUp.Moves = c.LearnMoves(levelUp.FromLevel, levelUp.ToLevel)
+	levelUp.Coins, levelUp.Items = LevelUpRewards(levelUp.FromLevel, levelUp.ToLevel)
+	if evolution, ok := c.ReachedEvolution(levelUp.FromLevel, levelUp.ToLevel); ok {
+		levelUp.Evolution = &evolution
+	}
+	return levelUp
+}
+
+// LevelUpRewards returns the coins and items for levelling up from one level
+// to another.
+func LevelUpRewards(fromLevel, toLevel int) (int, []int) {
+	coins := 0
+	var items []int
+	for level := fromLevel + 1; level <= toLevel; level++ {
+		coins += LevelUpReward * level
+		if level%MilestoneLevel == 0 {
+			items = append(items, MilestoneRewardItem)
+		}
+	}
+	return coins, items
+}
+
+// MoveLearnLevel returns the level a character learns a move of its type at.
+// Stronger moves are learned later.
+func MoveLearnLevel(move Move) int {
+	switch {
+	case move.Category == MoveCatStatus:
+		return StatusMoveLearnLevel
+	case move.Power <= 50:
+		return 1
+	case move.Power <= 75:
+		return 15
+	case move.Power <= 95:
+		return 30
+	default:
+		return 45
+	}
+}
+
+// LearnsMove reports whether the character learns a move by levelling, which
+// it does for every move of its types.
+func (bc BaseCharacter) LearnsMove(move Move) bool {
+	return move.Type == bc.Type0 || (bc.Type1 != TypeNone && move.Type == bc.Type1)
+}
+
+// LearnMoves teaches the character the moves of its types learned after
+// fromLevel and up to toLevel. With every slot taken a new damaging move
+// replaces the weakest known one if it is stronger, so characters grow into
+// their best moves.
+func (c *Character) LearnMoves(fromLevel, toLevel int) []LearnedMove {
+	data := c.Data()
+	if len(c.Moves) == 0 {
+		c.Moves = DefaultMoveset(data, fromLevel)
+	}
+
+	var learned []LearnedMove
+	for id := 1; id <= len(MovesRegistry); id++ {
+		move, exists := MovesRegistry[id]
+		if !exists || slices.Contains(c.Moves, int32(id)) {
+			continue
+		}
+		if !data.LearnsMove(move) {
+			continue
+		}
+		if level := MoveLearnLevel(move); level <= fromLevel || level > toLevel {
+			continue
+		}
+
+		if len(c.Moves) < MaxMoves {
+			c.Moves = append(c.Moves, int32(id))
+			learned = append(learned, LearnedMove{Move: move})
+			continue
+		}
+
+		if move.Category == MoveCatStatus {
+			continue
+		}
+		weakest := -1
+		for i, known := range c.Moves {
+			knownMove := MovesRegistry[int(known)]
+			if knownMove.Category == MoveCatStatus {
+				continue
+			}
+			if weakest < 0 || knownMove.Power < MovesRegistry[int(c.Moves[weakest])].Power {
+				weakest = i
+			}
+		}
+		if weakest < 0 {
+			continue
+		}
+		forgot := MovesRegistry[int(c.Moves[weakest])]
+		if forgot.Power >= move.Power {
+			continue
+		}
+		c.Moves[weakest] = int32(id)
+		learned = append(learned, LearnedMove{Move: move, Forgot: &forgot})
+	}
+	return learned
+}
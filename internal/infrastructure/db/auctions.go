@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/disgoorg/snowflake/v2"
+	"gorm.io/gorm"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+var ErrAuctionChanged = errors.New("the auction changed, try again")
+
+// CreateAuction starts an auction, locking its character until it settles.
+func (db *DB) CreateAuction(ctx context.Context, auction game.Auction) (*game.Auction, error) {
+	dbAuction := Auction{
+		SellerID:    auction.SellerID.String(),
+		CharacterID: auction.Character.ID,
+		ChannelID:   auction.ChannelID.String(),
+		StartingBid: int32(auction.StartingBid),
+		State:       int32(game.AuctionActive),
+		EndsAt:      auction.EndsAt,
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Character{}).
+			Where("id = ? AND owner_id = ? AND NOT locked", auction.Character.ID, auction.SellerID.String()).
+			Update("locked", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCharacterLocked
+		}
+
+		return tx.Create(&dbAuction).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetAuction(ctx, int(dbAuction.ID))
+}
+
+// GetAuction returns an auction with its character, or nil if it does not exist.
+func (db *DB) GetAuction(ctx context.Context, id int) (*game.Auction, error) {
+	var auction Auction
+	result := db.WithContext(ctx).Preload("Character").First(&auction, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return dbAuctionToModelAuction(auction), nil
+}
+
+// GetActiveAuctions returns every auction that has not settled yet.
+func (db *DB) GetActiveAuctions(ctx context.Context) ([]game.Auction, error) {
+	var auctions []Auction
+	err := db.WithContext(ctx).
+		Preload("Character").
+		Where("state = ?", int32(game.AuctionActive)).
+		Order("ends_at ASC").
+		Find(&auctions).Error
+
+	modelAuctions := make([]game.Auction, len(auctions))
+	for i, auction := range auctions {
+		modelAuctions[i] = *dbAuctionToModelAuction(auction)
+	}
+
+	return modelAuctions, err
+}
+
+// SetAuctionMessage records the message showing an auction.
+func (db *DB) SetAuctionMessage(ctx context.Context, id int, messageID snowflake.ID) error {
+	return db.WithContext(ctx).Model(&Auction{}).
+		Where("id = ?", id).
+		Update("message_id", messageID.String()).Error
+}
+
+// UpdateAuctionBid saves a bid placed with game.Auction.PlaceBid. It fails
+// with ErrAuctionChanged if another bid was saved since the auction was read.
+func (db *DB) UpdateAuctionBid(ctx context.Context, auction *game.Auction) error {
+	result := db.WithContext(ctx).Model(&Auction{}).
+		Where("id = ? AND state = ? AND bids = ?", auction.ID, int32(game.AuctionActive), auction.Bids-1).
+		Updates(map[string]interface{}{
+			"current_bid": auction.CurrentBid,
+			"bidder_id":   auction.BidderID.String(),
+			"bids":        auction.Bids,
+			"ends_at":     auction.EndsAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAuctionChanged
+	}
+
+	return nil
+}
+
+// FinishAuction moves an active auction to its final state and unlocks its
+// character. It reports false if the auction already finished or received
+// another bid since it was read, so only one caller settles an auction.
+func (db *DB) FinishAuction(ctx context.Context, auction *game.Auction, state game.AuctionState) (bool, error) {
+	finished := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Auction{}).
+			Where("id = ? AND state = ? AND bids = ?", auction.ID, int32(game.AuctionActive), auction.Bids).
+			Update("state", int32(state))
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		finished = true
+
+		return tx.Model(&Character{}).Where("id = ?", auction.Character.ID).Update("locked", false).Error
+	})
+
+	return finished, err
+}
+
+func dbAuctionToModelAuction(auction Auction) *game.Auction {
+	a := &game.Auction{
+		ID:          int(auction.ID),
+		SellerID:    snowflake.MustParse(auction.SellerID),
+		ChannelID:   snowflake.MustParse(auction.ChannelID),
+		StartingBid: int(auction.StartingBid),
+		CurrentBid:  int(auction.CurrentBid),
+		Bids:        int(auction.Bids),
+		State:       game.AuctionState(auction.State),
+		EndsAt:      auction.EndsAt,
+		CreatedAt:   auction.CreatedAt,
+	}
+	if auction.MessageID != "" {
+		a.MessageID = snowflake.MustParse(auction.MessageID)
+	}
+	if auction.BidderID != "" {
+		a.BidderID = snowflake.MustParse(auction.BidderID)
+	}
+	if auction.Character != nil {
+		a.Character = *dbCharToModelChar(*auction.Character)
+	}
+
+	return a
+}
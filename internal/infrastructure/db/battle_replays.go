@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+func (db *DB) CreateBattleReplay(ctx context.Context, replay *game.BattleReplay) error {
+	data, err := json.Marshal(replay)
+	if err != nil {
+		return fmt.Errorf("failed to encode replay: %w", err)
+	}
+
+	winnerID := ""
+	if replay.Winner != nil {
+		winnerID = replay.Winner.String()
+	}
+
+	dbReplay := BattleReplay{
+		ID:         replay.BattleID,
+		ShortID:    replay.ShortID(),
+		Player1ID:  replay.Player1.ID.String(),
+		Player2ID:  replay.Player2.ID.String(),
+		WinnerID:   winnerID,
+		Turns:      int32(len(replay.Turns)),
+		Data:       data,
+		FinishedAt: replay.FinishedAt,
+	}
+
+	return db.WithContext(ctx).Create(&dbReplay).Error
+}
+
+// GetBattleReplay looks up a replay by its short ID, preferring the most recent
+// battle if several share the same prefix.
+func (db *DB) GetBattleReplay(ctx context.Context, shortID string) (*game.BattleReplay, error) {
+	var dbReplay BattleReplay
+	result := db.WithContext(ctx).
+		Where("short_id = ?", shortID).
+		Order("finished_at DESC").
+		First(&dbReplay)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("replay %s not found", shortID)
+		}
+		return nil, result.Error
+	}
+
+	var replay game.BattleReplay
+	if err := json.Unmarshal(dbReplay.Data, &replay); err != nil {
+		return nil, fmt.Errorf("failed to decode replay: %w", err)
+	}
+
+	return &replay, nil
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+var orderColumns = map[game.OrderBy]string{
+	game.OrderByIDX:   "idx",
+	game.OrderByIV:    "iv_total",
+	game.OrderByLevel: "level",
+}
+
+// CountCharacters returns how many of a user's characters match the filter.
+func (db *DB) CountCharacters(ctx context.Context, userID snowflake.ID, filter game.CharacterFilter) (int, error) {
+	var count int64
+	err := db.filterCharacters(ctx, userID, filter).Count(&count).Error
+	return int(count), err
+}
+
+// ListCharacters returns a page of a user's characters matching the filter.
+func (db *DB) ListCharacters(ctx context.Context, userID snowflake.ID, filter game.CharacterFilter, order game.OrderOptions, offset, limit int) ([]game.Character, error) {
+	column, ok := orderColumns[order.OrderBy]
+	if !ok {
+		column = orderColumns[game.OrderByIDX]
+	}
+
+	direction := " ASC"
+	if order.Desc {
+		direction = " DESC"
+	}
+
+	var characters []Character
+	err := db.filterCharacters(ctx, userID, filter).
+		Order(column + direction).
+		Order("idx" + direction).
+		Offset(offset).
+		Limit(limit).
+		Find(&characters).Error
+
+	modelChars := make([]game.Character, len(characters))
+	for i, char := range characters {
+		modelChars[i] = *dbCharToModelChar(char)
+	}
+
+	return modelChars, err
+}
+
+// UpdateOrder saves the user's default collection order.
+func (db *DB) UpdateOrder(ctx context.Context, userID snowflake.ID, order game.OrderOptions) error {
+	return db.WithContext(ctx).Model(&User{}).
+		Where("id = ?", userID.String()).
+		Updates(map[string]interface{}{
+			"order_by":   int32(order.OrderBy),
+			"order_desc": order.Desc,
+		}).Error
+}
+
+func (db *DB) filterCharacters(ctx context.Context, userID snowflake.ID, filter game.CharacterFilter) *gorm.DB {
+	query := db.WithContext(ctx).Model(&Character{}).Where("owner_id = ?", userID.String())
+	return applyCharacterFilter(query, filter)
+}
+
+// applyCharacterFilter narrows down a query on, or joined with, the characters table.
+func applyCharacterFilter(query *gorm.DB, filter game.CharacterFilter) *gorm.DB {
+	if ids := filter.CharacterIDs(); ids != nil {
+		query = query.Where("characters.character_id IN ?", ids)
+	}
+	if filter.Nickname != "" {
+		query = query.Where("characters.nickname ILIKE ?", "%"+escapeLike(filter.Nickname)+"%")
+	}
+	if filter.Personality != "" {
+		query = query.Where("characters.personality = ?", filter.Personality)
+	}
+	if filter.Shiny != nil {
+		query = query.Where("characters.shiny = ?", *filter.Shiny)
+	}
+	if filter.Favourite != nil {
+		query = query.Where("characters.favourite = ?", *filter.Favourite)
+	}
+	if filter.MinLevel > 0 {
+		query = query.Where("characters.level >= ?", filter.MinLevel)
+	}
+	if filter.MaxLevel > 0 {
+		query = query.Where("characters.level <= ?", filter.MaxLevel)
+	}
+	if filter.MinIV > 0 {
+		query = query.Where("characters.iv_total >= ?", filter.MinIV/100*game.MaxIvTotal)
+	}
+	if filter.MaxIV > 0 {
+		query = query.Where("characters.iv_total <= ?", filter.MaxIV/100*game.MaxIvTotal)
+	}
+
+	return query
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// SetNickname changes a character's nickname, an empty nickname clears it.
+func (db *DB) SetNickname(ctx context.Context, characterID uuid.UUID, nickname string) error {
+	return db.WithContext(ctx).Model(&Character{}).
+		Where("id = ?", characterID).
+		Update("nickname", nickname).Error
+}
+
+// SetFavourite marks or unmarks a user's characters matching the filter as
+// favourites and returns how many changed.
+func (db *DB) SetFavourite(ctx context.Context, userID snowflake.ID, filter game.CharacterFilter, favourite bool) (int, error) {
+	result := db.filterCharacters(ctx, userID, filter).
+		Where("favourite <> ?", favourite).
+		Update("favourite", favourite)
+	return int(result.RowsAffected), result.Error
+}
+
+// SetCharacterFavourite marks or unmarks a single character as a favourite.
+func (db *DB) SetCharacterFavourite(ctx context.Context, characterID uuid.UUID, favourite bool) error {
+	return db.WithContext(ctx).Model(&Character{}).
+		Where("id = ?", characterID).
+		Update("favourite", favourite).Error
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+// RecordDexEntry counts another character of a species caught by a user.
+func (db *DB) RecordDexEntry(ctx context.Context, userID snowflake.ID, characterID int, shiny bool) error {
+	entry := DexEntry{
+		UserID:        userID.String(),
+		CharacterID:   int32(characterID),
+		Caught:        1,
+		FirstCaughtAt: time.Now(),
+	}
+	if shiny {
+		entry.Shinies = 1
+	}
+
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "character_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"caught":     gorm.Expr("dex_entries.caught + EXCLUDED.caught"),
+			"shinies":    gorm.Expr("dex_entries.shinies + EXCLUDED.shinies"),
+			"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(&entry).Error
+}
+
+// GetDex returns the species a user has caught.
+func (db *DB) GetDex(ctx context.Context, userID snowflake.ID) (game.Dex, error) {
+	var entries []DexEntry
+	err := db.WithContext(ctx).
+		Where("user_id = ?", userID.String()).
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	modelEntries := make([]game.DexEntry, len(entries))
+	for i, entry := range entries {
+		modelEntries[i] = game.DexEntry{
+			CharacterID:   int(entry.CharacterID),
+			Caught:        int(entry.Caught),
+			Shinies:       int(entry.Shinies),
+			FirstCaughtAt: entry.FirstCaughtAt,
+		}
+	}
+
+	return game.NewDex(modelEntries), nil
+}
+
+// ClaimDexMilestones records that a user was rewarded the dex milestones up to
+// the given count. It reports false when another claim changed the count since
+// it was read, so each milestone is only paid once.
+func (db *DB) ClaimDexMilestones(ctx context.Context, userID snowflake.ID, claimed, milestones int) (bool, error) {
+	result := db.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND dex_milestones = ?", userID.String(), claimed).
+		Update("dex_milestones", milestones)
+	return result.RowsAffected > 0, result.Error
+}
+
+// backfillDex fills the dex from the characters users own, for players who
+// caught them before the dex was kept. Traded characters count for their
+// current owner.
+func (db *DB) backfillDex() error {
+	return db.DB.Exec(`INSERT INTO dex_entries (user_id, character_id, caught, shinies, first_caught_at, updated_at)
+		SELECT owner_id, character_id, COUNT(*), COUNT(*) FILTER (WHERE shiny), MIN(claimed_timestamp), NOW()
+		FROM characters
+		WHERE owner_id <> ''
+		GROUP BY owner_id, character_id
+		ON CONFLICT DO NOTHING`).Error
+}
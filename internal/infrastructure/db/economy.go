@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+// AdjustBalance atomically adds delta to a user's balance and records the
+// change in the transactions ledger, returning the new balance. Every balance
+// change goes through here so the ledger stays complete.
+func (db *DB) AdjustBalance(ctx context.Context, userID snowflake.ID, delta int, reason game.TransactionReason, reference string) (int, error) {
+	var user User
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&user).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "balance"}}}).
+			Where("id = ? AND balance + ? >= 0", userID.String(), delta).
+			Update("balance", gorm.Expr("balance + ?", delta))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInsufficientBalance
+		}
+
+		return tx.Create(&Transaction{
+			UserID:    userID.String(),
+			Amount:    int32(delta),
+			Balance:   user.Balance,
+			Reason:    string(reason),
+			Reference: reference,
+		}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(user.Balance), nil
+}
+
+// ClaimDaily records a daily claim, moving the user's streak on. It reports
+// false if the user claimed since lastClaim was read, so a reward is only
+// given once.
+func (db *DB) ClaimDaily(ctx context.Context, userID snowflake.ID, lastClaim time.Time, streak int, now time.Time) (bool, error) {
+	query := db.WithContext(ctx).Model(&User{}).Where("id = ?", userID.String())
+	if lastClaim.IsZero() {
+		query = query.Where("last_daily_at IS NULL")
+	} else {
+		query = query.Where("last_daily_at = ?", lastClaim)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"daily_streak":  streak,
+		"last_daily_at": now,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountTransactions counts a user's ledger entries with the given reason since a time.
+func (db *DB) CountTransactions(ctx context.Context, userID snowflake.ID, reason game.TransactionReason, since time.Time) (int, error) {
+	var count int64
+	err := db.WithContext(ctx).Model(&Transaction{}).
+		Where("user_id = ? AND reason = ? AND created_at >= ?", userID.String(), string(reason), since).
+		Count(&count).Error
+
+	return int(count), err
+}
+
+// GetTransactions returns a user's most recent ledger entries, newest first.
+func (db *DB) GetTransactions(ctx context.Context, userID snowflake.ID, limit int) ([]game.Transaction, error) {
+	var transactions []Transaction
+	err := db.WithContext(ctx).
+		Where("user_id = ?", userID.String()).
+		Order("id DESC").
+		Limit(limit).
+		Find(&transactions).Error
+
+	modelTransactions := make([]game.Transaction, len(transactions))
+	for i, transaction := range transactions {
+		modelTransactions[i] = game.Transaction{
+			ID:        int(transaction.ID),
+			UserID:    snowflake.MustParse(transaction.UserID),
+			Amount:    int(transaction.Amount),
+			Balance:   int(transaction.Balance),
+			Reason:    game.TransactionReason(transaction.Reason),
+			Reference: transaction.Reference,
+			CreatedAt: transaction.CreatedAt,
+		}
+	}
+
+	return modelTransactions, err
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"gorm.io/gorm"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+// GetGuildSettings returns a guild's settings, or nil if it never changed them.
+func (db *DB) GetGuildSettings(ctx context.Context, guildID snowflake.ID) (*game.GuildSettings, error) {
+	var settings GuildSettings
+	result := db.WithContext(ctx).First(&settings, "guild_id = ?", guildID.String())
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return dbGuildSettingsToModel(settings), nil
+}
+
+// SaveGuildSettings creates or replaces a guild's settings.
+func (db *DB) SaveGuildSettings(ctx context.Context, settings game.GuildSettings) error {
+	dbSettings := GuildSettings{
+		GuildID:           settings.GuildID.String(),
+		AllowedChannels:   snowflakesToStrings(settings.AllowedChannels),
+		BlockedChannels:   snowflakesToStrings(settings.BlockedChannels),
+		SpawnThresholdMin: int32(settings.SpawnThresholdMin),
+		SpawnThresholdMax: int32(settings.SpawnThresholdMax),
+		SpawnCooldown:     int64(settings.SpawnCooldown / time.Second),
+		ShinyMultiplier:   settings.ShinyMultiplier,
+		CatchByName:       settings.CatchByName,
+		MinMessageLength:  int32(settings.MinMessageLength),
+		BlockDuplicates:   settings.BlockDuplicates,
+		MessageRateLimit:  int32(settings.MessageRateLimit),
+		MaxUserSpawnShare: settings.MaxUserSpawnShare,
+		LevelUpNotify:     int32(settings.LevelUpNotify),
+	}
+	if settings.RedirectChannelID != 0 {
+		dbSettings.RedirectChannelID = settings.RedirectChannelID.String()
+	}
+	if settings.ModLogChannelID != 0 {
+		dbSettings.ModLogChannelID = settings.ModLogChannelID.String()
+	}
+	if settings.LevelUpChannelID != 0 {
+		dbSettings.LevelUpChannelID = settings.LevelUpChannelID.String()
+	}
+
+	return db.WithContext(ctx).Save(&dbSettings).Error
+}
+
+func dbGuildSettingsToModel(settings GuildSettings) *game.GuildSettings {
+	s := &game.GuildSettings{
+		GuildID:           snowflake.MustParse(settings.GuildID),
+		AllowedChannels:   stringsToSnowflakes(settings.AllowedChannels),
+		BlockedChannels:   stringsToSnowflakes(settings.BlockedChannels),
+		SpawnThresholdMin: int(settings.SpawnThresholdMin),
+		SpawnThresholdMax: int(settings.SpawnThresholdMax),
+		SpawnCooldown:     time.Duration(settings.SpawnCooldown) * time.Second,
+		ShinyMultiplier:   settings.ShinyMultiplier,
+		CatchByName:       settings.CatchByName,
+		MinMessageLength:  int(settings.MinMessageLength),
+		BlockDuplicates:   settings.BlockDuplicates,
+		MessageRateLimit:  int(settings.MessageRateLimit),
+		MaxUserSpawnShare: settings.MaxUserSpawnShare,
+		LevelUpNotify:     game.LevelUpNotify(settings.LevelUpNotify),
+	}
+	if settings.RedirectChannelID != "" {
+		s.RedirectChannelID = snowflake.MustParse(settings.RedirectChannelID)
+	}
+	if settings.ModLogChannelID != "" {
+		s.ModLogChannelID = snowflake.MustParse(settings.ModLogChannelID)
+	}
+	if settings.LevelUpChannelID != "" {
+		s.LevelUpChannelID = snowflake.MustParse(settings.LevelUpChannelID)
+	}
+
+	return s
+}
+
+func snowflakesToStrings(ids []snowflake.ID) []string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+	return strs
+}
+
+func stringsToSnowflakes(strs []string) []snowflake.ID {
+	ids := make([]snowflake.ID, 0, len(strs))
+	for _, s := range strs {
+		if id, err := snowflake.Parse(s); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
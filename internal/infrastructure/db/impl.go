@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/disgoorg/snowflake/v2"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/theoreotm/friemon/constants"
 	"github.com/theoreotm/friemon/internal/core/game"
@@ -15,6 +17,8 @@ import (
 
 var _ Store = (*DB)(nil)
 
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
 func (db *DB) DeleteEverything(ctx context.Context) error {
 	tx := db.WithContext(ctx)
 
@@ -26,9 +30,16 @@ func (db *DB) DeleteEverything(ctx context.Context) error {
 		return err
 	}
 
+	if err := tx.Delete(&DexEntry{}, "1=1").Error; err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// UpdateUser saves a user. The balance, daily streak and dex milestones are left
+// untouched, they only change through AdjustBalance, ClaimDaily and
+// ClaimDexMilestones so every reward is paid once.
 func (db *DB) UpdateUser(ctx context.Context, user game.User) (*game.User, error) {
 	dbUser := modelUserToDBUser(user)
 
@@ -40,6 +51,43 @@ func (db *DB) UpdateUser(ctx context.Context, user game.User) (*game.User, error
 	return dbUserToModelUser(dbUser), nil
 }
 
+// SetSelectedCharacter changes the user's selected character without touching
+// the rest of the user.
+func (db *DB) SetSelectedCharacter(ctx context.Context, userID snowflake.ID, characterID uuid.UUID) error {
+	return db.WithContext(ctx).Model(&User{}).Where("id = ?", userID.String()).Update("selected_id", characterID).Error
+}
+
+// UpdateRating stores a user's rating without touching the rest of the user.
+// lastRankedAt is left unchanged when nil.
+func (db *DB) UpdateRating(ctx context.Context, userID snowflake.ID, rating game.Rating, lastRankedAt *time.Time) error {
+	updates := map[string]interface{}{
+		"elo":               rating.Value(),
+		"rating_deviation":  rating.Deviation,
+		"rating_volatility": rating.Volatility,
+	}
+	if lastRankedAt != nil {
+		updates["last_ranked_at"] = *lastRankedAt
+	}
+
+	return db.WithContext(ctx).Model(&User{}).Where("id = ?", userID.String()).Updates(updates).Error
+}
+
+// GetInactiveRatedUsers returns users who have played ranked but not since the
+// given time, and whose rating deviation can still grow.
+func (db *DB) GetInactiveRatedUsers(ctx context.Context, since time.Time) ([]game.User, error) {
+	var users []User
+	err := db.WithContext(ctx).
+		Where("last_ranked_at < ? AND rating_deviation < ?", since, game.DefaultDeviation).
+		Find(&users).Error
+
+	modelUsers := make([]game.User, len(users))
+	for i, user := range users {
+		modelUsers[i] = *dbUserToModelUser(user)
+	}
+
+	return modelUsers, err
+}
+
 func (db *DB) GetSelectedCharacter(ctx context.Context, id snowflake.ID) (*game.Character, error) {
 	var user User
 	result := db.WithContext(ctx).Preload("SelectedCharacter").First(&user, "id = ?", id.String())
@@ -67,6 +115,9 @@ func (db *DB) CreateUser(ctx context.Context, id snowflake.ID) (*game.User, erro
 		ShiniesCaught: 0,
 		NextIdx:       1,
 		ELO:           1000, // Default ELO value
+
+		RatingDeviation:  game.DefaultDeviation,
+		RatingVolatility: game.DefaultVolatility,
 	}
 
 	result := db.WithContext(ctx).Create(&dbUser)
@@ -90,6 +141,21 @@ func (db *DB) GetUser(ctx context.Context, id snowflake.ID) (*game.User, error)
 	return dbUserToModelUser(user), nil
 }
 
+// LockUser returns a user and locks their row until the transaction ends, or
+// nil if there is none with the ID. Use it inside Tx.
+func (db *DB) LockUser(ctx context.Context, id snowflake.ID) (*game.User, error) {
+	var user User
+	result := db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", id.String())
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return dbUserToModelUser(user), nil
+}
+
 func (db *DB) EnsureUser(ctx context.Context, id snowflake.ID) (*game.User, error) {
 	var user User
 	result := db.WithContext(ctx).First(&user, "id = ?", id.String())
@@ -142,45 +208,68 @@ func (db *DB) GetCharacter(ctx context.Context, id uuid.UUID) (*game.Character,
 	return dbCharToModelChar(character), nil
 }
 
-func (db *DB) CreateCharacter(ctx context.Context, ownerID snowflake.ID, char *game.Character) (Character, error) {
-	// Get the user's next idx
-	var user User
-	result := db.WithContext(ctx).First(&user, "id = ?", ownerID.String())
+// LockCharacter returns a character and locks its row until the transaction
+// ends, or nil if there is none with the ID. Use it inside Tx.
+func (db *DB) LockCharacter(ctx context.Context, id uuid.UUID) (*game.Character, error) {
+	var character Character
+	result := db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&character, "id = ?", id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return Character{}, fmt.Errorf("user with ID %s not found", ownerID.String())
+			return nil, nil
 		}
-		return Character{}, result.Error
-	}
-
-	// Ensure NextIdx is valid
-	if user.NextIdx <= 0 {
-		return Character{}, fmt.Errorf("invalid NextIdx for user with ID %s", ownerID.String())
+		return nil, result.Error
 	}
 
-	// Assign IDX and OwnerID to the character
-	char.IDX = int(user.NextIdx)
-	char.OwnerID = ownerID.String()
+	return dbCharToModelChar(character), nil
+}
 
-	dbChar := modelCharToDBChar(char)
+// SaveCharacterProgress saves a character's level, XP and moves, leaving the
+// rest of it untouched.
+func (db *DB) SaveCharacterProgress(ctx context.Context, ch *game.Character) error {
+	dbChar := modelCharToDBChar(ch)
+	return db.WithContext(ctx).Model(&Character{}).
+		Where("id = ?", ch.ID).
+		Select("Level", "XP", "Moves").
+		Updates(&dbChar).Error
+}
 
-	// Start a transaction
-	tx := db.WithContext(ctx).Begin()
+// SaveCharacterStats saves the parts of a character items change: its level,
+// XP, moves, personality, IVs and held item. The rest is left untouched.
+func (db *DB) SaveCharacterStats(ctx context.Context, ch *game.Character) error {
+	dbChar := modelCharToDBChar(ch)
+	return db.WithContext(ctx).Model(&Character{}).
+		Where("id = ?", ch.ID).
+		Select("Level", "XP", "Moves", "Personality", "IvHP", "IvAtk", "IvDef", "IvSpAtk", "IvSpDef", "IvSpd", "IvTotal", "HeldItem").
+		Updates(&dbChar).Error
+}
 
-	// Create the character
-	if err := tx.Create(&dbChar).Error; err != nil {
-		tx.Rollback()
-		return Character{}, err
-	}
+func (db *DB) CreateCharacter(ctx context.Context, ownerID snowflake.ID, char *game.Character) (Character, error) {
+	var dbChar Character
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Take the owner's next idx atomically so concurrent catches never share one
+		var owner User
+		result := tx.Model(&owner).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "next_idx"}}}).
+			Where("id = ?", ownerID.String()).
+			Update("next_idx", gorm.Expr("next_idx + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("user with ID %s not found", ownerID.String())
+		}
 
-	fmt.Println(user.NextIdx)
+		char.IDX = int(owner.NextIdx - 1)
+		char.OwnerID = ownerID.String()
 
-	// Update user's next idx
-	user.NextIdx = user.NextIdx + 1
-	tx.Save(&user)
+		dbChar = modelCharToDBChar(char)
+		if err := tx.Create(&dbChar).Error; err != nil {
+			return err
+		}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
+		return (&DB{DB: tx}).RecordDexEntry(ctx, ownerID, char.CharacterID, char.Shiny)
+	})
+	if err != nil {
 		return Character{}, err
 	}
 
@@ -189,6 +278,13 @@ func (db *DB) CreateCharacter(ctx context.Context, ownerID snowflake.ID, char *g
 	return dbChar, nil
 }
 
+// IncrementShiniesCaught counts another shiny caught by a user.
+func (db *DB) IncrementShiniesCaught(ctx context.Context, userID snowflake.ID) error {
+	return db.WithContext(ctx).Model(&User{}).
+		Where("id = ?", userID.String()).
+		Update("shinies_caught", gorm.Expr("shinies_caught + 1")).Error
+}
+
 func (db *DB) GetCharactersForUser(ctx context.Context, userID snowflake.ID) ([]game.Character, error) {
 	var characters []Character
 	err := db.WithContext(ctx).Where("owner_id = ?", userID).Find(&characters).Error
@@ -212,6 +308,16 @@ func (db *DB) Tx(ctx context.Context, fn func(Store) error) error {
 func dbUserToModelUser(dbUser User) *game.User {
 	orderBy := game.OrderBy(dbUser.OrderBy)
 
+	var lastRankedAt time.Time
+	if dbUser.LastRankedAt != nil {
+		lastRankedAt = *dbUser.LastRankedAt
+	}
+
+	var lastDailyAt time.Time
+	if dbUser.LastDailyAt != nil {
+		lastDailyAt = *dbUser.LastDailyAt
+	}
+
 	return &game.User{
 		ID:         snowflake.MustParse(dbUser.ID),
 		Balance:    int(dbUser.Balance),
@@ -222,10 +328,33 @@ func dbUserToModelUser(dbUser User) *game.User {
 		},
 		NextIdx:       int(dbUser.NextIdx),
 		ShiniesCaught: int(dbUser.ShiniesCaught),
+		ELO:           int(dbUser.ELO),
+		BattlesWon:    int(dbUser.BattlesWon),
+		BattlesLost:   int(dbUser.BattlesLost),
+		JoinedAt:      dbUser.CreatedAt,
+
+		RatingDeviation:  dbUser.RatingDeviation,
+		RatingVolatility: dbUser.RatingVolatility,
+		LastRankedAt:     lastRankedAt,
+
+		DailyStreak: int(dbUser.DailyStreak),
+		LastDailyAt: lastDailyAt,
+
+		DexMilestones: int(dbUser.DexMilestones),
 	}
 }
 
 func modelUserToDBUser(user game.User) User {
+	var lastRankedAt *time.Time
+	if !user.LastRankedAt.IsZero() {
+		lastRankedAt = &user.LastRankedAt
+	}
+
+	var lastDailyAt *time.Time
+	if !user.LastDailyAt.IsZero() {
+		lastDailyAt = &user.LastDailyAt
+	}
+
 	return User{
 		ID:            user.ID.String(),
 		Balance:       int32(user.Balance),
@@ -234,6 +363,19 @@ func modelUserToDBUser(user game.User) User {
 		OrderDesc:     user.Order.Desc,
 		NextIdx:       int32(user.NextIdx),
 		ShiniesCaught: int32(user.ShiniesCaught),
+		ELO:           int32(user.ELO),
+		BattlesWon:    int32(user.BattlesWon),
+		BattlesLost:   int32(user.BattlesLost),
+		CreatedAt:     user.JoinedAt,
+
+		RatingDeviation:  user.RatingDeviation,
+		RatingVolatility: user.RatingVolatility,
+		LastRankedAt:     lastRankedAt,
+
+		DailyStreak: int32(user.DailyStreak),
+		LastDailyAt: lastDailyAt,
+
+		DexMilestones: int32(user.DexMilestones),
 	}
 }
 
@@ -257,6 +399,7 @@ func modelCharToDBChar(ch *game.Character) Character {
 		IvTotal:          ch.IvTotal,
 		Nickname:         ch.Nickname,
 		Favourite:        ch.Favourite,
+		Locked:           ch.Locked,
 		HeldItem:         int32(ch.HeldItem),
 		Moves:            ch.Moves,
 		Color:            ch.Color,
@@ -283,6 +426,7 @@ func dbCharToModelChar(dbch Character) *game.Character {
 		IvTotal:          dbch.IvTotal,
 		Nickname:         dbch.Nickname,
 		Favourite:        dbch.Favourite,
+		Locked:           dbch.Locked,
 		HeldItem:         int(dbch.HeldItem),
 		Moves:            dbch.Moves,
 		Color:            dbch.Color,
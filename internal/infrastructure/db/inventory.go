@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/disgoorg/snowflake/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+var ErrNotEnoughItems = errors.New("not enough items")
+
+// GetInventory returns the items a user has, ordered by item ID.
+func (db *DB) GetInventory(ctx context.Context, userID snowflake.ID) ([]game.InventoryItem, error) {
+	var items []InventoryItem
+	err := db.WithContext(ctx).
+		Where("user_id = ? AND quantity > 0", userID.String()).
+		Order("item_id ASC").
+		Find(&items).Error
+
+	modelItems := make([]game.InventoryItem, len(items))
+	for i, item := range items {
+		modelItems[i] = game.InventoryItem{ItemID: int(item.ItemID), Quantity: int(item.Quantity)}
+	}
+
+	return modelItems, err
+}
+
+// AddItem adds to the quantity of an item in a user's inventory and returns
+// the new quantity.
+func (db *DB) AddItem(ctx context.Context, userID snowflake.ID, itemID, quantity int) (int, error) {
+	item := InventoryItem{
+		UserID:   userID.String(),
+		ItemID:   int32(itemID),
+		Quantity: int32(quantity),
+	}
+
+	err := db.WithContext(ctx).
+		Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "user_id"}, {Name: "item_id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"quantity":   gorm.Expr("inventory_items.quantity + EXCLUDED.quantity"),
+					"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+				}),
+			},
+			clause.Returning{Columns: []clause.Column{{Name: "quantity"}}},
+		).
+		Create(&item).Error
+
+	return int(item.Quantity), err
+}
+
+// RemoveItem takes items out of a user's inventory. It fails with
+// ErrNotEnoughItems if the user has fewer than quantity.
+func (db *DB) RemoveItem(ctx context.Context, userID snowflake.ID, itemID, quantity int) error {
+	result := db.WithContext(ctx).Model(&InventoryItem{}).
+		Where("user_id = ? AND item_id = ? AND quantity >= ?", userID.String(), itemID, quantity).
+		Update("quantity", gorm.Expr("quantity - ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotEnoughItems
+	}
+
+	return nil
+}
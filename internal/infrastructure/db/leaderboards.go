@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+
+	"github.com/disgoorg/snowflake/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+type leaderboardStatsRow struct {
+	UserID           string
+	ELO              int32
+	RatingDeviation  float64
+	RatingVolatility float64
+	Ranked           bool
+	ShiniesCaught    int32
+	BattlesWon       int32
+	Characters       int64
+	TotalLevels      int64
+	BestIV           float64
+}
+
+// RecordBattleResult counts a battle toward the winner's and loser's records.
+func (db *DB) RecordBattleResult(ctx context.Context, winnerID, loserID snowflake.ID) error {
+	err := db.WithContext(ctx).Model(&User{}).
+		Where("id = ?", winnerID.String()).
+		Update("battles_won", gorm.Expr("battles_won + 1")).Error
+	if err != nil {
+		return err
+	}
+
+	return db.WithContext(ctx).Model(&User{}).
+		Where("id = ?", loserID.String()).
+		Update("battles_lost", gorm.Expr("battles_lost + 1")).Error
+}
+
+// AddGuildMember records that a user plays in a guild.
+func (db *DB) AddGuildMember(ctx context.Context, guildID, userID snowflake.ID) error {
+	return db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&GuildMember{
+		GuildID: guildID.String(),
+		UserID:  userID.String(),
+	}).Error
+}
+
+// GetGuildMemberships returns the guilds every user plays in, keyed by user ID.
+func (db *DB) GetGuildMemberships(ctx context.Context) (map[snowflake.ID][]snowflake.ID, error) {
+	var members []GuildMember
+	if err := db.WithContext(ctx).Find(&members).Error; err != nil {
+		return nil, err
+	}
+
+	memberships := make(map[snowflake.ID][]snowflake.ID)
+	for _, member := range members {
+		userID := snowflake.MustParse(member.UserID)
+		memberships[userID] = append(memberships[userID], snowflake.MustParse(member.GuildID))
+	}
+
+	return memberships, nil
+}
+
+// GetLeaderboardStats aggregates the leaderboard figures of the given users, or
+// of every user if none are given.
+func (db *DB) GetLeaderboardStats(ctx context.Context, userIDs ...snowflake.ID) ([]game.LeaderboardStats, error) {
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	characters := db.WithContext(ctx).Model(&Character{}).
+		Select("owner_id, COUNT(*) AS characters, SUM(level) AS total_levels, MAX(iv_total) AS best_iv").
+		Group("owner_id")
+	if len(ids) > 0 {
+		characters = characters.Where("owner_id IN ?", ids)
+	}
+
+	query := db.WithContext(ctx).Model(&User{}).
+		Select(`users.id AS user_id, users.elo, users.rating_deviation, users.rating_volatility,
+			users.last_ranked_at IS NOT NULL AS ranked, users.shinies_caught, users.battles_won,
+			COALESCE(c.characters, 0) AS characters, COALESCE(c.total_levels, 0) AS total_levels, COALESCE(c.best_iv, 0) AS best_iv`).
+		Joins("LEFT JOIN (?) AS c ON c.owner_id = users.id", characters)
+	if len(ids) > 0 {
+		query = query.Where("users.id IN ?", ids)
+	}
+
+	var rows []leaderboardStatsRow
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	stats := make([]game.LeaderboardStats, len(rows))
+	for i, row := range rows {
+		stats[i] = game.LeaderboardStats{
+			UserID: snowflake.MustParse(row.UserID),
+			Rating: game.Rating{
+				Rating:     float64(row.ELO),
+				Deviation:  row.RatingDeviation,
+				Volatility: row.RatingVolatility,
+			},
+			Ranked:      row.Ranked,
+			Characters:  int(row.Characters),
+			Shinies:     int(row.ShiniesCaught),
+			BestIV:      row.BestIV,
+			TotalLevels: int(row.TotalLevels),
+			BattlesWon:  int(row.BattlesWon),
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,180 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+var ErrCharacterLocked = errors.New("character is already listed")
+
+var marketOrderColumns = map[game.MarketOrder]string{
+	game.MarketOrderPriceAsc:  "market_listings.price ASC",
+	game.MarketOrderPriceDesc: "market_listings.price DESC",
+	game.MarketOrderNewest:    "market_listings.created_at DESC",
+	game.MarketOrderIV:        "characters.iv_total DESC",
+	game.MarketOrderLevel:     "characters.level DESC",
+}
+
+// CreateListing puts a character up for sale, locking it until the listing
+// is removed.
+func (db *DB) CreateListing(ctx context.Context, sellerID snowflake.ID, characterID uuid.UUID, price int, expiresAt time.Time) (*game.MarketListing, error) {
+	listing := MarketListing{
+		SellerID:    sellerID.String(),
+		CharacterID: characterID,
+		Price:       int32(price),
+		ExpiresAt:   expiresAt,
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Character{}).
+			Where("id = ? AND owner_id = ? AND NOT locked", characterID, sellerID.String()).
+			Update("locked", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCharacterLocked
+		}
+
+		return tx.Create(&listing).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetListing(ctx, int(listing.ID))
+}
+
+// GetListing returns a market listing with its character, or nil if it does not exist.
+func (db *DB) GetListing(ctx context.Context, id int) (*game.MarketListing, error) {
+	var listing MarketListing
+	result := db.WithContext(ctx).Preload("Character").First(&listing, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return dbListingToModelListing(listing), nil
+}
+
+// CountListings returns how many market listings match the filter.
+func (db *DB) CountListings(ctx context.Context, filter game.MarketFilter) (int, error) {
+	var count int64
+	err := db.filterListings(ctx, filter).Count(&count).Error
+	return int(count), err
+}
+
+// ListListings returns a page of market listings matching the filter.
+func (db *DB) ListListings(ctx context.Context, filter game.MarketFilter, offset, limit int) ([]game.MarketListing, error) {
+	order, ok := marketOrderColumns[filter.Order]
+	if !ok {
+		order = marketOrderColumns[game.MarketOrderPriceAsc]
+	}
+
+	var listings []MarketListing
+	err := db.filterListings(ctx, filter).
+		Select("market_listings.*").
+		Preload("Character").
+		Order(order).
+		Order("market_listings.id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&listings).Error
+
+	modelListings := make([]game.MarketListing, len(listings))
+	for i, listing := range listings {
+		modelListings[i] = *dbListingToModelListing(listing)
+	}
+
+	return modelListings, err
+}
+
+// DeleteListing takes a listing off the market and unlocks its character. It
+// returns nil if the listing was already gone, so only one caller can ever
+// remove a listing.
+func (db *DB) DeleteListing(ctx context.Context, id int) (*game.MarketListing, error) {
+	var listing MarketListing
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Returning{}).Where("id = ?", id).Delete(&listing)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			listing.ID = 0
+			return nil
+		}
+
+		return tx.Model(&Character{}).Where("id = ?", listing.CharacterID).Update("locked", false).Error
+	})
+	if err != nil || listing.ID == 0 {
+		return nil, err
+	}
+
+	var character Character
+	if err := db.WithContext(ctx).First(&character, "id = ?", listing.CharacterID).Error; err != nil {
+		return nil, err
+	}
+	listing.Character = &character
+
+	return dbListingToModelListing(listing), nil
+}
+
+// GetExpiredListings returns up to limit listings that expired before the given time.
+func (db *DB) GetExpiredListings(ctx context.Context, before time.Time, limit int) ([]game.MarketListing, error) {
+	var listings []MarketListing
+	err := db.WithContext(ctx).
+		Preload("Character").
+		Where("expires_at < ?", before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&listings).Error
+
+	modelListings := make([]game.MarketListing, len(listings))
+	for i, listing := range listings {
+		modelListings[i] = *dbListingToModelListing(listing)
+	}
+
+	return modelListings, err
+}
+
+func (db *DB) filterListings(ctx context.Context, filter game.MarketFilter) *gorm.DB {
+	query := db.WithContext(ctx).Model(&MarketListing{}).
+		Joins("JOIN characters ON characters.id = market_listings.character_id AND characters.deleted_at IS NULL")
+
+	if filter.SellerID != 0 {
+		query = query.Where("market_listings.seller_id = ?", filter.SellerID.String())
+	}
+	if filter.MinPrice > 0 {
+		query = query.Where("market_listings.price >= ?", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		query = query.Where("market_listings.price <= ?", filter.MaxPrice)
+	}
+
+	return applyCharacterFilter(query, filter.CharacterFilter)
+}
+
+func dbListingToModelListing(listing MarketListing) *game.MarketListing {
+	l := &game.MarketListing{
+		ID:        int(listing.ID),
+		SellerID:  snowflake.MustParse(listing.SellerID),
+		Price:     int(listing.Price),
+		CreatedAt: listing.CreatedAt,
+		ExpiresAt: listing.ExpiresAt,
+	}
+	if listing.Character != nil {
+		l.Character = *dbCharToModelChar(*listing.Character)
+	}
+
+	return l
+}
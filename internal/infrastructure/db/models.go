@@ -27,6 +27,7 @@ type Character struct {
 	IvTotal          float64   `gorm:"not null" json:"iv_total"`
 	Nickname         string    `gorm:"type:varchar(255);not null;default:''" json:"nickname"`
 	Favourite        bool      `gorm:"not null;default:false" json:"favourite"`
+	Locked           bool      `gorm:"not null;default:false" json:"locked"` // Held in escrow by a market listing or auction
 	HeldItem         int32     `gorm:"not null;default:-1" json:"held_item"`
 	Moves            []int32   `gorm:"type:integer[]" json:"moves"`
 	Color            int32     `gorm:"not null" json:"color"`
@@ -53,6 +54,17 @@ type User struct {
 	ShiniesCaught int32     `gorm:"not null;default:0" json:"shinies_caught"`
 	NextIdx       int32     `gorm:"not null;default:1" json:"next_idx"`
 	ELO           int32     `gorm:"not null;default:1000" json:"elo"`
+	BattlesWon    int32     `gorm:"not null;default:0" json:"battles_won"`
+	BattlesLost   int32     `gorm:"not null;default:0" json:"battles_lost"`
+
+	RatingDeviation  float64    `gorm:"not null;default:350" json:"rating_deviation"`
+	RatingVolatility float64    `gorm:"not null;default:0.06" json:"rating_volatility"`
+	LastRankedAt     *time.Time `gorm:"index" json:"last_ranked_at"`
+
+	DailyStreak int32      `gorm:"not null;default:0" json:"daily_streak"`
+	LastDailyAt *time.Time `json:"last_daily_at"`
+
+	DexMilestones int32 `gorm:"not null;default:0" json:"dex_milestones"` // How many of game.DexMilestones were rewarded
 
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -66,3 +78,201 @@ type User struct {
 func (User) TableName() string {
 	return "users"
 }
+
+type BattleReplay struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"` // Battle ID
+	ShortID    string    `gorm:"type:varchar(8);not null;index" json:"short_id"`
+	Player1ID  string    `gorm:"type:varchar(255);not null;index" json:"player1_id"`
+	Player2ID  string    `gorm:"type:varchar(255);not null;index" json:"player2_id"`
+	WinnerID   string    `gorm:"type:varchar(255);not null;default:''" json:"winner_id"`
+	Turns      int32     `gorm:"not null;default:0" json:"turns"`
+	Data       []byte    `gorm:"type:jsonb;not null" json:"data"` // Serialized game.BattleReplay
+	FinishedAt time.Time `gorm:"not null" json:"finished_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (BattleReplay) TableName() string {
+	return "battle_replays"
+}
+
+type Season struct {
+	ID        int32     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Number    int32     `gorm:"not null;uniqueIndex" json:"number"`
+	StartsAt  time.Time `gorm:"not null" json:"starts_at"`
+	EndsAt    time.Time `gorm:"not null" json:"ends_at"`
+	Active    bool      `gorm:"not null;default:true;index" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Season) TableName() string {
+	return "seasons"
+}
+
+type SeasonStanding struct {
+	SeasonID   int32     `gorm:"primaryKey" json:"season_id"`
+	UserID     string    `gorm:"type:varchar(255);primaryKey" json:"user_id"`
+	Rating     int32     `gorm:"not null;default:1000;index" json:"rating"`
+	PeakRating int32     `gorm:"not null;default:1000" json:"peak_rating"`
+	Wins       int32     `gorm:"not null;default:0" json:"wins"`
+	Losses     int32     `gorm:"not null;default:0" json:"losses"`
+	Draws      int32     `gorm:"not null;default:0" json:"draws"`
+	Rank       int32     `gorm:"not null;default:0" json:"rank"`
+	Bracket    int32     `gorm:"not null;default:0" json:"bracket"`
+	Rewarded   bool      `gorm:"not null;default:false" json:"rewarded"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (SeasonStanding) TableName() string {
+	return "season_standings"
+}
+
+type Tournament struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	ShortID   string    `gorm:"type:varchar(8);not null;index" json:"short_id"`
+	GuildID   string    `gorm:"type:varchar(255);not null;index" json:"guild_id"`
+	ChannelID string    `gorm:"type:varchar(255);not null;index" json:"channel_id"`
+	ThreadID  string    `gorm:"type:varchar(255);not null;index" json:"thread_id"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	State     int32     `gorm:"not null;default:0;index" json:"state"`
+	WinnerID  string    `gorm:"type:varchar(255);not null;default:'';index" json:"winner_id"`
+	Data      []byte    `gorm:"type:jsonb;not null" json:"data"` // Serialized game.Tournament
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Tournament) TableName() string {
+	return "tournaments"
+}
+
+type MarketListing struct {
+	ID          int32      `gorm:"primaryKey;autoIncrement" json:"id"`
+	SellerID    string     `gorm:"type:varchar(255);not null;index" json:"seller_id"`
+	CharacterID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"character_id"`
+	Price       int32      `gorm:"not null;index" json:"price"`
+	ExpiresAt   time.Time  `gorm:"not null;index" json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Character   *Character `gorm:"foreignKey:CharacterID;references:ID" json:"character,omitempty"`
+}
+
+func (MarketListing) TableName() string {
+	return "market_listings"
+}
+
+type Auction struct {
+	ID          int32      `gorm:"primaryKey;autoIncrement" json:"id"`
+	SellerID    string     `gorm:"type:varchar(255);not null;index" json:"seller_id"`
+	CharacterID uuid.UUID  `gorm:"type:uuid;not null;index" json:"character_id"`
+	ChannelID   string     `gorm:"type:varchar(255);not null" json:"channel_id"`
+	MessageID   string     `gorm:"type:varchar(255);not null;default:''" json:"message_id"`
+	StartingBid int32      `gorm:"not null" json:"starting_bid"`
+	CurrentBid  int32      `gorm:"not null;default:0" json:"current_bid"`
+	BidderID    string     `gorm:"type:varchar(255);not null;default:''" json:"bidder_id"`
+	Bids        int32      `gorm:"not null;default:0" json:"bids"`
+	State       int32      `gorm:"not null;default:0;index" json:"state"`
+	EndsAt      time.Time  `gorm:"not null;index" json:"ends_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Character   *Character `gorm:"foreignKey:CharacterID;references:ID" json:"character,omitempty"`
+}
+
+func (Auction) TableName() string {
+	return "auctions"
+}
+
+// Transaction is a ledger entry for one change to a user's balance.
+type Transaction struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    string    `gorm:"type:varchar(255);not null;index:idx_transactions_user_created" json:"user_id"`
+	Amount    int32     `gorm:"not null" json:"amount"`
+	Balance   int32     `gorm:"not null" json:"balance"`
+	Reason    string    `gorm:"type:varchar(32);not null;index" json:"reason"`
+	Reference string    `gorm:"type:varchar(255);not null;default:''" json:"reference"`
+	CreatedAt time.Time `gorm:"index:idx_transactions_user_created" json:"created_at"`
+}
+
+func (Transaction) TableName() string {
+	return "transactions"
+}
+
+type InventoryItem struct {
+	UserID    string    `gorm:"type:varchar(255);primaryKey" json:"user_id"`
+	ItemID    int32     `gorm:"primaryKey" json:"item_id"`
+	Quantity  int32     `gorm:"not null;default:0" json:"quantity"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (InventoryItem) TableName() string {
+	return "inventory_items"
+}
+
+// GuildSettings configure spawning in a guild. Guilds without a row use the defaults.
+type GuildSettings struct {
+	GuildID           string    `gorm:"type:varchar(255);primaryKey" json:"guild_id"`
+	AllowedChannels   []string  `gorm:"type:jsonb;serializer:json" json:"allowed_channels"`
+	BlockedChannels   []string  `gorm:"type:jsonb;serializer:json" json:"blocked_channels"`
+	RedirectChannelID string    `gorm:"type:varchar(255);not null;default:''" json:"redirect_channel_id"`
+	SpawnThresholdMin int32     `gorm:"not null" json:"spawn_threshold_min"`
+	SpawnThresholdMax int32     `gorm:"not null" json:"spawn_threshold_max"`
+	SpawnCooldown     int64     `gorm:"not null;default:0" json:"spawn_cooldown"` // Seconds
+	ShinyMultiplier   float64   `gorm:"not null;default:1" json:"shiny_multiplier"`
+	CatchByName       bool      `gorm:"not null;default:false" json:"catch_by_name"`
+	MinMessageLength  int32     `gorm:"not null;default:3" json:"min_message_length"`
+	BlockDuplicates   bool      `gorm:"not null;default:true" json:"block_duplicates"`
+	MessageRateLimit  int32     `gorm:"not null;default:8" json:"message_rate_limit"`
+	MaxUserSpawnShare float64   `gorm:"not null;default:0.5" json:"max_user_spawn_share"`
+	ModLogChannelID   string    `gorm:"type:varchar(255);not null;default:''" json:"mod_log_channel_id"`
+	LevelUpNotify     int32     `gorm:"not null;default:0" json:"level_up_notify"`
+	LevelUpChannelID  string    `gorm:"type:varchar(255);not null;default:''" json:"level_up_channel_id"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func (GuildSettings) TableName() string {
+	return "guild_settings"
+}
+
+// SpawnEvent changes spawns in every guild between its start and end.
+type SpawnEvent struct {
+	ID                int32     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name              string    `gorm:"type:varchar(100);not null" json:"name"`
+	BoostedCharacters []int     `gorm:"type:jsonb;serializer:json" json:"boosted_characters"`
+	BoostMultiplier   float64   `gorm:"not null;default:1" json:"boost_multiplier"`
+	ShinyMultiplier   float64   `gorm:"not null;default:1" json:"shiny_multiplier"`
+	MinIV             int32     `gorm:"not null;default:0" json:"min_iv"`
+	XPMultiplier      float64   `gorm:"not null;default:1" json:"xp_multiplier"`
+	StartsAt          time.Time `gorm:"not null;index" json:"starts_at"`
+	EndsAt            time.Time `gorm:"not null;index" json:"ends_at"`
+	RecurEvery        int64     `gorm:"not null;default:0" json:"recur_every"` // Seconds
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func (SpawnEvent) TableName() string {
+	return "spawn_events"
+}
+
+// GuildMember records that a user plays in a guild, for per guild leaderboards.
+type GuildMember struct {
+	GuildID   string    `gorm:"type:varchar(255);primaryKey" json:"guild_id"`
+	UserID    string    `gorm:"type:varchar(255);primaryKey;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (GuildMember) TableName() string {
+	return "guild_members"
+}
+
+// DexEntry counts how many of one species a user has caught.
+type DexEntry struct {
+	UserID        string    `gorm:"type:varchar(255);primaryKey" json:"user_id"`
+	CharacterID   int32     `gorm:"primaryKey" json:"character_id"`
+	Caught        int32     `gorm:"not null;default:0" json:"caught"`
+	Shinies       int32     `gorm:"not null;default:0" json:"shinies"`
+	FirstCaughtAt time.Time `gorm:"not null" json:"first_caught_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (DexEntry) TableName() string {
+	return "dex_entries"
+}
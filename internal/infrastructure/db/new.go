@@ -90,11 +90,18 @@ func (db *DB) Close() error {
 }
 
 func (db *DB) AutoMigrate() error {
+	hasDex := db.DB.Migrator().HasTable(&DexEntry{})
 
-	err := db.DB.AutoMigrate(&User{}, &Character{})
+	err := db.DB.AutoMigrate(&User{}, &Character{}, &BattleReplay{}, &Season{}, &SeasonStanding{}, &Tournament{}, &GuildMember{}, &MarketListing{}, &Auction{}, &Transaction{}, &InventoryItem{}, &GuildSettings{}, &SpawnEvent{}, &DexEntry{})
 	if err != nil {
 		return err
 	}
 
+	if !hasDex {
+		if err := db.backfillDex(); err != nil {
+			return fmt.Errorf("failed to backfill dex: %w", err)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+// GetCollectionStats counts a user's characters without loading them.
+func (db *DB) GetCollectionStats(ctx context.Context, userID snowflake.ID) (*game.CollectionStats, error) {
+	var row struct {
+		Characters  int64
+		Favourites  int64
+		Shinies     int64
+		TotalLevels int64
+	}
+
+	err := db.WithContext(ctx).Model(&Character{}).
+		Select(`COUNT(*) AS characters,
+			COUNT(*) FILTER (WHERE favourite) AS favourites,
+			COUNT(*) FILTER (WHERE shiny) AS shinies,
+			COALESCE(SUM(level), 0) AS total_levels`).
+		Where("owner_id = ?", userID.String()).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &game.CollectionStats{
+		Characters:  int(row.Characters),
+		Favourites:  int(row.Favourites),
+		Shinies:     int(row.Shinies),
+		TotalLevels: int(row.TotalLevels),
+	}, nil
+}
+
+// GetUserBadges returns the badges a user has earned from past seasons and
+// tournament wins, oldest first.
+func (db *DB) GetUserBadges(ctx context.Context, userID snowflake.ID) ([]game.Badge, error) {
+	var seasons []struct {
+		SeasonStanding
+		Number int32
+	}
+
+	err := db.WithContext(ctx).Model(&SeasonStanding{}).
+		Select("season_standings.*, seasons.number").
+		Joins("JOIN seasons ON seasons.id = season_standings.season_id").
+		Where("season_standings.user_id = ? AND seasons.active = ?", userID.String(), false).
+		Order("seasons.number").
+		Scan(&seasons).Error
+	if err != nil {
+		return nil, err
+	}
+
+	badges := make([]game.Badge, 0)
+	for _, season := range seasons {
+		// The same rule as the season rewards, including the minimum games
+		standing := dbStandingToModelStanding(season.SeasonStanding)
+		if standing.Reward().Badge {
+			badges = append(badges, game.SeasonBadge(int(season.Number), standing.Bracket))
+		}
+	}
+
+	var tournaments []Tournament
+	err = db.WithContext(ctx).
+		Select("name").
+		Where("winner_id = ?", userID.String()).
+		Order("created_at").
+		Find(&tournaments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tournament := range tournaments {
+		badges = append(badges, game.TournamentBadge(tournament.Name))
+	}
+
+	return badges, nil
+}
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/disgoorg/snowflake/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+// GetActiveSeason returns the running season, or nil if there is none.
+func (db *DB) GetActiveSeason(ctx context.Context) (*game.Season, error) {
+	var season Season
+	result := db.WithContext(ctx).Where("active = ?", true).Order("number DESC").First(&season)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return dbSeasonToModelSeason(season), nil
+}
+
+func (db *DB) CreateSeason(ctx context.Context, season game.Season) (*game.Season, error) {
+	dbSeason := Season{
+		Number:   int32(season.Number),
+		StartsAt: season.StartsAt,
+		EndsAt:   season.EndsAt,
+		Active:   season.Active,
+	}
+
+	if err := db.WithContext(ctx).Create(&dbSeason).Error; err != nil {
+		return nil, err
+	}
+
+	return dbSeasonToModelSeason(dbSeason), nil
+}
+
+// RecordSeasonResult stores a player's rating after a ranked battle and
+// counts the result toward their season record.
+func (db *DB) RecordSeasonResult(ctx context.Context, seasonID int, userID snowflake.ID, rating int, result game.SeasonResult) error {
+	standing := SeasonStanding{
+		SeasonID:   int32(seasonID),
+		UserID:     userID.String(),
+		Rating:     int32(rating),
+		PeakRating: int32(rating),
+	}
+
+	counter := "wins"
+	switch result {
+	case game.SeasonResultWin:
+		standing.Wins = 1
+	case game.SeasonResultLoss:
+		standing.Losses = 1
+		counter = "losses"
+	case game.SeasonResultDraw:
+		standing.Draws = 1
+		counter = "draws"
+	}
+
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "season_id"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"rating":      rating,
+			"peak_rating": gorm.Expr("GREATEST(season_standings.peak_rating, ?)", rating),
+			counter:       gorm.Expr("season_standings." + counter + " + 1"),
+			"updated_at":  gorm.Expr("NOW()"),
+		}),
+	}).Create(&standing).Error
+}
+
+// GetSeasonStanding returns a player's record in a season, or nil if they have
+// not played a ranked battle in it.
+func (db *DB) GetSeasonStanding(ctx context.Context, seasonID int, userID snowflake.ID) (*game.SeasonStanding, error) {
+	var standing SeasonStanding
+	result := db.WithContext(ctx).First(&standing, "season_id = ? AND user_id = ?", seasonID, userID.String())
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return dbStandingToModelStanding(standing), nil
+}
+
+// GetSeasonRank returns the player's current position in the season.
+func (db *DB) GetSeasonRank(ctx context.Context, seasonID int, rating int) (int, error) {
+	var higher int64
+	err := db.WithContext(ctx).Model(&SeasonStanding{}).
+		Where("season_id = ? AND rating > ?", seasonID, rating).
+		Count(&higher).Error
+	return int(higher) + 1, err
+}
+
+func (db *DB) GetSeasonStandings(ctx context.Context, seasonID int) ([]game.SeasonStanding, error) {
+	var standings []SeasonStanding
+	err := db.WithContext(ctx).
+		Where("season_id = ?", seasonID).
+		Order("rating DESC").
+		Find(&standings).Error
+
+	modelStandings := make([]game.SeasonStanding, len(standings))
+	for i, standing := range standings {
+		modelStandings[i] = *dbStandingToModelStanding(standing)
+	}
+
+	return modelStandings, err
+}
+
+// ArchiveSeason ends a season and stores the final rank and bracket of every
+// ranked standing.
+func (db *DB) ArchiveSeason(ctx context.Context, seasonID int, standings []game.SeasonStanding) error {
+	tx := db.WithContext(ctx)
+
+	for _, standing := range standings {
+		err := tx.Model(&SeasonStanding{}).
+			Where("season_id = ? AND user_id = ?", seasonID, standing.UserID.String()).
+			Updates(map[string]interface{}{
+				"rank":    standing.Rank,
+				"bracket": int32(standing.Bracket),
+			}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&Season{}).Where("id = ?", seasonID).Update("active", false).Error
+}
+
+// SoftResetRatings pulls every user's rating halfway back toward the default.
+func (db *DB) SoftResetRatings(ctx context.Context) error {
+	return db.WithContext(ctx).Model(&User{}).
+		Where("elo <> ?", game.DefaultRating).
+		Update("elo", gorm.Expr("? + (elo - ?) / 2", game.DefaultRating, game.DefaultRating)).Error
+}
+
+// GetUnrewardedStandings returns archived standings whose rewards have not been granted yet.
+func (db *DB) GetUnrewardedStandings(ctx context.Context) ([]game.SeasonStanding, error) {
+	var standings []SeasonStanding
+	err := db.WithContext(ctx).
+		Joins("JOIN seasons ON seasons.id = season_standings.season_id").
+		Where("seasons.active = ? AND season_standings.rewarded = ?", false, false).
+		Find(&standings).Error
+
+	modelStandings := make([]game.SeasonStanding, len(standings))
+	for i, standing := range standings {
+		modelStandings[i] = *dbStandingToModelStanding(standing)
+	}
+
+	return modelStandings, err
+}
+
+// MarkStandingRewarded marks a standing's reward as paid. It reports false if
+// it was marked already, so the reward is only paid once.
+func (db *DB) MarkStandingRewarded(ctx context.Context, seasonID int, userID snowflake.ID) (bool, error) {
+	result := db.WithContext(ctx).Model(&SeasonStanding{}).
+		Where("season_id = ? AND user_id = ? AND rewarded = ?", seasonID, userID.String(), false).
+		Update("rewarded", true)
+	return result.RowsAffected > 0, result.Error
+}
+
+func dbSeasonToModelSeason(season Season) *game.Season {
+	return &game.Season{
+		ID:       int(season.ID),
+		Number:   int(season.Number),
+		StartsAt: season.StartsAt,
+		EndsAt:   season.EndsAt,
+		Active:   season.Active,
+	}
+}
+
+func dbStandingToModelStanding(standing SeasonStanding) *game.SeasonStanding {
+	return &game.SeasonStanding{
+		SeasonID:   int(standing.SeasonID),
+		UserID:     snowflake.MustParse(standing.UserID),
+		Rating:     int(standing.Rating),
+		PeakRating: int(standing.PeakRating),
+		Wins:       int(standing.Wins),
+		Losses:     int(standing.Losses),
+		Draws:      int(standing.Draws),
+		Rank:       int(standing.Rank),
+		Bracket:    game.Bracket(standing.Bracket),
+		Rewarded:   standing.Rewarded,
+	}
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+// CreateSpawnEvent saves a new spawn event.
+func (db *DB) CreateSpawnEvent(ctx context.Context, event game.SpawnEvent) (*game.SpawnEvent, error) {
+	dbEvent := modelSpawnEventToDBSpawnEvent(event)
+	if err := db.WithContext(ctx).Create(&dbEvent).Error; err != nil {
+		return nil, err
+	}
+
+	return dbSpawnEventToModel(dbEvent), nil
+}
+
+// GetSpawnEvent returns a spawn event, or nil if there is none with the ID.
+func (db *DB) GetSpawnEvent(ctx context.Context, id int) (*game.SpawnEvent, error) {
+	var event SpawnEvent
+	result := db.WithContext(ctx).First(&event, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return dbSpawnEventToModel(event), nil
+}
+
+// GetSpawnEvents returns every spawn event that has not ended by the given
+// time, running or upcoming, soonest first.
+func (db *DB) GetSpawnEvents(ctx context.Context, since time.Time) ([]game.SpawnEvent, error) {
+	var events []SpawnEvent
+	err := db.WithContext(ctx).
+		Where("ends_at > ?", since).
+		Order("starts_at ASC").
+		Find(&events).Error
+
+	modelEvents := make([]game.SpawnEvent, len(events))
+	for i, event := range events {
+		modelEvents[i] = *dbSpawnEventToModel(event)
+	}
+
+	return modelEvents, err
+}
+
+// RescheduleSpawnEvent saves a spawn event's new start and end.
+func (db *DB) RescheduleSpawnEvent(ctx context.Context, event *game.SpawnEvent) error {
+	return db.WithContext(ctx).Model(&SpawnEvent{}).
+		Where("id = ?", event.ID).
+		Updates(map[string]interface{}{
+			"starts_at": event.StartsAt,
+			"ends_at":   event.EndsAt,
+		}).Error
+}
+
+// DeleteSpawnEvent removes a spawn event and reports whether it existed.
+func (db *DB) DeleteSpawnEvent(ctx context.Context, id int) (bool, error) {
+	result := db.WithContext(ctx).Delete(&SpawnEvent{}, "id = ?", id)
+	return result.RowsAffected > 0, result.Error
+}
+
+func modelSpawnEventToDBSpawnEvent(event game.SpawnEvent) SpawnEvent {
+	return SpawnEvent{
+		ID:                int32(event.ID),
+		Name:              event.Name,
+		BoostedCharacters: event.BoostedCharacters,
+		BoostMultiplier:   event.BoostMultiplier,
+		ShinyMultiplier:   event.ShinyMultiplier,
+		MinIV:             int32(event.MinIV),
+		XPMultiplier:      event.XPMultiplier,
+		StartsAt:          event.StartsAt,
+		EndsAt:            event.EndsAt,
+		RecurEvery:        int64(event.RecurEvery / time.Second),
+	}
+}
+
+func dbSpawnEventToModel(event SpawnEvent) *game.SpawnEvent {
+	return &game.SpawnEvent{
+		ID:                int(event.ID),
+		Name:              event.Name,
+		BoostedCharacters: event.BoostedCharacters,
+		BoostMultiplier:   event.BoostMultiplier,
+		ShinyMultiplier:   event.ShinyMultiplier,
+		MinIV:             int(event.MinIV),
+		XPMultiplier:      event.XPMultiplier,
+		StartsAt:          event.StartsAt,
+		EndsAt:            event.EndsAt,
+		RecurEvery:        time.Duration(event.RecurEvery) * time.Second,
+	}
+}
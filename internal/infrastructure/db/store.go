@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/disgoorg/snowflake/v2"
 	"github.com/google/uuid"
@@ -11,16 +12,106 @@ import (
 type Store interface {
 	// Character operations
 	GetCharactersForUser(context.Context, snowflake.ID) ([]game.Character, error)
+	CountCharacters(context.Context, snowflake.ID, game.CharacterFilter) (int, error)
+	ListCharacters(context.Context, snowflake.ID, game.CharacterFilter, game.OrderOptions, int, int) ([]game.Character, error)
 	GetCharacter(context.Context, uuid.UUID) (*game.Character, error)
 	CreateCharacter(context.Context, snowflake.ID, *game.Character) (Character, error)
+	IncrementShiniesCaught(context.Context, snowflake.ID) error
 	UpdateCharacter(context.Context, uuid.UUID, *game.Character) (*game.Character, error)
+	LockCharacter(context.Context, uuid.UUID) (*game.Character, error)
+	SaveCharacterProgress(context.Context, *game.Character) error
+	SaveCharacterStats(context.Context, *game.Character) error
 	DeleteCharacter(context.Context, uuid.UUID) (*game.Character, error)
+	SetNickname(context.Context, uuid.UUID, string) error
+	SetFavourite(context.Context, snowflake.ID, game.CharacterFilter, bool) (int, error)
+	SetCharacterFavourite(context.Context, uuid.UUID, bool) error
+	TransferCharacter(context.Context, uuid.UUID, snowflake.ID) error
 
 	// User operations
 	GetUser(context.Context, snowflake.ID) (*game.User, error)
+	LockUser(context.Context, snowflake.ID) (*game.User, error)
 	UpdateUser(context.Context, game.User) (*game.User, error)
+	SetSelectedCharacter(context.Context, snowflake.ID, uuid.UUID) error
 	CreateUser(context.Context, snowflake.ID) (*game.User, error)
 	GetSelectedCharacter(context.Context, snowflake.ID) (*game.Character, error)
+	UpdateOrder(context.Context, snowflake.ID, game.OrderOptions) error
+	UpdateRating(context.Context, snowflake.ID, game.Rating, *time.Time) error
+	GetInactiveRatedUsers(context.Context, time.Time) ([]game.User, error)
+
+	// Economy operations
+	AdjustBalance(context.Context, snowflake.ID, int, game.TransactionReason, string) (int, error)
+	ClaimDaily(context.Context, snowflake.ID, time.Time, int, time.Time) (bool, error)
+	CountTransactions(context.Context, snowflake.ID, game.TransactionReason, time.Time) (int, error)
+	GetTransactions(context.Context, snowflake.ID, int) ([]game.Transaction, error)
+
+	// Inventory operations
+	GetInventory(context.Context, snowflake.ID) ([]game.InventoryItem, error)
+	AddItem(context.Context, snowflake.ID, int, int) (int, error)
+	RemoveItem(context.Context, snowflake.ID, int, int) error
+
+	// Battle replay operations
+	CreateBattleReplay(context.Context, *game.BattleReplay) error
+	GetBattleReplay(context.Context, string) (*game.BattleReplay, error)
+
+	// Season operations
+	GetActiveSeason(context.Context) (*game.Season, error)
+	CreateSeason(context.Context, game.Season) (*game.Season, error)
+	RecordSeasonResult(context.Context, int, snowflake.ID, int, game.SeasonResult) error
+	GetSeasonStanding(context.Context, int, snowflake.ID) (*game.SeasonStanding, error)
+	GetSeasonRank(context.Context, int, int) (int, error)
+	GetSeasonStandings(context.Context, int) ([]game.SeasonStanding, error)
+	ArchiveSeason(context.Context, int, []game.SeasonStanding) error
+	SoftResetRatings(context.Context) error
+	GetUnrewardedStandings(context.Context) ([]game.SeasonStanding, error)
+	MarkStandingRewarded(context.Context, int, snowflake.ID) (bool, error)
+
+	// Tournament operations
+	SaveTournament(context.Context, *game.Tournament) error
+	GetTournament(context.Context, uuid.UUID) (*game.Tournament, error)
+	LockTournament(context.Context, uuid.UUID) (*game.Tournament, error)
+	GetChannelTournament(context.Context, snowflake.ID) (*game.Tournament, error)
+
+	// Market operations
+	CreateListing(context.Context, snowflake.ID, uuid.UUID, int, time.Time) (*game.MarketListing, error)
+	GetListing(context.Context, int) (*game.MarketListing, error)
+	CountListings(context.Context, game.MarketFilter) (int, error)
+	ListListings(context.Context, game.MarketFilter, int, int) ([]game.MarketListing, error)
+	DeleteListing(context.Context, int) (*game.MarketListing, error)
+	GetExpiredListings(context.Context, time.Time, int) ([]game.MarketListing, error)
+
+	// Auction operations
+	CreateAuction(context.Context, game.Auction) (*game.Auction, error)
+	GetAuction(context.Context, int) (*game.Auction, error)
+	GetActiveAuctions(context.Context) ([]game.Auction, error)
+	SetAuctionMessage(context.Context, int, snowflake.ID) error
+	UpdateAuctionBid(context.Context, *game.Auction) error
+	FinishAuction(context.Context, *game.Auction, game.AuctionState) (bool, error)
+
+	// Guild settings operations
+	GetGuildSettings(context.Context, snowflake.ID) (*game.GuildSettings, error)
+	SaveGuildSettings(context.Context, game.GuildSettings) error
+
+	// Spawn event operations
+	CreateSpawnEvent(context.Context, game.SpawnEvent) (*game.SpawnEvent, error)
+	GetSpawnEvent(context.Context, int) (*game.SpawnEvent, error)
+	GetSpawnEvents(context.Context, time.Time) ([]game.SpawnEvent, error)
+	RescheduleSpawnEvent(context.Context, *game.SpawnEvent) error
+	DeleteSpawnEvent(context.Context, int) (bool, error)
+
+	// Leaderboard operations
+	RecordBattleResult(context.Context, snowflake.ID, snowflake.ID) error
+	AddGuildMember(context.Context, snowflake.ID, snowflake.ID) error
+	GetGuildMemberships(context.Context) (map[snowflake.ID][]snowflake.ID, error)
+	GetLeaderboardStats(context.Context, ...snowflake.ID) ([]game.LeaderboardStats, error)
+
+	// Profile operations
+	GetCollectionStats(context.Context, snowflake.ID) (*game.CollectionStats, error)
+	GetUserBadges(context.Context, snowflake.ID) ([]game.Badge, error)
+
+	// Dex operations
+	RecordDexEntry(context.Context, snowflake.ID, int, bool) error
+	GetDex(context.Context, snowflake.ID) (game.Dex, error)
+	ClaimDexMilestones(context.Context, snowflake.ID, int, int) (bool, error)
 
 	// Utility operations
 	DeleteEverything(context.Context) error
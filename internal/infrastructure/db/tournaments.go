@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+// SaveTournament creates or updates a tournament.
+func (db *DB) SaveTournament(ctx context.Context, tournament *game.Tournament) error {
+	data, err := json.Marshal(tournament)
+	if err != nil {
+		return fmt.Errorf("failed to encode tournament: %w", err)
+	}
+
+	winnerID := ""
+	if tournament.Winner != nil {
+		winnerID = tournament.Winner.String()
+	}
+
+	dbTournament := Tournament{
+		ID:        tournament.ID,
+		ShortID:   tournament.ShortID(),
+		GuildID:   tournament.GuildID.String(),
+		ChannelID: tournament.ChannelID.String(),
+		ThreadID:  tournament.ThreadID.String(),
+		Name:      tournament.Name,
+		State:     int32(tournament.State),
+		WinnerID:  winnerID,
+		Data:      data,
+		CreatedAt: tournament.CreatedAt,
+	}
+
+	return db.WithContext(ctx).Save(&dbTournament).Error
+}
+
+// GetTournament returns a tournament, or nil if it does not exist.
+func (db *DB) GetTournament(ctx context.Context, id uuid.UUID) (*game.Tournament, error) {
+	var dbTournament Tournament
+	result := db.WithContext(ctx).First(&dbTournament, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return dbTournamentToModelTournament(dbTournament)
+}
+
+// LockTournament returns a tournament and locks its row until the transaction
+// ends, or nil if it does not exist. Use it inside Tx.
+func (db *DB) LockTournament(ctx context.Context, id uuid.UUID) (*game.Tournament, error) {
+	var dbTournament Tournament
+	result := db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&dbTournament, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return dbTournamentToModelTournament(dbTournament)
+}
+
+// GetChannelTournament returns the most recent tournament run in a channel or
+// its bracket thread, or nil if there has never been one.
+func (db *DB) GetChannelTournament(ctx context.Context, channelID snowflake.ID) (*game.Tournament, error) {
+	var dbTournament Tournament
+	result := db.WithContext(ctx).
+		Where("channel_id = ? OR thread_id = ?", channelID.String(), channelID.String()).
+		Order("created_at DESC").
+		First(&dbTournament)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return dbTournamentToModelTournament(dbTournament)
+}
+
+func dbTournamentToModelTournament(dbTournament Tournament) (*game.Tournament, error) {
+	var tournament game.Tournament
+	if err := json.Unmarshal(dbTournament.Data, &tournament); err != nil {
+		return nil, fmt.Errorf("failed to decode tournament: %w", err)
+	}
+
+	return &tournament, nil
+}
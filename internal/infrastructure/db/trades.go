@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TransferCharacter gives a character to a new owner, numbering it after the
+// rest of their collection. The old owner's selection is cleared if it was
+// the transferred character.
+func (db *DB) TransferCharacter(ctx context.Context, characterID uuid.UUID, newOwnerID snowflake.ID) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var character Character
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&character, "id = ?", characterID).Error
+		if err != nil {
+			return err
+		}
+
+		var receiver User
+		result := tx.Model(&receiver).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "next_idx"}}}).
+			Where("id = ?", newOwnerID.String()).
+			Update("next_idx", gorm.Expr("next_idx + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("user with ID %s not found", newOwnerID.String())
+		}
+
+		err = tx.Model(&User{}).
+			Where("id = ? AND selected_id = ?", character.OwnerID, characterID).
+			Update("selected_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&character).Updates(map[string]interface{}{
+			"owner_id": newOwnerID.String(),
+			"idx":      receiver.NextIdx - 1,
+		}).Error
+	})
+}
@@ -4,9 +4,13 @@ import (
 	"time"
 
 	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
 	"github.com/theoreotm/friemon/internal/core/game"
 )
 
+// spawnCacheTTL outlives a spawn, so the spawn is still cached when it expires.
+const spawnCacheTTL = game.SpawnLifetime + time.Minute
+
 type Cache interface {
 	Set(key string, value interface{}, ttl time.Duration) error
 	Get(key string) (interface{}, error)
@@ -15,8 +19,43 @@ type Cache interface {
 	IncrementInteractionCount(channelId snowflake.ID) error
 	GetInteractionCount(channelId snowflake.ID) int
 	ResetInteractionCount(channelId snowflake.ID) error
+	IncrementSpawnContribution(channelID, userID snowflake.ID) (count int, contributors int, err error)
+
+	IncrementMessageRate(guildID, userID snowflake.ID, window time.Duration) (int, error)
+	SwapLastMessage(userID snowflake.ID, fingerprint string, ttl time.Duration) (string, error)
+	MarkSpamReported(guildID, userID snowflake.ID, reason game.SpamReason, ttl time.Duration) (bool, error)
 
-	SetChannelCharacter(channelID snowflake.ID, character *game.Character) error
+	SetChannelCharacter(channelID snowflake.ID, character *game.Character) (bool, error)
 	GetChannelCharacter(channelID snowflake.ID) (*game.Character, error)
 	DeleteChannelCharacter(channelID snowflake.ID) error
+	TakeChannelCharacter(channelID snowflake.ID, spawnID uuid.UUID) (*game.Character, error)
+	SetSpawnMessage(spawnID uuid.UUID, messageID snowflake.ID) error
+	GetSpawnMessage(spawnID uuid.UUID) (snowflake.ID, error)
+
+	SetChannelLure(channelID snowflake.ID, lure *game.ChannelLure, ttl time.Duration) (bool, error)
+	GetChannelLure(channelID snowflake.ID) (*game.ChannelLure, error)
+	DeleteChannelLure(channelID snowflake.ID) error
+	TakeChannelLure(channelID snowflake.ID, lureID uuid.UUID) (*game.ChannelLure, error)
+
+	SetPendingRelease(release *game.PendingRelease, ttl time.Duration) error
+	TakePendingRelease(releaseID uuid.UUID, userID snowflake.ID) (*game.PendingRelease, error)
+
+	SetSpawnCooldown(channelID snowflake.ID, cooldown time.Duration) error
+	IsSpawnOnCooldown(channelID snowflake.ID) bool
+
+	AddPendingXP(gain game.XPGain) error
+	TakePendingXP() ([]game.XPGain, error)
+	StartXPCooldown(userID snowflake.ID, cooldown time.Duration) (bool, error)
+
+	IncrementHintLevel(characterID uuid.UUID) (int, error)
+	SetHintCooldown(userID snowflake.ID, cooldown time.Duration) error
+	IsHintOnCooldown(userID snowflake.ID) bool
+
+	SetSpawnEvents(events []game.SpawnEvent, ttl time.Duration) error
+	GetSpawnEvents() ([]game.SpawnEvent, error)
+	DeleteSpawnEvents() error
+
+	SetGuildSettings(settings *game.GuildSettings, ttl time.Duration) error
+	GetGuildSettings(guildID snowflake.ID) (*game.GuildSettings, error)
+	DeleteGuildSettings(guildID snowflake.ID) error
 }
@@ -0,0 +1,139 @@
+package memstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+const leaderboardBuiltKey = "leaderboard:built" // Set once the leaderboards have been filled from the database
+
+// Leaderboard keeps player rankings in Redis sorted sets, one per category
+// globally and one per category and guild.
+type Leaderboard struct {
+	client *redis.Client
+}
+
+func NewLeaderboard(client *redis.Client) *Leaderboard {
+	return &Leaderboard{client: client}
+}
+
+// leaderboardKey returns the sorted set of a category, guildID 0 is the global leaderboard.
+func leaderboardKey(category game.LeaderboardCategory, guildID snowflake.ID) string {
+	if guildID == 0 {
+		return fmt.Sprintf("leaderboard:%s", category)
+	}
+	return fmt.Sprintf("leaderboard:%s:guild:%s", category, guildID)
+}
+
+// userGuildsKey is the set of guilds a user appears on the leaderboards of.
+func userGuildsKey(userID snowflake.ID) string {
+	return fmt.Sprintf("leaderboard:user:%s:guilds", userID)
+}
+
+// AddGuildMember lists a user on a guild's leaderboards from their next update,
+// reporting whether they were not listed yet.
+func (l *Leaderboard) AddGuildMember(ctx context.Context, guildID, userID snowflake.ID) (bool, error) {
+	added, err := l.client.SAdd(ctx, userGuildsKey(userID), guildID.String()).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to add guild member: %w", err)
+	}
+	return added > 0, nil
+}
+
+// Update stores a user's scores on the global leaderboards and those of every
+// guild they play in. Categories missing from scores are removed.
+func (l *Leaderboard) Update(ctx context.Context, userID snowflake.ID, scores map[game.LeaderboardCategory]float64) error {
+	guilds, err := l.client.SMembers(ctx, userGuildsKey(userID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read user guilds: %w", err)
+	}
+
+	guildIDs := []snowflake.ID{0}
+	for _, guild := range guilds {
+		if guildID, err := snowflake.Parse(guild); err == nil {
+			guildIDs = append(guildIDs, guildID)
+		}
+	}
+
+	member := userID.String()
+	pipe := l.client.Pipeline()
+	for _, category := range game.LeaderboardCategories {
+		score, ok := scores[category]
+		for _, guildID := range guildIDs {
+			if ok {
+				pipe.ZAdd(ctx, leaderboardKey(category, guildID), redis.Z{Score: score, Member: member})
+			} else {
+				pipe.ZRem(ctx, leaderboardKey(category, guildID), member)
+			}
+		}
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to update leaderboards: %w", err)
+	}
+	return nil
+}
+
+// Top returns the highest ranked players of a leaderboard.
+func (l *Leaderboard) Top(ctx context.Context, category game.LeaderboardCategory, guildID snowflake.ID, limit int) ([]game.LeaderboardEntry, error) {
+	players, err := l.client.ZRevRangeWithScores(ctx, leaderboardKey(category, guildID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read leaderboard: %w", err)
+	}
+
+	entries := make([]game.LeaderboardEntry, 0, len(players))
+	for i, player := range players {
+		member, ok := player.Member.(string)
+		if !ok {
+			continue
+		}
+
+		userID, err := snowflake.Parse(member)
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, game.LeaderboardEntry{
+			Rank:   i + 1,
+			UserID: userID,
+			Score:  player.Score,
+		})
+	}
+
+	return entries, nil
+}
+
+// Rank returns a player's position on a leaderboard, or nil if they are not on it.
+func (l *Leaderboard) Rank(ctx context.Context, category game.LeaderboardCategory, guildID, userID snowflake.ID) (*game.LeaderboardEntry, error) {
+	key := leaderboardKey(category, guildID)
+
+	rank, err := l.client.ZRevRank(ctx, key, userID.String()).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read leaderboard rank: %w", err)
+	}
+
+	score, err := l.client.ZScore(ctx, key, userID.String()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read leaderboard score: %w", err)
+	}
+
+	return &game.LeaderboardEntry{Rank: int(rank) + 1, UserID: userID, Score: score}, nil
+}
+
+// Built reports whether the leaderboards have been filled from the database.
+func (l *Leaderboard) Built(ctx context.Context) (bool, error) {
+	exists, err := l.client.Exists(ctx, leaderboardBuiltKey).Result()
+	return exists > 0, err
+}
+
+func (l *Leaderboard) MarkBuilt(ctx context.Context) error {
+	return l.client.Set(ctx, leaderboardBuiltKey, 1, 0).Err()
+}
@@ -0,0 +1,123 @@
+package memstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/theoreotm/friemon/internal/core/game"
+)
+
+const (
+	rankedQueueKey       = "matchmaking:ranked"        // Sorted set of player IDs scored by rating
+	rankedQueueJoinedKey = "matchmaking:ranked:joined" // Hash of player ID to join time in unix milliseconds
+)
+
+var ErrAlreadyQueued = errors.New("player is already in the queue")
+
+// RankedQueue is the Redis backed ranked matchmaking queue.
+type RankedQueue struct {
+	client *redis.Client
+}
+
+func NewRankedQueue(client *redis.Client) *RankedQueue {
+	return &RankedQueue{client: client}
+}
+
+// Join adds a player to the queue. The entry's join time is kept so players
+// put back in the queue do not lose their place.
+func (q *RankedQueue) Join(ctx context.Context, entry game.QueueEntry) error {
+	member := entry.PlayerID.String()
+
+	added, err := q.client.ZAddNX(ctx, rankedQueueKey, redis.Z{Score: float64(entry.Rating), Member: member}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to join queue: %w", err)
+	}
+	if added == 0 {
+		return ErrAlreadyQueued
+	}
+
+	if err := q.client.HSet(ctx, rankedQueueJoinedKey, member, entry.JoinedAt.UnixMilli()).Err(); err != nil {
+		return fmt.Errorf("failed to record queue time: %w", err)
+	}
+
+	return nil
+}
+
+// Leave removes a player from the queue, reporting whether they were queued.
+func (q *RankedQueue) Leave(ctx context.Context, playerID snowflake.ID) (bool, error) {
+	removed, err := q.Remove(ctx, playerID)
+	return removed > 0, err
+}
+
+// Remove removes players from the queue and returns how many were queued.
+func (q *RankedQueue) Remove(ctx context.Context, playerIDs ...snowflake.ID) (int64, error) {
+	if len(playerIDs) == 0 {
+		return 0, nil
+	}
+
+	members := make([]string, 0, len(playerIDs))
+	for _, id := range playerIDs {
+		members = append(members, id.String())
+	}
+
+	pipe := q.client.TxPipeline()
+	removed := pipe.ZRem(ctx, rankedQueueKey, toInterfaces(members)...)
+	pipe.HDel(ctx, rankedQueueJoinedKey, members...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to leave queue: %w", err)
+	}
+
+	return removed.Val(), nil
+}
+
+// Entries returns everyone waiting in the queue.
+func (q *RankedQueue) Entries(ctx context.Context) ([]game.QueueEntry, error) {
+	players, err := q.client.ZRangeWithScores(ctx, rankedQueueKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue: %w", err)
+	}
+
+	joined, err := q.client.HGetAll(ctx, rankedQueueJoinedKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue times: %w", err)
+	}
+
+	entries := make([]game.QueueEntry, 0, len(players))
+	for _, player := range players {
+		member, ok := player.Member.(string)
+		if !ok {
+			continue
+		}
+
+		playerID, err := snowflake.Parse(member)
+		if err != nil {
+			continue
+		}
+
+		joinedAt := time.Now()
+		if millis, err := strconv.ParseInt(joined[member], 10, 64); err == nil {
+			joinedAt = time.UnixMilli(millis)
+		}
+
+		entries = append(entries, game.QueueEntry{
+			PlayerID: playerID,
+			Rating:   int(player.Score),
+			JoinedAt: joinedAt,
+		})
+	}
+
+	return entries, nil
+}
+
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
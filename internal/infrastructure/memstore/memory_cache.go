@@ -2,10 +2,12 @@ package memstore
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
 	"github.com/theoreotm/friemon/internal/core/game"
 )
 
@@ -44,12 +46,90 @@ func (c *memoryCache) GetInteractionCount(channelId snowflake.ID) int {
 
 func (c *memoryCache) ResetInteractionCount(channelId snowflake.ID) error {
 	key := "channel:" + channelId.String() + ":interactions"
+	_ = c.Delete("channel:" + channelId.String() + ":contributors")
 	return c.Set(key, 0, 3*time.Minute)
 }
 
-func (c *memoryCache) SetChannelCharacter(channelID snowflake.ID, character *game.Character) error {
+func (c *memoryCache) IncrementSpawnContribution(channelID, userID snowflake.ID) (int, int, error) {
+	key := "channel:" + channelID.String() + ":contributors"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	contributions := map[snowflake.ID]int{}
+	if item, exists := c.data[key]; exists && time.Now().Before(item.expiresAt) {
+		contributions = item.value.(map[snowflake.ID]int)
+	}
+	contributions[userID]++
+	c.data[key] = cacheItem{
+		value:     contributions,
+		expiresAt: time.Now().Add(3 * time.Minute),
+	}
+	return contributions[userID], len(contributions), nil
+}
+
+func (c *memoryCache) IncrementMessageRate(guildID, userID snowflake.ID, window time.Duration) (int, error) {
+	key := "guild:" + guildID.String() + ":user:" + userID.String() + ":message_rate"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, exists := c.data[key]
+	if !exists || time.Now().After(item.expiresAt) {
+		item = cacheItem{value: 0, expiresAt: time.Now().Add(window)}
+	}
+	item.value = item.value.(int) + 1
+	c.data[key] = item
+	return item.value.(int), nil
+}
+
+func (c *memoryCache) SwapLastMessage(userID snowflake.ID, fingerprint string, ttl time.Duration) (string, error) {
+	key := "user:" + userID.String() + ":last_message"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous := ""
+	if item, exists := c.data[key]; exists && time.Now().Before(item.expiresAt) {
+		previous = item.value.(string)
+	}
+	c.data[key] = cacheItem{
+		value:     fingerprint,
+		expiresAt: time.Now().Add(ttl),
+	}
+	return previous, nil
+}
+
+func (c *memoryCache) MarkSpamReported(guildID, userID snowflake.ID, reason game.SpamReason, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("guild:%s:user:%s:spam:%d", guildID, userID, reason)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if item, exists := c.data[key]; exists && time.Now().Before(item.expiresAt) {
+		return false, nil
+	}
+	c.data[key] = cacheItem{
+		value:     true,
+		expiresAt: time.Now().Add(ttl),
+	}
+	return true, nil
+}
+
+func (c *memoryCache) SetChannelCharacter(channelID snowflake.ID, character *game.Character) (bool, error) {
 	key := "channel:" + channelID.String() + ":character"
-	return c.Set(key, character, 3*time.Minute)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if item, exists := c.data[key]; exists && time.Now().Before(item.expiresAt) {
+		return false, nil
+	}
+	c.data[key] = cacheItem{
+		value:     character,
+		expiresAt: time.Now().Add(spawnCacheTTL),
+	}
+	return true, nil
 }
 
 func (c *memoryCache) GetChannelCharacter(channelID snowflake.ID) (*game.Character, error) {
@@ -67,6 +147,242 @@ func (c *memoryCache) DeleteChannelCharacter(channelID snowflake.ID) error {
 	return c.Delete(key)
 }
 
+func (c *memoryCache) TakeChannelCharacter(channelID snowflake.ID, spawnID uuid.UUID) (*game.Character, error) {
+	key := "channel:" + channelID.String() + ":character"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, exists := c.data[key]
+	if !exists || time.Now().After(item.expiresAt) {
+		return nil, nil
+	}
+	character := item.value.(*game.Character)
+	if character.ID != spawnID {
+		return nil, nil
+	}
+
+	delete(c.data, key)
+	return character, nil
+}
+
+func (c *memoryCache) SetSpawnMessage(spawnID uuid.UUID, messageID snowflake.ID) error {
+	key := "spawn:" + spawnID.String() + ":message"
+	return c.Set(key, messageID, spawnCacheTTL)
+}
+
+func (c *memoryCache) GetSpawnMessage(spawnID uuid.UUID) (snowflake.ID, error) {
+	key := "spawn:" + spawnID.String() + ":message"
+	value, err := c.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	return value.(snowflake.ID), nil
+}
+
+func (c *memoryCache) SetChannelLure(channelID snowflake.ID, lure *game.ChannelLure, ttl time.Duration) (bool, error) {
+	key := "channel:" + channelID.String() + ":lure"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if item, exists := c.data[key]; exists && time.Now().Before(item.expiresAt) {
+		return false, nil
+	}
+	c.data[key] = cacheItem{
+		value:     lure,
+		expiresAt: time.Now().Add(ttl),
+	}
+	return true, nil
+}
+
+func (c *memoryCache) GetChannelLure(channelID snowflake.ID) (*game.ChannelLure, error) {
+	key := "channel:" + channelID.String() + ":lure"
+	value, err := c.Get(key)
+	if err != nil {
+		return nil, nil
+	}
+	return value.(*game.ChannelLure), nil
+}
+
+func (c *memoryCache) DeleteChannelLure(channelID snowflake.ID) error {
+	key := "channel:" + channelID.String() + ":lure"
+	return c.Delete(key)
+}
+
+func (c *memoryCache) SetPendingRelease(release *game.PendingRelease, ttl time.Duration) error {
+	key := "release:" + release.ID.String()
+	return c.Set(key, release, ttl)
+}
+
+func (c *memoryCache) TakePendingRelease(releaseID uuid.UUID, userID snowflake.ID) (*game.PendingRelease, error) {
+	key := "release:" + releaseID.String()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, exists := c.data[key]
+	if !exists || time.Now().After(item.expiresAt) {
+		return nil, nil
+	}
+	release := item.value.(*game.PendingRelease)
+	if release.UserID != userID {
+		return nil, nil
+	}
+
+	delete(c.data, key)
+	return release, nil
+}
+
+func (c *memoryCache) TakeChannelLure(channelID snowflake.ID, lureID uuid.UUID) (*game.ChannelLure, error) {
+	key := "channel:" + channelID.String() + ":lure"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, exists := c.data[key]
+	if !exists || time.Now().After(item.expiresAt) {
+		return nil, nil
+	}
+	lure := item.value.(*game.ChannelLure)
+	if lure.ID != lureID {
+		return nil, nil
+	}
+
+	delete(c.data, key)
+	return lure, nil
+}
+
+func (c *memoryCache) SetSpawnCooldown(channelID snowflake.ID, cooldown time.Duration) error {
+	key := "channel:" + channelID.String() + ":spawn_cooldown"
+	return c.Set(key, true, cooldown)
+}
+
+func (c *memoryCache) IsSpawnOnCooldown(channelID snowflake.ID) bool {
+	key := "channel:" + channelID.String() + ":spawn_cooldown"
+	_, err := c.Get(key)
+	return err == nil
+}
+
+func (c *memoryCache) AddPendingXP(gain game.XPGain) error {
+	key := "xp:pending"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending := map[uuid.UUID]game.XPGain{}
+	if item, exists := c.data[key]; exists && time.Now().Before(item.expiresAt) {
+		pending = item.value.(map[uuid.UUID]game.XPGain)
+	}
+	gain.XP += pending[gain.CharacterID].XP
+	pending[gain.CharacterID] = gain
+	c.data[key] = cacheItem{
+		value:     pending,
+		expiresAt: time.Now().Add(24 * time.Hour),
+	}
+	return nil
+}
+
+func (c *memoryCache) TakePendingXP() ([]game.XPGain, error) {
+	key := "xp:pending"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, exists := c.data[key]
+	if !exists || time.Now().After(item.expiresAt) {
+		return nil, nil
+	}
+	delete(c.data, key)
+
+	pending := item.value.(map[uuid.UUID]game.XPGain)
+	gains := make([]game.XPGain, 0, len(pending))
+	for _, gain := range pending {
+		gains = append(gains, gain)
+	}
+	return gains, nil
+}
+
+func (c *memoryCache) StartXPCooldown(userID snowflake.ID, cooldown time.Duration) (bool, error) {
+	key := "user:" + userID.String() + ":xp_cooldown"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if item, exists := c.data[key]; exists && time.Now().Before(item.expiresAt) {
+		return false, nil
+	}
+	c.data[key] = cacheItem{
+		value:     true,
+		expiresAt: time.Now().Add(cooldown),
+	}
+	return true, nil
+}
+
+func (c *memoryCache) IncrementHintLevel(characterID uuid.UUID) (int, error) {
+	key := "character:" + characterID.String() + ":hints"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	level := 1
+	if item, exists := c.data[key]; exists && time.Now().Before(item.expiresAt) {
+		level = item.value.(int) + 1
+	}
+	c.data[key] = cacheItem{
+		value:     level,
+		expiresAt: time.Now().Add(3 * time.Minute),
+	}
+	return level, nil
+}
+
+func (c *memoryCache) SetHintCooldown(userID snowflake.ID, cooldown time.Duration) error {
+	key := "user:" + userID.String() + ":hint_cooldown"
+	return c.Set(key, true, cooldown)
+}
+
+func (c *memoryCache) IsHintOnCooldown(userID snowflake.ID) bool {
+	key := "user:" + userID.String() + ":hint_cooldown"
+	_, err := c.Get(key)
+	return err == nil
+}
+
+func (c *memoryCache) SetSpawnEvents(events []game.SpawnEvent, ttl time.Duration) error {
+	return c.Set("spawn_events", events, ttl)
+}
+
+func (c *memoryCache) GetSpawnEvents() ([]game.SpawnEvent, error) {
+	value, err := c.Get("spawn_events")
+	if err != nil {
+		return nil, err
+	}
+	return value.([]game.SpawnEvent), nil
+}
+
+func (c *memoryCache) DeleteSpawnEvents() error {
+	return c.Delete("spawn_events")
+}
+
+func (c *memoryCache) SetGuildSettings(settings *game.GuildSettings, ttl time.Duration) error {
+	key := "guild:" + settings.GuildID.String() + ":settings"
+	return c.Set(key, settings, ttl)
+}
+
+func (c *memoryCache) GetGuildSettings(guildID snowflake.ID) (*game.GuildSettings, error) {
+	key := "guild:" + guildID.String() + ":settings"
+	value, err := c.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*game.GuildSettings), nil
+}
+
+func (c *memoryCache) DeleteGuildSettings(guildID snowflake.ID) error {
+	key := "guild:" + guildID.String() + ":settings"
+	return c.Delete(key)
+}
+
 func (c *memoryCache) Set(key string, value interface{}, ttl time.Duration) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
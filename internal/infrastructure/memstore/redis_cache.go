@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/theoreotm/friemon/internal/core/game"
 )
@@ -46,11 +47,82 @@ func channelInteractionKey(channelID snowflake.ID) string {
 	return fmt.Sprintf("channel:%s:interactions", channelID.String())
 }
 
+// Helper function to generate a standardized key for how many messages each
+// user sent toward a channel's next spawn.
+func channelContributorsKey(channelID snowflake.ID) string {
+	return fmt.Sprintf("channel:%s:contributors", channelID.String())
+}
+
+// Helper function to generate a standardized key for a user's message rate in a guild.
+func userMessageRateKey(guildID, userID snowflake.ID) string {
+	return fmt.Sprintf("guild:%s:user:%s:message_rate", guildID.String(), userID.String())
+}
+
+// Helper function to generate a standardized key for a user's last message.
+func userLastMessageKey(userID snowflake.ID) string {
+	return fmt.Sprintf("user:%s:last_message", userID.String())
+}
+
+// Helper function to generate a standardized key for a reported spam violation.
+func spamReportedKey(guildID, userID snowflake.ID, reason game.SpamReason) string {
+	return fmt.Sprintf("guild:%s:user:%s:spam:%d", guildID.String(), userID.String(), reason)
+}
+
 // Helper function to generate a standardized key for channel characters.
 func channelCharacterKey(channelID snowflake.ID) string {
 	return fmt.Sprintf("channel:%s:character", channelID.String())
 }
 
+// Helper function to generate a standardized key for channel lures.
+func channelLureKey(channelID snowflake.ID) string {
+	return fmt.Sprintf("channel:%s:lure", channelID.String())
+}
+
+// Helper function to generate a standardized key for a release waiting for confirmation.
+func pendingReleaseKey(releaseID uuid.UUID) string {
+	return fmt.Sprintf("release:%s", releaseID.String())
+}
+
+// Helper function to generate a standardized key for channel spawn cooldowns.
+func channelSpawnCooldownKey(channelID snowflake.ID) string {
+	return fmt.Sprintf("channel:%s:spawn_cooldown", channelID.String())
+}
+
+// Helper function to generate a standardized key for the message showing a spawn.
+func spawnMessageKey(spawnID uuid.UUID) string {
+	return fmt.Sprintf("spawn:%s:message", spawnID.String())
+}
+
+// Keys of the XP waiting to be saved, by character ID, and where each
+// character's XP was earned.
+const (
+	pendingXPKey        = "xp:pending"
+	pendingXPTargetsKey = "xp:pending:targets"
+)
+
+// Helper function to generate a standardized key for user XP cooldowns.
+func userXPCooldownKey(userID snowflake.ID) string {
+	return fmt.Sprintf("user:%s:xp_cooldown", userID.String())
+}
+
+// Helper function to generate a standardized key for a spawned character's hints.
+func characterHintKey(characterID uuid.UUID) string {
+	return fmt.Sprintf("character:%s:hints", characterID.String())
+}
+
+// Helper function to generate a standardized key for user hint cooldowns.
+func userHintCooldownKey(userID snowflake.ID) string {
+	return fmt.Sprintf("user:%s:hint_cooldown", userID.String())
+}
+
+// Key of the spawn events that have not ended.
+const spawnEventsKey = "spawn_events"
+
+// Helper function to generate a standardized key for guild settings.
+func guildSettingsKey(guildID snowflake.ID) string {
+	return fmt.Sprintf("guild:%s:settings", guildID.String())
+}
+
 // Set stores a value in Redis with a given key and TTL (time-to-live).
 // Complex types are marshalled to JSON. Integers are stored as strings.
 func (c *RedisCache) Set(key string, value interface{}, ttl time.Duration) error {
@@ -152,15 +224,67 @@ func (c *RedisCache) GetInteractionCount(channelID snowflake.ID) int {
 // The counter expires after 3 minutes.
 func (c *RedisCache) ResetInteractionCount(channelID snowflake.ID) error {
 	key := channelInteractionKey(channelID)
+	if err := c.client.Del(c.ctx, channelContributorsKey(channelID)).Err(); err != nil {
+		return err
+	}
 	// Set to 0 with a 3-minute TTL
 	return c.Set(key, 0, 3*time.Minute)
 }
 
-// SetChannelCharacter stores a character associated with a channel.
-// The character data is marshalled to JSON and expires after 3 minutes.
-func (c *RedisCache) SetChannelCharacter(channelID snowflake.ID, character *game.Character) error {
-	key := channelCharacterKey(channelID)
-	return c.Set(key, character, 3*time.Minute)
+// IncrementSpawnContribution counts a user's message toward a channel's next
+// spawn and returns how many they have sent, and how many users sent any. The
+// counts reset with the interaction counter.
+func (c *RedisCache) IncrementSpawnContribution(channelID, userID snowflake.ID) (int, int, error) {
+	key := channelContributorsKey(channelID)
+	pipe := c.client.TxPipeline()
+	incr := pipe.HIncrBy(c.ctx, key, userID.String(), 1)
+	contributors := pipe.HLen(c.ctx, key)
+	pipe.Expire(c.ctx, key, 3*time.Minute)
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return 0, 0, err
+	}
+	return int(incr.Val()), int(contributors.Val()), nil
+}
+
+// IncrementMessageRate counts a user's message in a guild and returns how many
+// they have sent in the current window. The window starts with the first
+// message and is set in the same transaction, so the count always expires.
+func (c *RedisCache) IncrementMessageRate(guildID, userID snowflake.ID, window time.Duration) (int, error) {
+	key := userMessageRateKey(guildID, userID)
+	pipe := c.client.TxPipeline()
+	pipe.SetNX(c.ctx, key, 0, window)
+	incr := pipe.Incr(c.ctx, key)
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
+
+// SwapLastMessage records a user's latest message and returns the one before
+// it, or an empty string if it has expired.
+func (c *RedisCache) SwapLastMessage(userID snowflake.ID, fingerprint string, ttl time.Duration) (string, error) {
+	previous, err := c.client.SetArgs(c.ctx, userLastMessageKey(userID), fingerprint, redis.SetArgs{TTL: ttl, Get: true}).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return previous, err
+}
+
+// MarkSpamReported records that a violation was reported, and reports false if
+// the same one already was within the TTL.
+func (c *RedisCache) MarkSpamReported(guildID, userID snowflake.ID, reason game.SpamReason, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(c.ctx, spamReportedKey(guildID, userID, reason), 1, ttl).Result()
+}
+
+// SetChannelCharacter stores a character associated with a channel unless one
+// is already there, and reports whether it was stored. The character data is
+// marshalled to JSON and expires shortly after the spawn does.
+func (c *RedisCache) SetChannelCharacter(channelID snowflake.ID, character *game.Character) (bool, error) {
+	data, err := json.Marshal(character)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal character to JSON: %w", err)
+	}
+	return c.client.SetNX(c.ctx, channelCharacterKey(channelID), data, spawnCacheTTL).Result()
 }
 
 // GetChannelCharacter retrieves a character associated with a channel.
@@ -188,6 +312,328 @@ func (c *RedisCache) DeleteChannelCharacter(channelID snowflake.ID) error {
 	return c.Delete(key)
 }
 
+// takeCharacterScript deletes a channel's spawned character only if it is
+// still the spawn being claimed, returning it to the single caller that won.
+var takeCharacterScript = redis.NewScript(`
+local value = redis.call("GET", KEYS[1])
+if not value then
+	return false
+end
+if cjson.decode(value).ID ~= ARGV[1] then
+	return false
+end
+redis.call("DEL", KEYS[1])
+return value
+`)
+
+// TakeChannelCharacter atomically removes the character spawned in a channel
+// and returns it, as long as it is the given spawn. Exactly one caller across
+// every bot process gets the character; the rest get nil.
+func (c *RedisCache) TakeChannelCharacter(channelID snowflake.ID, spawnID uuid.UUID) (*game.Character, error) {
+	val, err := takeCharacterScript.Run(c.ctx, c.client, []string{channelCharacterKey(channelID)}, spawnID.String()).Text()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to take character from Redis for channel %s: %w", channelID, err)
+	}
+
+	var character game.Character
+	if err := json.Unmarshal([]byte(val), &character); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal character JSON for channel %s: %w", channelID, err)
+	}
+	return &character, nil
+}
+
+// SetSpawnMessage records the message showing a spawn.
+func (c *RedisCache) SetSpawnMessage(spawnID uuid.UUID, messageID snowflake.ID) error {
+	return c.client.Set(c.ctx, spawnMessageKey(spawnID), messageID.String(), spawnCacheTTL).Err()
+}
+
+// GetSpawnMessage retrieves the message showing a spawn.
+func (c *RedisCache) GetSpawnMessage(spawnID uuid.UUID) (snowflake.ID, error) {
+	val, err := c.client.Get(c.ctx, spawnMessageKey(spawnID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, errors.New("spawn message not found in cache or expired")
+		}
+		return 0, fmt.Errorf("failed to get message from Redis for spawn %s: %w", spawnID, err)
+	}
+	return snowflake.Parse(val)
+}
+
+// SetChannelLure places a lure in a channel unless one is already there, and
+// reports whether it was placed.
+func (c *RedisCache) SetChannelLure(channelID snowflake.ID, lure *game.ChannelLure, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(lure)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal lure: %w", err)
+	}
+	return c.client.SetNX(c.ctx, channelLureKey(channelID), data, ttl).Result()
+}
+
+// GetChannelLure retrieves the lure placed in a channel, or nil if there is none.
+func (c *RedisCache) GetChannelLure(channelID snowflake.ID) (*game.ChannelLure, error) {
+	val, err := c.client.Get(c.ctx, channelLureKey(channelID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get lure from Redis for channel %s: %w", channelID, err)
+	}
+
+	var lure game.ChannelLure
+	if err := json.Unmarshal(val, &lure); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal lure JSON for channel %s: %w", channelID, err)
+	}
+	return &lure, nil
+}
+
+// DeleteChannelLure removes the lure placed in a channel.
+func (c *RedisCache) DeleteChannelLure(channelID snowflake.ID) error {
+	return c.Delete(channelLureKey(channelID))
+}
+
+// SetPendingRelease holds a release until it is confirmed or the TTL runs out.
+func (c *RedisCache) SetPendingRelease(release *game.PendingRelease, ttl time.Duration) error {
+	return c.Set(pendingReleaseKey(release.ID), release, ttl)
+}
+
+// takeReleaseScript deletes a pending release only if it belongs to the user
+// confirming it, returning it to the single caller that won.
+var takeReleaseScript = redis.NewScript(`
+local value = redis.call("GET", KEYS[1])
+if not value then
+	return false
+end
+if cjson.decode(value).UserID ~= ARGV[1] then
+	return false
+end
+redis.call("DEL", KEYS[1])
+return value
+`)
+
+// TakePendingRelease atomically removes a user's pending release and returns
+// it, or nil if it does not exist, belongs to someone else or has expired.
+func (c *RedisCache) TakePendingRelease(releaseID uuid.UUID, userID snowflake.ID) (*game.PendingRelease, error) {
+	val, err := takeReleaseScript.Run(c.ctx, c.client, []string{pendingReleaseKey(releaseID)}, userID.String()).Text()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to take release %s from Redis: %w", releaseID, err)
+	}
+
+	var release game.PendingRelease
+	if err := json.Unmarshal([]byte(val), &release); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal release JSON for %s: %w", releaseID, err)
+	}
+	return &release, nil
+}
+
+// takeLureScript deletes a channel's lure only if it is still the given one,
+// returning it to the single caller that won.
+var takeLureScript = redis.NewScript(`
+local value = redis.call("GET", KEYS[1])
+if not value then
+	return false
+end
+if cjson.decode(value).ID ~= ARGV[1] then
+	return false
+end
+redis.call("DEL", KEYS[1])
+return value
+`)
+
+// TakeChannelLure atomically removes the lure placed in a channel and returns
+// it, as long as it is the given lure. Whoever uses or refunds a lure takes it
+// first, so it is only ever one of the two.
+func (c *RedisCache) TakeChannelLure(channelID snowflake.ID, lureID uuid.UUID) (*game.ChannelLure, error) {
+	val, err := takeLureScript.Run(c.ctx, c.client, []string{channelLureKey(channelID)}, lureID.String()).Text()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to take lure from Redis for channel %s: %w", channelID, err)
+	}
+
+	var lure game.ChannelLure
+	if err := json.Unmarshal([]byte(val), &lure); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal lure JSON for channel %s: %w", channelID, err)
+	}
+	return &lure, nil
+}
+
+// SetSpawnCooldown stops characters spawning in a channel for the cooldown.
+func (c *RedisCache) SetSpawnCooldown(channelID snowflake.ID, cooldown time.Duration) error {
+	return c.client.Set(c.ctx, channelSpawnCooldownKey(channelID), 1, cooldown).Err()
+}
+
+// IsSpawnOnCooldown reports whether a channel's spawn cooldown is running.
+func (c *RedisCache) IsSpawnOnCooldown(channelID snowflake.ID) bool {
+	exists, err := c.client.Exists(c.ctx, channelSpawnCooldownKey(channelID)).Result()
+	return err == nil && exists > 0
+}
+
+// AddPendingXP adds XP to a character's pending XP. The latest gain decides
+// where a level-up is announced.
+func (c *RedisCache) AddPendingXP(gain game.XPGain) error {
+	target := gain
+	target.XP = 0
+	data, err := json.Marshal(target)
+	if err != nil {
+		return fmt.Errorf("failed to marshal XP gain: %w", err)
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.HIncrBy(c.ctx, pendingXPKey, gain.CharacterID.String(), int64(gain.XP))
+	pipe.HSet(c.ctx, pendingXPTargetsKey, gain.CharacterID.String(), data)
+	_, err = pipe.Exec(c.ctx)
+	return err
+}
+
+// takePendingXPScript empties the pending XP and returns it, so XP added while
+// a flush runs waits for the next one instead of being lost.
+var takePendingXPScript = redis.NewScript(`
+local amounts = redis.call("HGETALL", KEYS[1])
+local targets = redis.call("HGETALL", KEYS[2])
+redis.call("DEL", KEYS[1], KEYS[2])
+return {amounts, targets}
+`)
+
+// TakePendingXP removes all pending XP and returns it, one gain per character.
+func (c *RedisCache) TakePendingXP() ([]game.XPGain, error) {
+	result, err := takePendingXPScript.Run(c.ctx, c.client, []string{pendingXPKey, pendingXPTargetsKey}).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take pending XP from Redis: %w", err)
+	}
+	if len(result) != 2 {
+		return nil, fmt.Errorf("unexpected pending XP reply from Redis: %v", result)
+	}
+	amounts, _ := result[0].([]interface{})
+	targets, _ := result[1].([]interface{})
+
+	gains := map[string]game.XPGain{}
+	for i := 0; i+1 < len(targets); i += 2 {
+		id, _ := targets[i].(string)
+		data, _ := targets[i+1].(string)
+		var gain game.XPGain
+		if err := json.Unmarshal([]byte(data), &gain); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal XP gain JSON for character %s: %w", id, err)
+		}
+		gains[id] = gain
+	}
+
+	pending := make([]game.XPGain, 0, len(amounts)/2)
+	for i := 0; i+1 < len(amounts); i += 2 {
+		id, _ := amounts[i].(string)
+		value, _ := amounts[i+1].(string)
+		xp, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pending XP for character %s: %w", id, err)
+		}
+
+		gain, ok := gains[id]
+		if !ok {
+			characterID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid character ID in pending XP: %w", err)
+			}
+			gain.CharacterID = characterID
+		}
+		gain.XP = xp
+		pending = append(pending, gain)
+	}
+	return pending, nil
+}
+
+// StartXPCooldown starts a user's XP cooldown unless it is already running,
+// and reports whether it started.
+func (c *RedisCache) StartXPCooldown(userID snowflake.ID, cooldown time.Duration) (bool, error) {
+	return c.client.SetNX(c.ctx, userXPCooldownKey(userID), 1, cooldown).Result()
+}
+
+// IncrementHintLevel counts another hint for a spawned character and returns
+// how many hints it has had. The count lives as long as a spawn.
+func (c *RedisCache) IncrementHintLevel(characterID uuid.UUID) (int, error) {
+	key := characterHintKey(characterID)
+	pipe := c.client.Pipeline()
+	incr := pipe.Incr(c.ctx, key)
+	pipe.Expire(c.ctx, key, 3*time.Minute)
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
+
+// SetHintCooldown stops a user asking for hints for the cooldown.
+func (c *RedisCache) SetHintCooldown(userID snowflake.ID, cooldown time.Duration) error {
+	return c.client.Set(c.ctx, userHintCooldownKey(userID), 1, cooldown).Err()
+}
+
+// IsHintOnCooldown reports whether a user's hint cooldown is running.
+func (c *RedisCache) IsHintOnCooldown(userID snowflake.ID) bool {
+	exists, err := c.client.Exists(c.ctx, userHintCooldownKey(userID)).Result()
+	return err == nil && exists > 0
+}
+
+// SetSpawnEvents caches the spawn events that have not ended as JSON.
+func (c *RedisCache) SetSpawnEvents(events []game.SpawnEvent, ttl time.Duration) error {
+	if events == nil {
+		events = []game.SpawnEvent{}
+	}
+	return c.Set(spawnEventsKey, events, ttl)
+}
+
+// GetSpawnEvents retrieves the cached spawn events.
+func (c *RedisCache) GetSpawnEvents() ([]game.SpawnEvent, error) {
+	val, err := c.client.Get(c.ctx, spawnEventsKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errors.New("spawn events not found in cache or expired")
+		}
+		return nil, fmt.Errorf("failed to get spawn events from Redis: %w", err)
+	}
+
+	var events []game.SpawnEvent
+	if err := json.Unmarshal(val, &events); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal spawn events JSON: %w", err)
+	}
+	return events, nil
+}
+
+// DeleteSpawnEvents removes the spawn events from the cache.
+func (c *RedisCache) DeleteSpawnEvents() error {
+	return c.Delete(spawnEventsKey)
+}
+
+// SetGuildSettings caches a guild's settings as JSON.
+func (c *RedisCache) SetGuildSettings(settings *game.GuildSettings, ttl time.Duration) error {
+	return c.Set(guildSettingsKey(settings.GuildID), settings, ttl)
+}
+
+// GetGuildSettings retrieves a guild's cached settings.
+func (c *RedisCache) GetGuildSettings(guildID snowflake.ID) (*game.GuildSettings, error) {
+	val, err := c.client.Get(c.ctx, guildSettingsKey(guildID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errors.New("guild settings not found in cache or expired")
+		}
+		return nil, fmt.Errorf("failed to get settings from Redis for guild %s: %w", guildID, err)
+	}
+
+	var settings game.GuildSettings
+	if err := json.Unmarshal(val, &settings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal settings JSON for guild %s: %w", guildID, err)
+	}
+	return &settings, nil
+}
+
+// DeleteGuildSettings removes a guild's settings from the cache.
+func (c *RedisCache) DeleteGuildSettings(guildID snowflake.ID) error {
+	return c.Delete(guildSettingsKey(guildID))
+}
+
 // Close closes the Redis client connection.
 func (c *RedisCache) Close() error {
 	return c.client.Close()
@@ -12,6 +12,10 @@ import (
 	"github.com/theoreotm/friemon/internal/types"
 )
 
+// ErrDuplicateTask is returned when a task is emitted with the ID of a task
+// that is still queued.
+var ErrDuplicateTask = asynq.ErrTaskIDConflict
+
 // AsynqConfig holds the configuration for the Asynq scheduler
 type AsynqConfig struct {
 	RedisAddr     string
@@ -26,6 +30,7 @@ type Scheduler struct {
 	client    *asynq.Client
 	server    *asynq.Server
 	inspector *asynq.Inspector
+	periodic  *asynq.Scheduler
 	mux       *asynq.ServeMux
 	handlers  map[string]types.TaskHandler
 	mu        sync.RWMutex
@@ -44,6 +49,7 @@ func NewAsynqScheduler(config AsynqConfig, logger *slog.Logger) *Scheduler {
 
 	client := asynq.NewClient(redisOpt)
 	inspector := asynq.NewInspector(redisOpt)
+	periodic := asynq.NewScheduler(redisOpt, &asynq.SchedulerOpts{Location: time.UTC})
 
 	serverConfig := asynq.Config{
 		Concurrency: config.Concurrency,
@@ -70,6 +76,7 @@ func NewAsynqScheduler(config AsynqConfig, logger *slog.Logger) *Scheduler {
 		client:    client,
 		server:    server,
 		inspector: inspector,
+		periodic:  periodic,
 		mux:       mux,
 		handlers:  make(map[string]types.TaskHandler),
 		ctx:       ctx,
@@ -86,6 +93,10 @@ func (s *Scheduler) Start() error {
 		}
 	}()
 
+	if err := s.periodic.Start(); err != nil {
+		return fmt.Errorf("failed to start periodic scheduler: %w", err)
+	}
+
 	s.logger.Info("Task scheduler started")
 	return nil
 }
@@ -93,6 +104,7 @@ func (s *Scheduler) Start() error {
 // Stop gracefully shuts down the scheduler
 func (s *Scheduler) Stop() error {
 	s.cancel()
+	s.periodic.Shutdown()
 	s.server.Shutdown()
 	if err := s.client.Close(); err != nil {
 		return fmt.Errorf("failed to close client: %w", err)
@@ -154,6 +166,25 @@ func (s *Scheduler) Now() *TaskBuilder {
 	return s.After(0)
 }
 
+// Every enqueues a task of the given type on a fixed interval. Only one task
+// is enqueued per interval even when several bot instances register it.
+func (s *Scheduler) Every(interval time.Duration, taskType string) error {
+	task := asynq.NewTask(taskType, []byte("{}"))
+
+	entryID, err := s.periodic.Register(fmt.Sprintf("@every %s", interval), task, asynq.Unique(interval))
+	if err != nil {
+		return fmt.Errorf("failed to register periodic task: %w", err)
+	}
+
+	s.logger.Info("Periodic task registered",
+		"task_type", taskType,
+		"entry_id", entryID,
+		"interval", interval,
+	)
+
+	return nil
+}
+
 // Cancel cancels a scheduled task by ID
 func (s *Scheduler) Cancel(queue, taskID string) error {
 	return s.inspector.DeleteTask(queue, taskID)
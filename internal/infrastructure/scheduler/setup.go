@@ -31,7 +31,6 @@ func SetupAsynqScheduler(redisAddr, redisPassword string, redisDB int, logger *s
 func setupTaskHandlers(scheduler *Scheduler, deps tasks.BotDependencies) {
 	spawnHandlers := tasks.NewSpawnTaskHandlers(deps)
 
-	scheduler.On("disable_spawn_button", spawnHandlers.DisableSpawnButton)
+	scheduler.On(tasks.SpawnExpiryTask, spawnHandlers.ExpireSpawn)
 	scheduler.On("cleanup_channel", spawnHandlers.CleanupChannel)
-
 }
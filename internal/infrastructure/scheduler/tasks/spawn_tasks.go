@@ -2,16 +2,32 @@ package tasks
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/rest"
 	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/theoreotm/friemon/internal/core/game"
 	"github.com/theoreotm/friemon/internal/types"
 )
 
+// SpawnExpiryTask ends a spawn once it has been up for its lifetime.
+const SpawnExpiryTask = "expire_spawn"
+
+// SpawnTaskID keys a spawn's expiry by its message, so a claim can cancel it.
+func SpawnTaskID(messageID snowflake.ID) string {
+	return "spawn:" + messageID.String()
+}
+
+// SpawnButtonID is the custom ID of a spawn's claim button.
+func SpawnButtonID(spawnID uuid.UUID) string {
+	return "/claim/" + spawnID.String()
+}
+
 type BotDependencies interface {
 	GetRestClient() RestClient
 	GetCache() CacheClient
@@ -23,6 +39,7 @@ type RestClient interface {
 }
 
 type CacheClient interface {
+	TakeChannelCharacter(channelID snowflake.ID, spawnID uuid.UUID) (*game.Character, error)
 	DeleteChannelCharacter(channelID snowflake.ID) error
 	ResetInteractionCount(channelID snowflake.ID) error
 }
@@ -37,53 +54,76 @@ func NewSpawnTaskHandlers(deps BotDependencies) *SpawnTaskHandlers {
 	}
 }
 
-func (h *SpawnTaskHandlers) DisableSpawnButton(ctx context.Context, data types.TaskData) error {
-	channelIDStr := data.MustString("channel_id")
-	messageIDStr := data.MustString("message_id")
-
-	channelID, err := snowflake.Parse(channelIDStr)
+// ExpireSpawn ends a spawn nobody caught in time. The spawn is taken from the
+// cache first, so a spawn that was claimed meanwhile is left alone. If closing
+// the message fails, the retry finds the spawn gone and closes the message as
+// long as it still shows the spawn.
+func (h *SpawnTaskHandlers) ExpireSpawn(ctx context.Context, data types.TaskData) error {
+	channelID, err := snowflake.Parse(data.MustString("channel_id"))
 	if err != nil {
 		return fmt.Errorf("invalid channel_id: %w", err)
 	}
 
-	messageID, err := snowflake.Parse(messageIDStr)
+	messageID, err := snowflake.Parse(data.MustString("message_id"))
 	if err != nil {
 		return fmt.Errorf("invalid message_id: %w", err)
 	}
 
-	slog.Info("Disabling spawn button",
+	spawnID, err := uuid.Parse(data.MustString("spawn_id"))
+	if err != nil {
+		return fmt.Errorf("invalid spawn_id: %w", err)
+	}
+
+	character, err := h.deps.GetCache().TakeChannelCharacter(channelID, spawnID)
+	if err != nil {
+		return fmt.Errorf("failed to take spawned character: %w", err)
+	}
+
+	name, ok := data.String("character_name")
+	if !ok {
+		name = "character"
+	}
+	if character != nil {
+		name = character.CharacterName()
+	} else if retried, _ := asynq.GetRetryCount(ctx); retried == 0 {
+		slog.Debug("Spawn already claimed", "channel_id", channelID, "spawn_id", spawnID)
+		return nil
+	}
+
+	slog.Info("Expiring spawn",
 		"channel_id", channelID,
 		"message_id", messageID,
+		"spawn_id", spawnID,
 	)
 
-	// Get the current message
 	restClient := h.deps.GetRestClient()
 	message, err := restClient.GetMessage(channelID, messageID)
 	if err != nil {
 		return fmt.Errorf("failed to get message: %w", err)
 	}
 
-	button, exists := message.ButtonByID("/claim")
-	if !exists {
-		return errors.New("failed to find button")
-	}
-
-	if button.Disabled {
+	// A claim renames the spawn, so a message that was claimed is left alone
+	if character == nil && (len(message.Embeds) == 0 || !strings.HasPrefix(message.Embeds[0].Title, "A wild ")) {
+		slog.Debug("Spawn already closed", "channel_id", channelID, "spawn_id", spawnID)
 		return nil
 	}
 
-	_, err = restClient.UpdateMessage(
-		channelID,
-		messageID,
-		discord.NewMessageUpdateBuilder().
-			AddActionRow(button.AsDisabled().WithLabel("Character has wandered away")).
-			Build())
+	update := discord.NewMessageUpdateBuilder()
+	if len(message.Embeds) > 0 {
+		embed := message.Embeds[0]
+		embed.Title = fmt.Sprintf("The wild %s wandered away...", name)
+		embed.Description = "Nobody caught it in time."
+		update.SetEmbeds(embed)
+	}
+	if button, exists := message.ButtonByID(SpawnButtonID(spawnID)); exists {
+		update.AddActionRow(button.AsDisabled().WithLabel("Character has wandered away"))
+	}
 
-	if err != nil {
+	if _, err := restClient.UpdateMessage(channelID, messageID, update.Build()); err != nil {
 		return fmt.Errorf("failed to update message: %w", err)
 	}
 
-	slog.Info("Successfully disabled spawn button",
+	slog.Info("Spawn expired",
 		"channel_id", channelID,
 		"message_id", messageID,
 	)